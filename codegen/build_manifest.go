@@ -0,0 +1,294 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package codegen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/uber/zanzibar/codegen/template_bundle"
+)
+
+// buildManifestFile is the file in the target build directory that records
+// the inputs and outputs of the last build.
+const buildManifestFile = ".build-manifest.json"
+
+var thriftIncludeRegexp = regexp.MustCompile(`(?m)^\s*include\s+"([^"]+)"`)
+
+// InputFileLister is implemented by build generators whose output depends on
+// files outside of the module instance directory, such as thrift files.
+type InputFileLister interface {
+	// InputFiles returns the absolute paths of the extra files read when
+	// generating the module instance.
+	InputFiles(instance *ModuleInstance) []string
+}
+
+// buildManifest records the input hash and generated files of every module
+// instance so unchanged instances can be skipped by the next build.
+type buildManifest struct {
+	Salt    string                     `json:"salt"`
+	Modules map[string]*moduleManifest `json:"modules"`
+}
+
+// moduleManifest records the build of a single module instance.
+type moduleManifest struct {
+	InputHash string `json:"inputHash"`
+	// Outputs maps generated file paths, relative to the target build
+	// directory, to the hash of their contents.
+	Outputs map[string]string `json:"outputs"`
+}
+
+// readBuildManifest reads the manifest of the last build, returning an empty
+// manifest if it is missing, unreadable or was built with a different salt.
+func readBuildManifest(targetGenDir string, salt string) *buildManifest {
+	manifest := &buildManifest{
+		Salt:    salt,
+		Modules: map[string]*moduleManifest{},
+	}
+
+	bytes, err := ioutil.ReadFile(filepath.Join(targetGenDir, buildManifestFile))
+	if err != nil {
+		return manifest
+	}
+
+	var prev buildManifest
+	if err := json.Unmarshal(bytes, &prev); err != nil || prev.Salt != salt {
+		return manifest
+	}
+	if prev.Modules != nil {
+		manifest.Modules = prev.Modules
+	}
+	return manifest
+}
+
+func (manifest *buildManifest) write(targetGenDir string) error {
+	bytes, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "Error serializing build manifest")
+	}
+	return writeFile(filepath.Join(targetGenDir, buildManifestFile), bytes)
+}
+
+// upToDate returns true if the module was built from the given inputs and
+// none of its generated files were changed or removed since.
+func (module *moduleManifest) upToDate(
+	targetGenDir string,
+	inputHash string,
+) bool {
+	if module.InputHash == "" || module.InputHash != inputHash {
+		return false
+	}
+	for filePath, fileHash := range module.Outputs {
+		current, err := hashFile(filepath.Join(targetGenDir, filePath))
+		if err != nil || current != fileHash {
+			return false
+		}
+	}
+	return true
+}
+
+// removeOrphans deletes the files generated by the previous build of a module
+// that are not part of its current outputs, and returns how many were removed.
+func removeOrphans(
+	targetGenDir string,
+	prev *moduleManifest,
+	outputs map[string]string,
+) (int, error) {
+	if prev == nil {
+		return 0, nil
+	}
+
+	removed := 0
+	for filePath := range prev.Outputs {
+		if _, ok := outputs[filePath]; ok {
+			continue
+		}
+		err := os.Remove(filepath.Join(targetGenDir, filePath))
+		if err != nil && !os.IsNotExist(err) {
+			return removed, errors.Wrapf(
+				err, "Error removing orphaned file %q", filePath,
+			)
+		}
+		if err == nil {
+			fmt.Printf("Removed orphaned file %s\n", filePath)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// inputHash returns a hash of everything the module instance is generated
+// from: its directory, the extra files listed by its generator, and the input
+// hashes of its dependencies.
+func (system *ModuleSystem) inputHash(
+	instance *ModuleInstance,
+	hashes map[*ModuleInstance]string,
+) (string, error) {
+	if h, ok := hashes[instance]; ok {
+		return h, nil
+	}
+
+	h := sha256.New()
+	writeHashEntry(h, "salt", system.buildSalt)
+	writeHashEntry(h, "class", instance.ClassName+"/"+instance.ClassType)
+
+	instanceDir := filepath.Join(instance.BaseDirectory, instance.Directory)
+	files, err := ioutil.ReadDir(instanceDir)
+	if err != nil {
+		return "", errors.Wrapf(
+			err, "Error reading module directory %q", instanceDir,
+		)
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		filePath := filepath.Join(instanceDir, file.Name())
+		fileHash, err := hashFile(filePath)
+		if err != nil {
+			return "", err
+		}
+		writeHashEntry(h, file.Name(), fileHash)
+	}
+
+	generator := system.classes[instance.ClassName].types[instance.ClassType]
+	if lister, ok := generator.(InputFileLister); ok {
+		inputFiles := lister.InputFiles(instance)
+		sort.Strings(inputFiles)
+		for _, filePath := range inputFiles {
+			fileHash, err := hashFile(filePath)
+			if err != nil {
+				// Missing inputs are reported by the generator itself
+				fileHash = "missing"
+			}
+			writeHashEntry(h, filePath, fileHash)
+		}
+	}
+
+	classNames := make([]string, 0, len(instance.ResolvedDependencies))
+	for className := range instance.ResolvedDependencies {
+		classNames = append(classNames, className)
+	}
+	sort.Strings(classNames)
+	for _, className := range classNames {
+		for _, dependency := range instance.ResolvedDependencies[className] {
+			depHash, err := system.inputHash(dependency, hashes)
+			if err != nil {
+				return "", err
+			}
+			writeHashEntry(
+				h, className+"/"+dependency.InstanceName, depHash,
+			)
+		}
+	}
+
+	hashes[instance] = hex.EncodeToString(h.Sum(nil))
+	return hashes[instance], nil
+}
+
+func writeHashEntry(h hash.Hash, key string, value string) {
+	_, _ = io.WriteString(h, key+"\x00"+value+"\n")
+}
+
+func hashFile(filePath string) (string, error) {
+	bytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error reading file %q", filePath)
+	}
+	return hashBytes(bytes), nil
+}
+
+func hashBytes(bytes []byte) string {
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:])
+}
+
+// generatorHash returns a hash identifying the code generator, made of the
+// bundled templates, the template overrides and the running executable.
+func generatorHash(templateOverrideDir string) string {
+	h := sha256.New()
+	names := templates.AssetNames()
+	sort.Strings(names)
+	for _, name := range names {
+		content, err := templates.Asset(name)
+		if err != nil {
+			continue
+		}
+		writeHashEntry(h, name, hashBytes(content))
+	}
+
+	overrideFiles, _ := templateOverrideFiles(templateOverrideDir)
+	for _, file := range overrideFiles {
+		if fileHash, err := hashFile(file); err == nil {
+			writeHashEntry(h, "override/"+filepath.Base(file), fileHash)
+		}
+	}
+
+	if executable, err := os.Executable(); err == nil {
+		if executableHash, err := hashFile(executable); err == nil {
+			writeHashEntry(h, "executable", executableHash)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// thriftFileClosure returns the thrift file and every thrift file it
+// transitively includes.
+func thriftFileClosure(thriftFile string) []string {
+	seen := map[string]bool{}
+	queue := []string{filepath.Clean(thriftFile)}
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+		if seen[file] {
+			continue
+		}
+		seen[file] = true
+
+		bytes, err := ioutil.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, match := range thriftIncludeRegexp.FindAllSubmatch(bytes, -1) {
+			queue = append(queue, filepath.Join(
+				filepath.Dir(file), string(match[1]),
+			))
+		}
+	}
+
+	files := make([]string, 0, len(seen))
+	for file := range seen {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package codegen
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// configSchemaTypes are the types a config key can be declared with, as in
+// zanzibar.ConfigSchemaTypes.
+var configSchemaTypes = map[string]bool{
+	"string":  true,
+	"integer": true,
+	"number":  true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+}
+
+// configSchemaFile is the configSchema field of a module instance json file
+type configSchemaFile struct {
+	ConfigSchema map[string]string `json:"configSchema"`
+}
+
+// readConfigSchema returns the config keys declared by the configSchema field
+// of the module instance json file.
+func readConfigSchema(instance *ModuleInstance) (map[string]string, error) {
+	var file configSchemaFile
+	if err := json.Unmarshal(instance.JSONFileRaw, &file); err != nil {
+		return nil, newDiagnostic(
+			instance.configPath(), "configSchema",
+			"must map config keys to types: %s", err,
+		)
+	}
+
+	var diags Diagnostics
+	for _, key := range sortedConfigKeys(file.ConfigSchema) {
+		if !configSchemaTypes[file.ConfigSchema[key]] {
+			diags = append(diags, newDiagnostic(
+				instance.configPath(), "configSchema."+key,
+				"unknown type %q", file.ConfigSchema[key],
+			))
+		}
+	}
+	if err := diags.Err(); err != nil {
+		return nil, err
+	}
+	return file.ConfigSchema, nil
+}
+
+// clientConfigSchema returns the config keys read by a generated client.
+func clientConfigSchema(cspec *ClientSpec) map[string]string {
+	prefix := "clients." + cspec.ClientID + "."
+	switch cspec.ClientType {
+	case "http":
+		return map[string]string{
+			prefix + "ip":   "string",
+			prefix + "port": "integer",
+		}
+	case "tchannel":
+		return map[string]string{
+			prefix + "serviceName":       "string",
+			prefix + "ip":                "string",
+			prefix + "port":              "integer",
+			prefix + "timeout":           "integer",
+			prefix + "timeoutPerAttempt": "integer",
+		}
+	}
+	return nil
+}
+
+// gatewayConfigSchema aggregates the config keys declared by the module
+// instances, and the ones read by the generated clients, into the config
+// schema of the gateway.
+func gatewayConfigSchema(
+	moduleInstances map[string][]*ModuleInstance,
+	clientSpecs map[string]*ClientSpec,
+) (map[string]string, error) {
+	schema := map[string]string{}
+	declaredBy := map[string]string{}
+
+	var diags Diagnostics
+	add := func(file string, keys map[string]string) {
+		for _, key := range sortedConfigKeys(keys) {
+			prev, ok := schema[key]
+			if ok && prev != keys[key] {
+				diags = append(diags, newDiagnostic(
+					file, "configSchema."+key,
+					"declared as %s but as %s by %s",
+					keys[key], prev, declaredBy[key],
+				))
+				continue
+			}
+			if !ok {
+				schema[key] = keys[key]
+				declaredBy[key] = file
+			}
+		}
+	}
+
+	clientIDs := make([]string, 0, len(clientSpecs))
+	for clientID := range clientSpecs {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Strings(clientIDs)
+	for _, clientID := range clientIDs {
+		cspec := clientSpecs[clientID]
+		add(cspec.JSONFile, clientConfigSchema(cspec))
+	}
+
+	classNames := make([]string, 0, len(moduleInstances))
+	for className := range moduleInstances {
+		classNames = append(classNames, className)
+	}
+	sort.Strings(classNames)
+	for _, className := range classNames {
+		for _, instance := range moduleInstances[className] {
+			keys, err := readConfigSchema(instance)
+			if err != nil {
+				diags.Add(instance.configPath(), errors.Wrapf(
+					err, "Cannot read config schema of %s %s",
+					className, instance.InstanceName,
+				))
+				continue
+			}
+			add(instance.configPath(), keys)
+		}
+	}
+
+	if err := diags.Err(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func sortedConfigKeys(schema map[string]string) []string {
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package codegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Diagnostic is a single configuration error found during code generation.
+type Diagnostic struct {
+	// File is the path of the config or thrift file with the error.
+	File string
+	// Location is the dotted JSON path of the value with the error,
+	// e.g. "config.exposedMethods.Call". It is empty if unknown.
+	Location string
+	// Message describes the error.
+	Message string
+}
+
+func newDiagnostic(
+	file string, location string, format string, args ...interface{},
+) *Diagnostic {
+	return &Diagnostic{
+		File:     file,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// Error implements the error interface.
+func (d *Diagnostic) Error() string {
+	if d.Location == "" {
+		return fmt.Sprintf("%s: %s", d.File, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", d.File, d.Location, d.Message)
+}
+
+// Diagnostics aggregates every configuration error found during code
+// generation so they can be reported together.
+type Diagnostics []*Diagnostic
+
+// Error implements the error interface, listing one diagnostic per line.
+func (ds Diagnostics) Error() string {
+	lines := make([]string, len(ds))
+	for i, d := range ds {
+		lines[i] = d.Error()
+	}
+	return fmt.Sprintf(
+		"%d configuration error(s):\n\t%s", len(ds), strings.Join(lines, "\n\t"),
+	)
+}
+
+// Add records err for the given file. Diagnostics carried by err keep
+// their own file and location.
+func (ds *Diagnostics) Add(file string, err error) {
+	switch cause := errors.Cause(err).(type) {
+	case Diagnostics:
+		*ds = append(*ds, cause...)
+	case *Diagnostic:
+		*ds = append(*ds, cause)
+	default:
+		*ds = append(*ds, &Diagnostic{File: file, Message: err.Error()})
+	}
+}
+
+// Err returns the diagnostics as an error, or nil if there are none.
+func (ds Diagnostics) Err() error {
+	if len(ds) == 0 {
+		return nil
+	}
+	return ds
+}
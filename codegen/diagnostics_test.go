@@ -0,0 +1,170 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package codegen_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/zanzibar/codegen"
+)
+
+func TestDiagnosticsAdd(t *testing.T) {
+	var diags codegen.Diagnostics
+	assert.NoError(t, diags.Err())
+
+	diags.Add("a.json", errors.New("plain error"))
+	diags.Add("b.json", errors.Wrap(codegen.Diagnostics{
+		{File: "c.json", Location: "config.thriftFile", Message: "missing"},
+		{File: "d.json", Message: "broken"},
+	}, "wrapped"))
+
+	err := diags.Err()
+	assert.Error(t, err)
+	assert.Equal(t, "3 configuration error(s):\n"+
+		"\ta.json: plain error\n"+
+		"\tc.json: config.thriftFile: missing\n"+
+		"\td.json: broken",
+		err.Error(),
+	)
+}
+
+func TestNewEndpointSpecReportsAllMissingFields(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zanzibar-diagnostics")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		assert.NoError(t, os.RemoveAll(dir))
+	}()
+
+	jsonFile := filepath.Join(dir, "endpoint.json")
+	err = ioutil.WriteFile(jsonFile, []byte(`{
+		"endpointType": "http",
+		"endpointId": "bar",
+		"handleId": "normal",
+		"thriftFileSha": "{{placeholder}}",
+		"workflowType": "httpClient"
+	}`), 0644)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = codegen.NewEndpointSpec(jsonFile, newPackageHelper(t), nil)
+	diags, ok := errors.Cause(err).(codegen.Diagnostics)
+	if !assert.True(t, ok, "expected diagnostics, got %v", err) {
+		return
+	}
+
+	locations := []string{}
+	for _, d := range diags {
+		assert.Equal(t, jsonFile, d.File)
+		locations = append(locations, d.Location)
+	}
+	assert.Equal(t, []string{"thriftFile", "thriftMethodName"}, locations)
+}
+
+func TestNewEndpointSpecReportsNonStringFields(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zanzibar-diagnostics")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		assert.NoError(t, os.RemoveAll(dir))
+	}()
+
+	jsonFile := filepath.Join(dir, "endpoint.json")
+	err = ioutil.WriteFile(jsonFile, []byte(`{
+		"endpointType": "http",
+		"endpointId": 1,
+		"handleId": true,
+		"thriftFile": "clients/bar/bar.thrift",
+		"thriftFileSha": "{{placeholder}}",
+		"thriftMethodName": "Bar::normal",
+		"workflowType": "httpClient"
+	}`), 0644)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = codegen.NewEndpointSpec(jsonFile, newPackageHelper(t), nil)
+	diags, ok := errors.Cause(err).(codegen.Diagnostics)
+	if !assert.True(t, ok, "expected diagnostics, got %v", err) {
+		return
+	}
+
+	locations := []string{}
+	for _, d := range diags {
+		locations = append(locations, d.Location)
+	}
+	assert.Equal(t, []string{"endpointId", "handleId"}, locations)
+}
+
+func TestMiddlewareConfigReportsAllErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zanzibar-diagnostics")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		assert.NoError(t, os.RemoveAll(dir))
+	}()
+
+	err = ioutil.WriteFile(filepath.Join(dir, "middleware-config.json"), []byte(`{
+		"middlewares": [
+			"example",
+			{"name": "a", "schema": 1, "importPath": "a"},
+			{"schema": "b.json", "importPath": true}
+		]
+	}`), 0644)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = codegen.NewPackageHelper(
+		"github.com/uber/zanzibar/examples/example-gateway",
+		dir,
+		"middleware-config.json",
+		dir,
+		"github.com/uber/zanzibar/examples/example-gateway/build/gen-code",
+		filepath.Join(dir, "build"),
+		"",
+		"",
+	)
+	diags, ok := errors.Cause(err).(codegen.Diagnostics)
+	if !assert.True(t, ok, "expected diagnostics, got %v", err) {
+		return
+	}
+
+	locations := []string{}
+	for _, d := range diags {
+		locations = append(locations, d.Location)
+	}
+	assert.Equal(t, []string{
+		"middlewares[0]",
+		"middlewares[1].schema",
+		"middlewares[2].name",
+		"middlewares[2].importPath",
+	}, locations)
+}
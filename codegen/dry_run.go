@@ -0,0 +1,156 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package codegen
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// DryRun collects the files of a build in memory instead of writing them to
+// the target build directory, so that they can be compared with the files
+// on disk.
+type DryRun struct {
+	files   map[string][]byte
+	removed map[string]bool
+}
+
+// NewDryRun returns an empty dry run.
+func NewDryRun() *DryRun {
+	return &DryRun{
+		files:   map[string][]byte{},
+		removed: map[string]bool{},
+	}
+}
+
+// FileDiff is the difference between a generated file and the file on disk.
+type FileDiff struct {
+	// Path is the path of the file in the build directory.
+	Path string
+	// Diff is the unified diff from the file on disk to the generated file.
+	Diff string
+}
+
+// writeFile adds a generated file to the dry run, go files are formatted
+// as they would be on disk.
+func (d *DryRun) writeFile(filePath string, content []byte) error {
+	if filepath.Ext(filePath) == ".go" {
+		var err error
+		content, err = formatGoSource(filePath, content)
+		if err != nil {
+			return err
+		}
+	}
+	d.files[filePath] = content
+	delete(d.removed, filePath)
+	return nil
+}
+
+// removeFile marks a previously generated file as removed.
+func (d *DryRun) removeFile(filePath string) {
+	if _, ok := d.files[filePath]; !ok {
+		d.removed[filePath] = true
+	}
+}
+
+// Diff compares the generated files with the files on disk and returns the
+// files that differ, sorted by path.
+func (d *DryRun) Diff() ([]*FileDiff, error) {
+	paths := make([]string, 0, len(d.files)+len(d.removed))
+	for filePath := range d.files {
+		paths = append(paths, filePath)
+	}
+	for filePath := range d.removed {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	var diffs []*FileDiff
+	for _, filePath := range paths {
+		existing, err := ioutil.ReadFile(filePath)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "Error reading %q", filePath)
+		}
+		exists := err == nil
+		generated, isGenerated := d.files[filePath]
+		if exists == isGenerated && bytes.Equal(existing, generated) {
+			continue
+		}
+		if !exists && !isGenerated {
+			continue
+		}
+
+		fromFile, toFile := filePath, filePath
+		if !exists {
+			fromFile = os.DevNull
+		}
+		if !isGenerated {
+			toFile = os.DevNull
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(existing)),
+			B:        difflib.SplitLines(string(generated)),
+			FromFile: fromFile,
+			ToFile:   toFile,
+			Context:  3,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error diffing %q", filePath)
+		}
+		diffs = append(diffs, &FileDiff{Path: filePath, Diff: diff})
+	}
+	return diffs, nil
+}
+
+// formatGoSource formats a go file with gofmt and goimports like
+// formatGoFile, without writing it.
+func formatGoSource(filePath string, content []byte) ([]byte, error) {
+	gofmtCmd := exec.Command("gofmt", "-s", "-e")
+	formatted, err := runFormatter(gofmtCmd, content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to gofmt file: %q", filePath)
+	}
+
+	goimportsCmd := exec.Command("goimports", "-e", "-srcdir", filePath)
+	formatted, err = runFormatter(goimportsCmd, formatted)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to goimports file: %q", filePath)
+	}
+	return formatted, nil
+}
+
+func runFormatter(cmd *exec.Cmd, content []byte) ([]byte, error) {
+	var stdout bytes.Buffer
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
@@ -22,6 +22,7 @@ package codegen
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -95,8 +96,8 @@ type ClientSpec struct {
 	// `Clients` struct will contain a field of this name
 	ClientName string
 	// ThriftServiceName, if the thrift file has multiple
-	// services then this is the service that describes the client
-	// TODO: this field needs to be deprecated for multi-service support
+	// services then this is the service that describes the client.
+	// Deprecated: only used to default ExposedMethods for http clients.
 	ThriftServiceName string
 	// ExposedMethods is a map of exposed method name to thrift "$service::$method"
 	// only the method values in this map are generated for the client
@@ -155,11 +156,11 @@ func NewTChannelClientSpec(
 	clientConfig *ClientClassConfig,
 	h *PackageHelper,
 ) (*ClientSpec, error) {
-	exposedMethods := clientConfig.Config["exposedMethods"].(map[string]interface{})
+	exposedMethods, _ := clientConfig.Config["exposedMethods"].(map[string]interface{})
 	if len(exposedMethods) == 0 {
-		return nil, errors.Errorf(
-			"No methods are exposed in client config: %s",
-			instance.JSONFileName,
+		return nil, newDiagnostic(
+			instance.configPath(), "config.exposedMethods",
+			"No methods are exposed in client config",
 		)
 	}
 
@@ -168,21 +169,85 @@ func NewTChannelClientSpec(
 		return nil, err
 	}
 
-	cspec.ExposedMethods = map[string]string{}
+	cspec.ExposedMethods, err = parseExposedMethods(instance, exposedMethods)
+	if err != nil {
+		return nil, err
+	}
+
+	return cspec, nil
+}
+
+// parseExposedMethods reads the "exposedMethods" map of a client config and
+// ensures both its keys and values are unique.
+func parseExposedMethods(
+	instance *ModuleInstance,
+	exposedMethods map[string]interface{},
+) (map[string]string, error) {
+	methods := map[string]string{}
 	reversed := map[string]string{}
 	for key, val := range exposedMethods {
-		cspec.ExposedMethods[key] = val.(string)
-		reversed[val.(string)] = key
+		serviceMethod, ok := val.(string)
+		if !ok {
+			return nil, newDiagnostic(
+				instance.configPath(), "config.exposedMethods."+key,
+				"Exposed method must be a string",
+			)
+		}
+		methods[key] = serviceMethod
+		reversed[serviceMethod] = key
 	}
 
-	if len(cspec.ExposedMethods) != len(reversed) {
-		return nil, errors.Errorf(
-			"Keys or values of the exposedMethods of are not unique: %s",
-			instance.JSONFileName,
+	if len(methods) != len(reversed) {
+		return nil, newDiagnostic(
+			instance.configPath(), "config.exposedMethods",
+			"Keys or values of the exposedMethods are not unique",
 		)
 	}
 
-	return cspec, nil
+	return methods, nil
+}
+
+// defaultExposedMethods exposes every method of the named service, or of
+// every service in the thrift file if serviceName is empty. Methods whose
+// names collide across services are prefixed with their service name.
+func defaultExposedMethods(
+	mspec *ModuleSpec,
+	serviceName string,
+) (map[string]string, error) {
+	services := mspec.Services
+	if serviceName != "" {
+		services = nil
+		for _, service := range mspec.Services {
+			if service.Name == serviceName {
+				services = []*ServiceSpec{service}
+				break
+			}
+		}
+		if services == nil {
+			return nil, errors.Errorf(
+				"Service %q is not found in %q", serviceName, mspec.ThriftFile,
+			)
+		}
+	}
+
+	counts := map[string]int{}
+	for _, service := range services {
+		for _, method := range service.Methods {
+			counts[method.Name]++
+		}
+	}
+
+	exposedMethods := map[string]string{}
+	for _, service := range services {
+		for _, method := range service.Methods {
+			name := method.Name
+			if counts[name] > 1 {
+				name = service.Name + strings.Title(name)
+			}
+			exposedMethods[name] = service.Name + "::" + method.Name
+		}
+	}
+	return exposedMethods, nil
 }
 
 // NewCustomClientSpec creates a client spec from a json file whose type is custom
@@ -210,9 +275,23 @@ func NewCustomClientSpec(
 		ClientType:         clientConfig.Type,
 		ClientID:           clientConfig.Name,
 		ClientName:         instance.PackageInfo.QualifiedInstanceName,
-		CustomImportPath:   clientConfig.Config["customImportPath"].(string),
-		CustomClientType:   clientConfig.Config["customClientType"].(string),
-		CustomPackageName:  clientConfig.Config["customPackageName"].(string),
+	}
+
+	var diags Diagnostics
+	clientSpec.CustomImportPath = stringField(
+		clientConfig.Config, "customImportPath",
+		instance.configPath(), "config.", &diags,
+	)
+	clientSpec.CustomClientType = stringField(
+		clientConfig.Config, "customClientType",
+		instance.configPath(), "config.", &diags,
+	)
+	clientSpec.CustomPackageName = stringField(
+		clientConfig.Config, "customPackageName",
+		instance.configPath(), "config.", &diags,
+	)
+	if err := diags.Err(); err != nil {
+		return nil, err
 	}
 
 	return clientSpec, nil
@@ -229,11 +308,27 @@ func NewHTTPClientSpec(
 		return nil, err
 	}
 
-	if cspec.ThriftServiceName == "" {
-		return nil, errors.Errorf(
-			"client config (%s) must have serviceName field",
-			instance.JSONFileName,
+	if exposedMethods, ok := clientConfig.Config["exposedMethods"]; ok {
+		methods, ok := exposedMethods.(map[string]interface{})
+		if !ok || len(methods) == 0 {
+			return nil, newDiagnostic(
+				instance.configPath(), "config.exposedMethods",
+				"No methods are exposed in client config",
+			)
+		}
+		cspec.ExposedMethods, err = parseExposedMethods(instance, methods)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		cspec.ExposedMethods, err = defaultExposedMethods(
+			cspec.ModuleSpec, cspec.ThriftServiceName,
 		)
+		if err != nil {
+			return nil, newDiagnostic(
+				instance.configPath(), "config.serviceName", "%s", err,
+			)
+		}
 	}
 
 	return cspec, nil
@@ -246,32 +341,43 @@ func newClientSpec(
 ) (*ClientSpec, error) {
 	config := clientConfig.Config
 
+	var diags Diagnostics
 	for i := 0; i < len(mandatoryClientFields); i++ {
 		fieldName := mandatoryClientFields[i]
 		if _, ok := config[fieldName]; !ok {
-			return nil, errors.Errorf(
-				"client config (%s) must have %s field",
-				instance.JSONFileName,
-				fieldName,
-			)
+			diags = append(diags, newDiagnostic(
+				instance.configPath(), "config."+fieldName,
+				"client config must have %s field", fieldName,
+			))
 		}
 	}
+	if err := diags.Err(); err != nil {
+		return nil, err
+	}
 
-	thriftFile := filepath.Join(
-		h.ThriftIDLPath(), config["thriftFile"].(string),
-	)
+	thriftFileName, ok := config["thriftFile"].(string)
+	if !ok {
+		return nil, newDiagnostic(
+			instance.configPath(), "config.thriftFile",
+			"thriftFile must be a string",
+		)
+	}
+	thriftFile := filepath.Join(h.ThriftIDLPath(), thriftFileName)
 
 	mspec, err := NewModuleSpec(thriftFile, wantAnnot, h)
 	if err != nil {
-		return nil, errors.Wrapf(
-			err, "Could not build module spec for thrift %s: ", thriftFile,
+		return nil, newDiagnostic(
+			instance.configPath(), "config.thriftFile",
+			"Could not build module spec for thrift %s: %s", thriftFile, err,
 		)
 	}
 	mspec.PackageName = mspec.PackageName + "Client"
 
-	thriftServiceName := ""
-	if serviceName, ok := config["serviceName"]; ok {
-		thriftServiceName = serviceName.(string)
+	thriftServiceName := optionalStringField(
+		config, "serviceName", instance.configPath(), "config.", &diags,
+	)
+	if err := diags.Err(); err != nil {
+		return nil, err
 	}
 
 	return &ClientSpec{
@@ -298,6 +404,32 @@ type MiddlewareSpec struct {
 	Path string
 	// Middleware specific configuration options.
 	Options map[string]interface{}
+	// Go literals for the configured options keyed by Options field name.
+	OptionLiterals map[string]string
+	// Path of the json schema file of the middleware options.
+	SchemaFile string
+	// The json schema of the middleware options.
+	OptionsSchema *OptionsSchema
+	// Go import path of the generated dependencies struct of a middleware
+	// module with client dependencies, empty otherwise.
+	DependenciesPath string
+	// Import alias of the generated dependencies package.
+	DependenciesAlias string
+	// The field names of the clients the middleware module depends on.
+	ClientDependencies []string
+	// The middlewares that must run before the middleware.
+	MiddlewareDependencies []string
+}
+
+// MiddlewareClassConfig is the config of a middleware module.
+type MiddlewareClassConfig struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Config struct {
+		// Schema is the json schema of the middleware options, relative
+		// to the middleware directory.
+		Schema string `json:"schema"`
+	} `json:"config"`
 }
 
 // NewMiddlewareSpec creates a middleware spec from a go file.
@@ -312,6 +444,78 @@ func NewMiddlewareSpec(
 		jsonFile,
 	)
 
+	midOptSchema, err := readMiddlewareSchema(schPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateOptionsSchema(midOptSchema, schPath); err != nil {
+		return nil, err
+	}
+
+	return &MiddlewareSpec{
+		Name:          name,
+		Path:          goFile,
+		SchemaFile:    schPath,
+		OptionsSchema: midOptSchema,
+	}, nil
+}
+
+// NewMiddlewareModuleSpec creates a middleware spec from a middleware
+// module instance.
+func NewMiddlewareModuleSpec(instance *ModuleInstance) (*MiddlewareSpec, error) {
+	var config MiddlewareClassConfig
+	if err := json.Unmarshal(instance.JSONFileRaw, &config); err != nil {
+		return nil, errors.Wrapf(
+			err, "Error reading config for middleware instance",
+		)
+	}
+	if config.Config.Schema == "" {
+		return nil, newDiagnostic(
+			instance.configPath(), "config.schema",
+			"middleware %q has no options schema", instance.InstanceName,
+		)
+	}
+
+	dir := filepath.Join(instance.BaseDirectory, instance.Directory)
+	schPath := filepath.Join(dir, config.Config.Schema)
+	midOptSchema, err := readMiddlewareSchema(schPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateOptionsSchema(midOptSchema, schPath); err != nil {
+		return nil, err
+	}
+
+	spec := &MiddlewareSpec{
+		Name:          instance.InstanceName,
+		Path:          instance.PackageInfo.PackagePath,
+		SchemaFile:    schPath,
+		OptionsSchema: midOptSchema,
+	}
+
+	for _, client := range instance.ResolvedDependencies["client"] {
+		spec.ClientDependencies = append(
+			spec.ClientDependencies, client.PackageInfo.QualifiedInstanceName,
+		)
+	}
+	sort.Strings(spec.ClientDependencies)
+	if len(spec.ClientDependencies) > 0 {
+		spec.DependenciesPath = instance.PackageInfo.GeneratedPackagePath
+		spec.DependenciesAlias = instance.PackageInfo.GeneratedPackageAlias
+	}
+	for _, middleware := range instance.ResolvedDependencies["middleware"] {
+		spec.MiddlewareDependencies = append(
+			spec.MiddlewareDependencies, middleware.InstanceName,
+		)
+	}
+	sort.Strings(spec.MiddlewareDependencies)
+	return spec, nil
+}
+
+// readMiddlewareSchema reads the json schema of the middleware options.
+func readMiddlewareSchema(schPath string) (*OptionsSchema, error) {
 	bytes, err := ioutil.ReadFile(schPath)
 	if err != nil {
 		return nil, errors.Wrapf(
@@ -320,22 +524,15 @@ func NewMiddlewareSpec(
 		)
 	}
 
-	var midOptSchema map[string]interface{}
-	err = json.Unmarshal(bytes, &midOptSchema)
+	midOptSchema := &OptionsSchema{}
+	err = json.Unmarshal(bytes, midOptSchema)
 	if err != nil {
 		return nil, errors.Wrapf(
 			err, "Cannot parse json schema for middleware options: %s",
 			schPath,
 		)
 	}
-
-	// TODO(sindelar): Add middleware validation here. Validate name
-	// and package name match. Validate the options json schema matches the options
-	// struct
-	return &MiddlewareSpec{
-		Name: name,
-		Path: goFile,
-	}, nil
+	return midOptSchema, nil
 }
 
 // EndpointSpec holds information about each endpoint in the
@@ -365,9 +562,9 @@ type EndpointSpec struct {
 	ThriftMethodName string
 	// ThriftServiceName, which thrift service to use.
 	ThriftServiceName string
-	// TestFixtures, meta data to generate tests,
-	// TODO figure out struct type
-	TestFixtures []interface{}
+	// TestFixtures, meta data to generate tests, in addition to the
+	// fixtures of the endpoint test config file
+	TestFixtures []TestStub
 	// Middlewares, meta data to add middlewares,
 	Middlewares []MiddlewareSpec
 
@@ -394,18 +591,66 @@ type EndpointSpec struct {
 	ClientMethod string
 	// The client for this endpoint if httpClient or tchannelClient
 	ClientSpec *ClientSpec
+	// ReqTransforms overrides the by name mapping from the endpoint
+	// request to the client request, keyed by target field path.
+	ReqTransforms map[string]FieldMapperEntry
+	// ResTransforms overrides the by name mapping from the client
+	// response to the endpoint response, keyed by target field path.
+	ResTransforms map[string]FieldMapperEntry
+}
+
+// stringField returns the string value of a mandatory config field, adding
+// a diagnostic at prefix + field if it is missing or not a string.
+func stringField(
+	config map[string]interface{},
+	field string,
+	jsonFile string,
+	prefix string,
+	diags *Diagnostics,
+) string {
+	if _, ok := config[field]; !ok {
+		*diags = append(*diags, newDiagnostic(
+			jsonFile, prefix+field, "config must have %s field", field,
+		))
+		return ""
+	}
+	return optionalStringField(config, field, jsonFile, prefix, diags)
+}
+
+// optionalStringField returns the string value of a config field, or an
+// empty string if it is missing, adding a diagnostic at prefix + field if
+// it is not a string.
+func optionalStringField(
+	config map[string]interface{},
+	field string,
+	jsonFile string,
+	prefix string,
+	diags *Diagnostics,
+) string {
+	value, ok := config[field]
+	if !ok {
+		return ""
+	}
+	s, ok := value.(string)
+	if !ok {
+		*diags = append(*diags, newDiagnostic(
+			jsonFile, prefix+field, "%s must be a string, got %v", field, value,
+		))
+	}
+	return s
 }
 
 func ensureFields(config map[string]interface{}, mandatoryFields []string, jsonFile string) error {
+	var diags Diagnostics
 	for i := 0; i < len(mandatoryFields); i++ {
 		fieldName := mandatoryFields[i]
 		if _, ok := config[fieldName]; !ok {
-			return errors.Errorf(
-				"config (%s) must have %s field", jsonFile, fieldName,
-			)
+			diags = append(diags, newDiagnostic(
+				jsonFile, fieldName, "config must have %s field", fieldName,
+			))
 		}
 	}
-	return nil
+	return diags.Err()
 }
 
 // NewEndpointSpec creates an endpoint spec from a json file.
@@ -438,7 +683,17 @@ func NewEndpointSpec(
 		return nil, err
 	}
 
-	endpointType := endpointConfigObj["endpointType"]
+	var fieldDiags Diagnostics
+	endpointType := stringField(endpointConfigObj, "endpointType", jsonFile, "", &fieldDiags)
+	endpointID := stringField(endpointConfigObj, "endpointId", jsonFile, "", &fieldDiags)
+	handleID := stringField(endpointConfigObj, "handleId", jsonFile, "", &fieldDiags)
+	thriftFileName := stringField(endpointConfigObj, "thriftFile", jsonFile, "", &fieldDiags)
+	thriftInfo := stringField(endpointConfigObj, "thriftMethodName", jsonFile, "", &fieldDiags)
+	workflowType := stringField(endpointConfigObj, "workflowType", jsonFile, "", &fieldDiags)
+	if err := fieldDiags.Err(); err != nil {
+		return nil, err
+	}
+
 	if endpointType == "http" {
 		if err := ensureFields(endpointConfigObj, mandatoryHTTPEndpointFields, jsonFile); err != nil {
 			return nil, err
@@ -446,57 +701,42 @@ func NewEndpointSpec(
 
 	}
 	if endpointType != "http" && endpointType != "tchannel" {
-		return nil, errors.Errorf(
-			"Cannot support unknown endpointType for endpoint %s", jsonFile,
+		return nil, newDiagnostic(
+			jsonFile, "endpointType",
+			"Cannot support unknown endpointType %s", endpointType,
 		)
 	}
 
-	thriftFile := filepath.Join(
-		h.ThriftIDLPath(), endpointConfigObj["thriftFile"].(string),
-	)
+	var diags Diagnostics
+	thriftFile := filepath.Join(h.ThriftIDLPath(), thriftFileName)
 
 	mspec, err := NewModuleSpec(thriftFile, endpointType == "http", h)
 	if err != nil {
-		return nil, errors.Wrapf(
-			err, "Could not build module spec for thrift %s: ", thriftFile,
-		)
+		diags = append(diags, newDiagnostic(
+			jsonFile, "thriftFile",
+			"Could not build module spec for thrift %s: %s", thriftFile, err,
+		))
 	}
 
 	var workflowImportPath string
 	var clientID string
 	var clientMethod string
 
-	workflowType := endpointConfigObj["workflowType"].(string)
 	if workflowType == "httpClient" || workflowType == "tchannelClient" {
-		iclientID, ok := endpointConfigObj["clientID"]
-		if !ok {
-			return nil, errors.Errorf(
-				"endpoint config (%s) must have clientID field", jsonFile,
-			)
-		}
-		clientID = iclientID.(string)
-
-		iclientMethod, ok := endpointConfigObj["clientMethod"]
-		if !ok {
-			return nil, errors.Errorf(
-				"endpoint config (%s) must have clientMethod field", jsonFile,
-			)
-		}
-		clientMethod = iclientMethod.(string)
+		clientID = stringField(
+			endpointConfigObj, "clientID", jsonFile, "", &diags,
+		)
+		clientMethod = stringField(
+			endpointConfigObj, "clientMethod", jsonFile, "", &diags,
+		)
 	} else if workflowType == "custom" {
-		iworkflowImportPath, ok := endpointConfigObj["workflowImportPath"]
-		if !ok {
-			return nil, errors.Errorf(
-				"endpoint config (%s) must have workflowImportPath field",
-				jsonFile,
-			)
-		}
-		workflowImportPath = iworkflowImportPath.(string)
-	} else {
-		return nil, errors.Errorf(
-			"Invalid workflowType (%s) for endpoint (%s)",
-			workflowType, jsonFile,
+		workflowImportPath = stringField(
+			endpointConfigObj, "workflowImportPath", jsonFile, "", &diags,
 		)
+	} else {
+		diags = append(diags, newDiagnostic(
+			jsonFile, "workflowType", "Invalid workflowType (%s)", workflowType,
+		))
 	}
 
 	dirName := filepath.Base(filepath.Dir(jsonFile))
@@ -520,13 +760,21 @@ func NewEndpointSpec(
 		dirName,
 	)
 
-	thriftInfo := endpointConfigObj["thriftMethodName"].(string)
 	parts := strings.Split(thriftInfo, "::")
 	if len(parts) != 2 {
-		return nil, errors.Errorf(
-			"Cannot read thriftMethodName (%s) for endpoint json file %s : ",
-			thriftInfo, jsonFile,
-		)
+		diags = append(diags, newDiagnostic(
+			jsonFile, "thriftMethodName",
+			"Cannot read thriftMethodName (%s), expected \"$service::$method\"",
+			thriftInfo,
+		))
+	} else if mspec != nil && findMethod(mspec, parts[0], parts[1]) == nil {
+		diags = append(diags, newDiagnostic(
+			jsonFile, "thriftMethodName",
+			"Could not find thrift method %s in %s", thriftInfo, thriftFile,
+		))
+	}
+	if err := diags.Err(); err != nil {
+		return nil, err
 	}
 
 	espec := &EndpointSpec{
@@ -535,9 +783,9 @@ func NewEndpointSpec(
 		GoStructsFileName:  goStructsFileName,
 		GoFolderName:       goFolderName,
 		GoPackageName:      goPackageName,
-		EndpointType:       endpointConfigObj["endpointType"].(string),
-		EndpointID:         endpointConfigObj["endpointId"].(string),
-		HandleID:           endpointConfigObj["handleId"].(string),
+		EndpointType:       endpointType,
+		EndpointID:         endpointID,
+		HandleID:           handleID,
 		ThriftFile:         thriftFile,
 		ThriftServiceName:  parts[0],
 		ThriftMethodName:   parts[1],
@@ -547,59 +795,160 @@ func NewEndpointSpec(
 		ClientMethod:       clientMethod,
 	}
 
+	espec.ReqTransforms, err = parseFieldTransforms(
+		endpointConfigObj, "reqTransforms", jsonFile,
+	)
+	if err != nil {
+		return nil, err
+	}
+	espec.ResTransforms, err = parseFieldTransforms(
+		endpointConfigObj, "resTransforms", jsonFile,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	if endpointType == "tchannel" {
 		return espec, nil
 	}
 	return augmentHTTPEndpointSpec(espec, endpointConfigObj, midSpecs)
 }
 
+// parseFieldTransforms reads an optional map of target field path to
+// {"from": path}, {"constant": value} or {"drop": true} entries.
+func parseFieldTransforms(
+	endpointConfigObj map[string]interface{},
+	key string,
+	jsonFile string,
+) (map[string]FieldMapperEntry, error) {
+	transforms := map[string]FieldMapperEntry{}
+	m, ok := endpointConfigObj[key]
+	if !ok {
+		return transforms, nil
+	}
+
+	castMap, ok := m.(map[string]interface{})
+	if !ok {
+		return nil, newDiagnostic(jsonFile, key, "Unable to parse %s", key)
+	}
+
+	var diags Diagnostics
+	for target, value := range castMap {
+		location := key + "." + target
+		entryObj, ok := value.(map[string]interface{})
+		if !ok || len(entryObj) != 1 {
+			diags = append(diags, newDiagnostic(
+				jsonFile, location,
+				"must have exactly one of from, constant or drop",
+			))
+			continue
+		}
+
+		var entry FieldMapperEntry
+		if from, ok := entryObj["from"]; ok {
+			entry.QualifiedName, ok = from.(string)
+			if !ok || entry.QualifiedName == "" {
+				diags = append(diags, newDiagnostic(
+					jsonFile, location+".from", "must be a field path",
+				))
+				continue
+			}
+		} else if constant, ok := entryObj["constant"]; ok {
+			if constant == nil {
+				diags = append(diags, newDiagnostic(
+					jsonFile, location+".constant", "must not be null",
+				))
+				continue
+			}
+			entry.Constant = constant
+		} else if drop, ok := entryObj["drop"].(bool); ok && drop {
+			entry.Drop = true
+		} else {
+			diags = append(diags, newDiagnostic(
+				jsonFile, location,
+				"must have exactly one of from, constant or drop",
+			))
+			continue
+		}
+		transforms[target] = entry
+	}
+	if err := diags.Err(); err != nil {
+		return nil, err
+	}
+	return transforms, nil
+}
+
 func augmentHTTPEndpointSpec(
 	espec *EndpointSpec,
 	endpointConfigObj map[string]interface{},
 	midSpecs map[string]*MiddlewareSpec,
 ) (*EndpointSpec, error) {
-	espec.TestFixtures = endpointConfigObj["testFixtures"].([]interface{})
+	testFixtures, err := json.Marshal(endpointConfigObj["testFixtures"])
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot read testFixtures")
+	}
+	if err := json.Unmarshal(testFixtures, &espec.TestFixtures); err != nil {
+		return nil, newDiagnostic(
+			espec.JSONFile, "testFixtures", "Invalid test fixtures: %s", err,
+		)
+	}
 
 	endpointMids, ok := endpointConfigObj["middlewares"].([]interface{})
 	if !ok {
-		return nil, errors.Errorf(
-			"Unable to parse middlewares field",
+		return nil, newDiagnostic(
+			espec.JSONFile, "middlewares", "Unable to parse middlewares field",
 		)
 	}
+	var diags Diagnostics
 	middlewares := make([]MiddlewareSpec, len(endpointMids))
 	for idx, middleware := range endpointMids {
+		location := fmt.Sprintf("middlewares[%d]", idx)
 		middlewareObj, ok := middleware.(map[string]interface{})
 		if !ok {
-			return nil, errors.Errorf(
-				"Unable to parse middleware %s",
-				middlewareObj,
-			)
+			diags = append(diags, newDiagnostic(
+				espec.JSONFile, location,
+				"Unable to parse middleware %v", middleware,
+			))
+			continue
 		}
 		name, ok := middlewareObj["name"].(string)
 		if !ok {
-			return nil, errors.Errorf(
-				"Unable to parse \"name\" field in middleware %s",
+			diags = append(diags, newDiagnostic(
+				espec.JSONFile, location+".name",
+				"Unable to parse \"name\" field in middleware %v",
 				middlewareObj,
-			)
+			))
+			continue
 		}
 		// Verify the middleware name is defined.
 		if midSpecs[name] == nil {
-			return nil, errors.Errorf(
+			diags = append(diags, newDiagnostic(
+				espec.JSONFile, location+".name",
 				"middlewares config (%s) not found.", name,
-			)
+			))
+			continue
 		}
-		// TODO(sindelar): Validate Options against middleware spec and support
-		// nested typed objects.
 		opts, ok := middlewareObj["options"].(map[string]interface{})
 		if !ok {
 			opts = make(map[string]interface{})
 		}
-
-		middlewares[idx] = MiddlewareSpec{
-			Name:    name,
-			Path:    midSpecs[name].Path,
-			Options: opts,
+		literals, err := optionLiterals(
+			midSpecs[name], opts, espec.JSONFile, location+".options",
+		)
+		if err != nil {
+			diags.Add(espec.JSONFile, err)
+			continue
 		}
+
+		middlewares[idx] = *midSpecs[name]
+		middlewares[idx].Options = opts
+		middlewares[idx].OptionLiterals = literals
+	}
+	if err := diags.Err(); err != nil {
+		return nil, err
+	}
+	if err := validateMiddlewareOrder(espec.JSONFile, middlewares); err != nil {
+		return nil, err
 	}
 	espec.Middlewares = middlewares
 
@@ -715,10 +1064,17 @@ func (e *EndpointSpec) SetDownstream(
 	}
 
 	if clientSpec == nil {
-		return errors.Errorf(
-			"When parsing endpoint json (%s), "+
-				"could not find client (%s) in gateway",
-			e.JSONFile, e.ClientID,
+		return newDiagnostic(
+			e.JSONFile, "clientID",
+			"could not find client (%s) in gateway", e.ClientID,
+		)
+	}
+
+	if _, ok := clientSpec.ExposedMethods[e.ClientMethod]; !ok {
+		return newDiagnostic(
+			e.JSONFile, "clientMethod",
+			"Client %q does not expose method %q",
+			clientSpec.ClientID, e.ClientMethod,
 		)
 	}
 
@@ -726,7 +1082,8 @@ func (e *EndpointSpec) SetDownstream(
 
 	return e.ModuleSpec.SetDownstream(
 		e.ThriftServiceName, e.ThriftMethodName,
-		clientSpec, e.ClientMethod, h,
+		clientSpec, e.ClientMethod,
+		e.ReqTransforms, e.ResTransforms, h,
 	)
 }
 
@@ -776,6 +1133,34 @@ func parseEndpointJsons(
 	return endpointJsons, nil
 }
 
+// validateMiddlewareOrder verifies that the middlewares an endpoint
+// middleware depends on run before it.
+func validateMiddlewareOrder(
+	jsonFile string, middlewares []MiddlewareSpec,
+) error {
+	var diags Diagnostics
+	for idx, middleware := range middlewares {
+		for _, required := range middleware.MiddlewareDependencies {
+			found := false
+			for _, previous := range middlewares[:idx] {
+				if previous.Name == required {
+					found = true
+					break
+				}
+			}
+			if !found {
+				diags = append(diags, newDiagnostic(
+					jsonFile, fmt.Sprintf("middlewares[%d].name", idx),
+					"middleware %q depends on middleware %q, which must "+
+						"run before it",
+					middleware.Name, required,
+				))
+			}
+		}
+	}
+	return diags.Err()
+}
+
 func parseMiddlewareConfig(
 	middlewareConfig string,
 	configDirName string,
@@ -807,42 +1192,47 @@ func parseMiddlewareConfig(
 
 	midList, ok := configJSON["middlewares"].([]interface{})
 	if !ok {
-		return nil, errors.Wrapf(
-			err, "Cannot parse json for middleware config json: %s",
-			config,
+		return nil, newDiagnostic(
+			config, "middlewares", "middlewares must be a list",
 		)
 	}
 
-	for _, mid := range midList {
+	var diags Diagnostics
+	for idx, mid := range midList {
+		location := fmt.Sprintf("middlewares[%d]", idx)
 		mid, ok := mid.(map[string]interface{})
 		if !ok {
-			return nil, errors.Wrapf(
-				err, "Cannot parse json for middleware config json: %s",
-				config,
-			)
+			diags = append(diags, newDiagnostic(
+				config, location, "middleware must be an object",
+			))
+			continue
 		}
-		name, okOne := mid["name"].(string)
-		schema, okTwo := mid["schema"].(string)
-		importPath, okThree := mid["importPath"].(string)
-		if !okOne || !okTwo || !okThree {
-			return nil, errors.Wrapf(
-				err, "Cannot parse json for middleware config json: %s",
-				config,
-			)
+
+		var fieldDiags Diagnostics
+		name := stringField(mid, "name", config, location+".", &fieldDiags)
+		schema := stringField(mid, "schema", config, location+".", &fieldDiags)
+		importPath := stringField(
+			mid, "importPath", config, location+".", &fieldDiags,
+		)
+		if len(fieldDiags) > 0 {
+			diags = append(diags, fieldDiags...)
+			continue
 		}
 
-		specMap[name], err = NewMiddlewareSpec(
+		spec, err := NewMiddlewareSpec(
 			name,
 			importPath,
 			schema,
 			configDirName,
 		)
 		if err != nil {
-			return nil, errors.Wrapf(
-				err, "Cannot validate middleware: %s",
-				mid,
-			)
+			diags.Add(filepath.Join(configDirName, schema), err)
+			continue
 		}
+		specMap[name] = spec
+	}
+	if err := diags.Err(); err != nil {
+		return nil, err
 	}
 	return specMap, nil
 }
@@ -857,6 +1247,9 @@ type GatewaySpec struct {
 	ClientModules     map[string]*ClientSpec
 	EndpointModules   map[string]*EndpointSpec
 	MiddlewareModules map[string]*MiddlewareSpec
+	// ConfigSchema maps the config keys required by the modules to their
+	// type, it is validated when the gateway is created.
+	ConfigSchema map[string]string
 
 	gatewayName         string
 	configDirName       string
@@ -874,7 +1267,7 @@ func NewGatewaySpec(
 	middlewareConfig string,
 	gatewayName string,
 ) (*GatewaySpec, error) {
-	tmpl, err := NewTemplate()
+	tmpl, err := NewTemplateWithOverrides(packageHelper.TemplateOverrideDir())
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot create template")
 	}
@@ -912,44 +1305,115 @@ func NewGatewaySpec(
 			err, "Cannot load middlewares:")
 	}
 
+	var diags Diagnostics
+	for _, middlewareInstance := range moduleInstances["middleware"] {
+		mspec, err := NewMiddlewareModuleSpec(middlewareInstance)
+		if err != nil {
+			diags.Add(middlewareInstance.configPath(), errors.Wrapf(
+				err,
+				"Cannot create spec for middleware module %s :",
+				middlewareInstance.InstanceName,
+			))
+			continue
+		}
+		if _, ok := spec.MiddlewareModules[mspec.Name]; ok {
+			diags = append(diags, newDiagnostic(
+				middlewareInstance.configPath(), "name",
+				"middleware %q is also defined in %s",
+				mspec.Name, middlewareConfig,
+			))
+			continue
+		}
+		spec.MiddlewareModules[mspec.Name] = mspec
+	}
+
 	clientModules := moduleInstances["client"]
-	clientSpecs := make([]*ClientSpec, len(clientModules))
-	for i, clientInstance := range clientModules {
+	clientSpecs := make([]*ClientSpec, 0, len(clientModules))
+	for _, clientInstance := range clientModules {
 		cspec, err := NewClientSpec(clientInstance, packageHelper)
 		if err != nil {
-			return nil, errors.Wrapf(
+			diags.Add(clientInstance.configPath(), errors.Wrapf(
 				err,
 				"Cannot create spec for client module %s :",
 				clientInstance.InstanceName,
-			)
+			))
+			continue
 		}
 
-		clientSpecs[i] = cspec
+		clientSpecs = append(clientSpecs, cspec)
 		spec.ClientModules[cspec.ClientID] = cspec
 	}
 	for _, json := range endpointJsons {
 		espec, err := NewEndpointSpec(json, packageHelper, spec.MiddlewareModules)
 		if err != nil {
-			return nil, errors.Wrapf(
+			diags.Add(json, errors.Wrapf(
 				err, "Cannot parse endpoint json file %s :", json,
-			)
+			))
+			continue
 		}
 
 		err = espec.SetDownstream(clientSpecs, packageHelper)
 		if err != nil {
-			return nil, errors.Wrapf(
+			diags.Add(json, errors.Wrapf(
 				err, "Cannot parse downstream info for endpoint: %s", json,
-			)
+			))
+			continue
 		}
 		spec.EndpointModules[espec.EndpointID+"::"+espec.HandleID] = espec
 	}
 
+	spec.ConfigSchema, err = gatewayConfigSchema(
+		moduleInstances, spec.ClientModules,
+	)
+	if err != nil {
+		diags.Add(configDirName, err)
+	}
+
+	if err := diags.Err(); err != nil {
+		return nil, err
+	}
+
 	return spec, nil
 }
 
-// GenerateEndpointRegisterFile will generate endpoints registration for the gateway
+// EnableDryRun makes the gateway collect the generated files in the dry run
+// instead of writing them.
+func (gateway *GatewaySpec) EnableDryRun(dryRun *DryRun) {
+	gateway.Template.dryRun = dryRun
+}
+
+// GenerateEndpointRegisterFile will generate endpoints registration and the
+// OpenAPI document for the gateway
 func (gateway *GatewaySpec) GenerateEndpointRegisterFile() error {
-	_, err := gateway.Template.GenerateEndpointRegisterFile(
+	openAPIDocument, err := gateway.GenerateOpenAPIDocument()
+	if err != nil {
+		return err
+	}
+	openAPIPath := gateway.PackageHelper.TargetOpenAPIPath()
+	if gateway.Template.dryRun != nil {
+		err = gateway.Template.dryRun.writeFile(openAPIPath, openAPIDocument)
+	} else {
+		err = writeFile(openAPIPath, openAPIDocument)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = gateway.Template.GenerateOpenAPIFile(
+		openAPIDocument, gateway.PackageHelper,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = gateway.Template.GenerateEndpointRegisterFile(
+		gateway.EndpointModules, gateway.PackageHelper, gateway.ConfigSchema,
+	)
+	return err
+}
+
+// GenerateSDK will generate the go clients of the gateway endpoints
+func (gateway *GatewaySpec) GenerateSDK() error {
+	_, err := gateway.Template.GenerateSDKFiles(
 		gateway.EndpointModules, gateway.PackageHelper,
 	)
 	return err
@@ -21,6 +21,7 @@
 package codegen
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -33,6 +34,14 @@ type PathSegment struct {
 	Type           string
 	Text           string
 	BodyIdentifier string
+	// ParamName is the name of a "param" segment.
+	ParamName string
+	// ClientValue is the go expression that formats the field of the
+	// client request "r" as an escaped path segment.
+	ClientValue string
+	// ParseGoStatements parse the param of the server request "req" into
+	// the field of "requestBody", responding with a 400 on mismatch.
+	ParseGoStatements []string
 }
 
 // ExceptionSpec contains information about thrift exceptions
@@ -81,6 +90,9 @@ type MethodSpec struct {
 	ValidStatusCodes []int
 	// Additional struct generated from the bundle of request args.
 	RequestBoxed bool
+	// RequestEncoding is the body encoding set by "zanzibar.http.req.encoding",
+	// one of "json", "form" or "multipart".
+	RequestEncoding string
 	// Thrift service name the method belongs to.
 	ThriftService string
 	// The thriftrw-generated go package name
@@ -98,9 +110,15 @@ type MethodSpec struct {
 
 	// Statements for converting request types
 	ConvertRequestGoStatements []string
+	// ConvertRequestUsesHeaders is true if the request conversion
+	// reads endpoint request headers or writes client request headers
+	ConvertRequestUsesHeaders bool
 
 	// Statements for converting response types
 	ConvertResponseGoStatements []string
+	// ConvertResponseUsesHeaders is true if the response conversion
+	// reads client response headers or writes endpoint response headers
+	ConvertResponseUsesHeaders bool
 }
 
 // StructSpec specifies a Go struct to be generated.
@@ -121,6 +139,7 @@ const (
 	antHTTPPath        = "zanzibar.http.path"
 	antHTTPStatus      = "zanzibar.http.status"
 	antHTTPReqDefBoxed = "zanzibar.http.req.def"
+	antHTTPReqEncoding = "zanzibar.http.req.encoding"
 	antHTTPReqHeaders  = "zanzibar.http.reqHeaders"
 	antHTTPResHeaders  = "zanzibar.http.resHeaders"
 	antHTTPRef         = "zanzibar.http.ref"
@@ -196,7 +215,15 @@ func NewMethod(
 			"missing anotation '%s' for HTTP path", antHTTPPath,
 		)
 	}
-	method.setHTTPPath(httpPath, funcSpec)
+	err = method.setHTTPPath(httpPath, funcSpec, packageHelper)
+	if err != nil {
+		return nil, err
+	}
+
+	err = method.setRequestEncoding(funcSpec)
+	if err != nil {
+		return nil, err
+	}
 
 	method.setRequestHeaderFields(funcSpec)
 	method.setResponseHeaderFields(funcSpec)
@@ -204,6 +231,40 @@ func NewMethod(
 	return method, nil
 }
 
+// setRequestEncoding sets the http body encoding of the request. Form and
+// multipart encodings need a struct body whose fields become form values.
+func (ms *MethodSpec) setRequestEncoding(funcSpec *compile.FunctionSpec) error {
+	encoding, ok := funcSpec.Annotations[antHTTPReqEncoding]
+	if !ok {
+		ms.RequestEncoding = "json"
+		return nil
+	}
+
+	switch encoding {
+	case "json":
+	case "form", "multipart":
+		if ms.RequestType == "" {
+			return errors.Errorf(
+				"invalid annotation '%s': %s encoding without request body",
+				antHTTPReqEncoding, encoding,
+			)
+		}
+		if ms.RequestBoxed && !isStructType(funcSpec.ArgsSpec[0].Type) {
+			return errors.Errorf(
+				"invalid annotation '%s': %s encoding needs a struct body",
+				antHTTPReqEncoding, encoding,
+			)
+		}
+	default:
+		return errors.Errorf(
+			"invalid annotation '%s': unknown encoding %q",
+			antHTTPReqEncoding, encoding,
+		)
+	}
+	ms.RequestEncoding = encoding
+	return nil
+}
+
 // setRequestType sets the request type of the method specification. If the
 // "zanzibar.http.req.def.boxed" is true, then the first parameter will be used as
 // the request body; otherwise a new struct is generated to bundle the request
@@ -357,12 +418,14 @@ func (ms *MethodSpec) setExceptions(
 
 func findParamsAnnotation(
 	fields compile.FieldGroup, paramName string,
-) (string, bool) {
+) (string, *compile.FieldSpec, bool) {
 	var identifier string
+	var paramField *compile.FieldSpec
 	visitor := func(prefix string, field *compile.FieldSpec) bool {
 		if param, ok := field.Annotations[antHTTPRef]; ok {
 			if param == "params."+paramName[1:] {
 				identifier = prefix + "." + strings.Title(field.Name)
+				paramField = field
 				return true
 			}
 		}
@@ -372,10 +435,10 @@ func findParamsAnnotation(
 	walkFieldGroups(fields, visitor)
 
 	if identifier == "" {
-		return "", false
+		return "", nil, false
 	}
 
-	return identifier, true
+	return identifier, paramField, true
 }
 
 func (ms *MethodSpec) setRequestHeaderFields(
@@ -429,7 +492,11 @@ func (ms *MethodSpec) setResponseHeaderFields(
 	walkFieldGroups(fields, visitor)
 }
 
-func (ms *MethodSpec) setHTTPPath(httpPath string, funcSpec *compile.FunctionSpec) {
+func (ms *MethodSpec) setHTTPPath(
+	httpPath string,
+	funcSpec *compile.FunctionSpec,
+	packageHelper *PackageHelper,
+) error {
 	ms.HTTPPath = httpPath
 
 	segments := strings.Split(httpPath[1:], "/")
@@ -444,25 +511,163 @@ func (ms *MethodSpec) setHTTPPath(httpPath string, funcSpec *compile.FunctionSpe
 			ms.PathSegments[i].Type = "param"
 
 			var fieldSelect string
+			var field *compile.FieldSpec
 			var ok bool
 			if ms.RequestBoxed {
 				// Boxed requests mean first arg is struct
 				structType := funcSpec.ArgsSpec[0].Type.(*compile.StructSpec)
-				fieldSelect, ok = findParamsAnnotation(
+				fieldSelect, field, ok = findParamsAnnotation(
 					structType.Fields, segment,
 				)
 			} else {
-				fieldSelect, ok = findParamsAnnotation(
+				fieldSelect, field, ok = findParamsAnnotation(
 					compile.FieldGroup(funcSpec.ArgsSpec), segment,
 				)
 			}
 
 			if !ok {
-				panic("cannot find params: " + segment)
+				return errors.Errorf("cannot find params: %s", segment)
 			}
 			ms.PathSegments[i].BodyIdentifier = fieldSelect
+
+			err := ms.PathSegments[i].setParam(
+				segment[1:], field, packageHelper,
+			)
+			if err != nil {
+				return err
+			}
 		}
 	}
+	return nil
+}
+
+// setParam generates the code to format and parse a path param according to
+// the thrift type of its field.
+func (segment *PathSegment) setParam(
+	paramName string,
+	field *compile.FieldSpec,
+	packageHelper *PackageHelper,
+) error {
+	if !field.Required {
+		return errors.Errorf(
+			"path param %q must be a required field", paramName,
+		)
+	}
+
+	rootSpec := compile.RootTypeSpec(field.Type)
+	rootType, ok := pathParamPrimitives[rootSpec.ThriftName()]
+	if !ok {
+		if _, isEnum := rootSpec.(*compile.EnumSpec); !isEnum {
+			return errors.Errorf(
+				"path param %q has unsupported type %s",
+				paramName, field.Type.ThriftName(),
+			)
+		}
+	}
+
+	goType := rootType
+	if field.Type != rootSpec || !ok {
+		var err error
+		goType, err = packageHelper.TypeFullName(field.Type)
+		if err != nil {
+			return err
+		}
+	}
+
+	value := "r" + segment.BodyIdentifier
+	param := fmt.Sprintf("req.Params.ByName(%q)", paramName)
+	target := "requestBody" + segment.BodyIdentifier
+	paramVar := camelCase(paramName) + "Param"
+	badRequest := []string{
+		"if err != nil {",
+		fmt.Sprintf(
+			"res.SendErrorString(400, %q)",
+			"Invalid path parameter: "+paramName,
+		),
+		"return",
+		"}",
+	}
+
+	segment.ParamName = paramName
+	switch rootType {
+	case "string":
+		segment.ClientValue = fmt.Sprintf("url.PathEscape(string(%s))", value)
+		if goType != "string" {
+			param = fmt.Sprintf("%s(%s)", goType, param)
+		}
+		segment.ParseGoStatements = []string{
+			fmt.Sprintf("%s = %s", target, param),
+		}
+		return nil
+	case "bool":
+		segment.ClientValue = fmt.Sprintf("strconv.FormatBool(bool(%s))", value)
+		segment.ParseGoStatements = []string{
+			fmt.Sprintf("%s, err := strconv.ParseBool(%s)", paramVar, param),
+		}
+	case "float64":
+		segment.ClientValue = fmt.Sprintf(
+			"strconv.FormatFloat(float64(%s), 'g', -1, 64)", value,
+		)
+		segment.ParseGoStatements = []string{
+			fmt.Sprintf("%s, err := strconv.ParseFloat(%s, 64)", paramVar, param),
+		}
+	case "":
+		enumType, err := packageHelper.TypeFullName(rootSpec)
+		if err != nil {
+			return err
+		}
+		segment.ClientValue = fmt.Sprintf(
+			"url.PathEscape(%s(%s).String())", enumType, value,
+		)
+		segment.ParseGoStatements = []string{
+			fmt.Sprintf("var %s %s", paramVar, enumType),
+			fmt.Sprintf(
+				"if err := %s.UnmarshalText([]byte(%s)); err != nil {",
+				paramVar, param,
+			),
+		}
+		segment.ParseGoStatements = append(
+			segment.ParseGoStatements, badRequest[1:]...,
+		)
+		parsed := paramVar
+		if goType != enumType {
+			parsed = fmt.Sprintf("%s(%s)", goType, paramVar)
+		}
+		segment.ParseGoStatements = append(
+			segment.ParseGoStatements, target+" = "+parsed,
+		)
+		return nil
+	default:
+		segment.ClientValue = fmt.Sprintf(
+			"strconv.FormatInt(int64(%s), 10)", value,
+		)
+		segment.ParseGoStatements = []string{
+			fmt.Sprintf(
+				"%s, err := strconv.ParseInt(%s, 10, %s)",
+				paramVar, param, strings.TrimPrefix(rootType, "int"),
+			),
+		}
+	}
+
+	segment.ParseGoStatements = append(segment.ParseGoStatements, badRequest...)
+	segment.ParseGoStatements = append(
+		segment.ParseGoStatements,
+		fmt.Sprintf("%s = %s(%s)", target, goType, paramVar),
+	)
+	return nil
+}
+
+// pathParamPrimitives maps the thrift primitives supported as path params to
+// their go types.
+var pathParamPrimitives = map[string]string{
+	"string": "string",
+	"bool":   "bool",
+	"byte":   "int8",
+	"i8":     "int8",
+	"i16":    "int16",
+	"i32":    "int32",
+	"i64":    "int64",
+	"double": "float64",
 }
 
 func (ms *MethodSpec) setDownstream(
@@ -504,6 +709,8 @@ func (ms *MethodSpec) setDownstream(
 func (ms *MethodSpec) setTypeConverters(
 	funcSpec *compile.FunctionSpec,
 	downstreamSpec *compile.FunctionSpec,
+	reqTransforms map[string]FieldMapperEntry,
+	resTransforms map[string]FieldMapperEntry,
 	h *PackageHelper,
 ) error {
 	// TODO(sindelar): Iterate over fields that are structs (for foo/bar examples).
@@ -513,20 +720,22 @@ func (ms *MethodSpec) setTypeConverters(
 	downstreamStructType := compile.FieldGroup(downstreamSpec.ArgsSpec)
 
 	typeConverter := &TypeConverter{
-		Lines:  []string{},
-		Helper: h,
+		Lines:    []string{},
+		Helper:   h,
+		FieldMap: reqTransforms,
 	}
 
 	err := typeConverter.GenStructConverter(structType, downstreamStructType)
 	if err != nil {
-		return err
+		return errors.Wrapf(err, "could not convert request for %s", ms.Name)
 	}
 
 	ms.ConvertRequestGoStatements = typeConverter.Lines
+	ms.ConvertRequestUsesHeaders = typeConverter.UsesHeaders()
 
 	// TODO: support non-struct return types
 	respType := funcSpec.ResultSpec.ReturnType
-	downstreamRespType := funcSpec.ResultSpec.ReturnType
+	downstreamRespType := downstreamSpec.ResultSpec.ReturnType
 
 	if respType == nil || downstreamRespType == nil {
 		return nil
@@ -536,16 +745,18 @@ func (ms *MethodSpec) setTypeConverters(
 	downstreamRespFields := downstreamRespType.(*compile.StructSpec).Fields
 
 	respConverter := &TypeConverter{
-		Lines:  []string{},
-		Helper: h,
+		Lines:    []string{},
+		Helper:   h,
+		FieldMap: resTransforms,
 	}
 
 	err = respConverter.GenStructConverter(downstreamRespFields, respFields)
 	if err != nil {
-		return err
+		return errors.Wrapf(err, "could not convert response for %s", ms.Name)
 	}
 
 	ms.ConvertResponseGoStatements = respConverter.Lines
+	ms.ConvertResponseUsesHeaders = respConverter.UsesHeaders()
 
 	return nil
 }
@@ -0,0 +1,189 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package codegen
+
+import (
+	"go/token"
+	"sort"
+	"strconv"
+)
+
+// OptionsSchema is the subset of json schema used to describe the
+// options of a middleware. It is the document returned by the JSONSchema()
+// method of the middleware, whose properties are named after the fields of
+// its Options struct.
+type OptionsSchema struct {
+	Type       string                            `json:"type"`
+	Properties map[string]*OptionsSchemaProperty `json:"properties"`
+	Required   []string                          `json:"required"`
+}
+
+// OptionsSchemaProperty describes a single middleware option.
+type OptionsSchemaProperty struct {
+	Type string `json:"type"`
+	// GoName is the Options field of a property renamed by a json tag, it
+	// defaults to the property name.
+	GoName string `json:"goName,omitempty"`
+}
+
+// FieldName returns the name of the Options field of the property.
+func (prop *OptionsSchemaProperty) FieldName(name string) string {
+	if prop.GoName != "" {
+		return prop.GoName
+	}
+	return name
+}
+
+// optionSchemaTypes are the property types options can be generated for.
+var optionSchemaTypes = map[string]bool{
+	"string":  true,
+	"boolean": true,
+	"integer": true,
+	"number":  true,
+}
+
+// validateOptionsSchema checks that options can be generated from every
+// property of the schema.
+func validateOptionsSchema(schema *OptionsSchema, schemaPath string) error {
+	var diags Diagnostics
+
+	if schema.Type != "object" {
+		diags = append(diags, newDiagnostic(
+			schemaPath, "type", "options schema must be of type object",
+		))
+	}
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop := schema.Properties[name]
+		if prop == nil {
+			diags = append(diags, newDiagnostic(
+				schemaPath, "properties."+name, "property must be an object",
+			))
+			continue
+		}
+		if !optionSchemaTypes[prop.Type] {
+			diags = append(diags, newDiagnostic(
+				schemaPath, "properties."+name+".type",
+				"unsupported option type %q", prop.Type,
+			))
+		}
+		fieldName := prop.FieldName(name)
+		if !token.IsIdentifier(fieldName) || !token.IsExported(fieldName) {
+			diags = append(diags, newDiagnostic(
+				schemaPath, "properties."+name,
+				"%q is not an exported Options field, set goName to the "+
+					"field of the property", fieldName,
+			))
+		}
+	}
+
+	for _, name := range schema.Required {
+		if _, ok := schema.Properties[name]; !ok {
+			diags = append(diags, newDiagnostic(
+				schemaPath, "required",
+				"required property %s is not declared", name,
+			))
+		}
+	}
+
+	return diags.Err()
+}
+
+// optionLiterals validates endpoint middleware options against the
+// middleware schema and returns go literals keyed by Options field name.
+func optionLiterals(
+	mid *MiddlewareSpec,
+	opts map[string]interface{},
+	jsonFile string,
+	location string,
+) (map[string]string, error) {
+	var diags Diagnostics
+
+	for _, name := range mid.OptionsSchema.Required {
+		if _, ok := opts[name]; !ok {
+			diags = append(diags, newDiagnostic(
+				jsonFile, location+"."+name,
+				"missing required option for middleware %s", mid.Name,
+			))
+		}
+	}
+
+	names := make([]string, 0, len(opts))
+	for name := range opts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	literals := map[string]string{}
+	for _, name := range names {
+		prop, ok := mid.OptionsSchema.Properties[name]
+		if !ok {
+			diags = append(diags, newDiagnostic(
+				jsonFile, location+"."+name,
+				"unknown option for middleware %s", mid.Name,
+			))
+			continue
+		}
+
+		literal, ok := schemaLiteral(prop.Type, opts[name])
+		if !ok {
+			diags = append(diags, newDiagnostic(
+				jsonFile, location+"."+name,
+				"option must be of type %s", prop.Type,
+			))
+			continue
+		}
+		literals[prop.FieldName(name)] = literal
+	}
+
+	if err := diags.Err(); err != nil {
+		return nil, err
+	}
+	return literals, nil
+}
+
+func schemaLiteral(schemaType string, value interface{}) (string, bool) {
+	switch schemaType {
+	case "string":
+		if v, ok := value.(string); ok {
+			return strconv.Quote(v), true
+		}
+	case "boolean":
+		if v, ok := value.(bool); ok {
+			return strconv.FormatBool(v), true
+		}
+	case "integer":
+		if v, ok := value.(float64); ok && v == float64(int64(v)) {
+			return strconv.FormatInt(int64(v), 10), true
+		}
+	case "number":
+		if v, ok := value.(float64); ok {
+			return strconv.FormatFloat(v, 'g', -1, 64), true
+		}
+	}
+	return "", false
+}
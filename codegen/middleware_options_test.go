@@ -0,0 +1,256 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package codegen_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/zanzibar/codegen"
+)
+
+const exampleMiddleware = "github.com/uber/zanzibar/examples/example-gateway/middlewares/example"
+
+func writeTempFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	assert.NoError(t, err)
+	return path
+}
+
+func TestNewMiddlewareSpecValidatesSchema(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zanzibar-middleware")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		assert.NoError(t, os.RemoveAll(dir))
+	}()
+
+	writeTempFile(t, dir, "schema.json", `{
+		"type": "object",
+		"properties": {
+			"Foo": {"type": "integer"},
+			"Baz": {"type": "object"},
+			"qux": {"type": "string"},
+			"renamed": {"type": "string", "goName": "Renamed"}
+		},
+		"required": ["Foo", "Bar"]
+	}`)
+
+	_, err = codegen.NewMiddlewareSpec("example", exampleMiddleware, "schema.json", dir)
+	diags, ok := errors.Cause(err).(codegen.Diagnostics)
+	if !assert.True(t, ok, "expected diagnostics, got %v", err) {
+		return
+	}
+	locations := []string{}
+	for _, d := range diags {
+		assert.Equal(t, filepath.Join(dir, "schema.json"), d.File)
+		locations = append(locations, d.Location)
+	}
+	assert.Equal(t, []string{
+		"properties.Baz.type",
+		"properties.qux",
+		"required",
+	}, locations)
+}
+
+func TestEndpointMiddlewareOptionsGoName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zanzibar-middleware")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		assert.NoError(t, os.RemoveAll(dir))
+	}()
+
+	writeTempFile(t, dir, "schema.json", `{
+		"type": "object",
+		"properties": {
+			"max_count": {"type": "integer", "goName": "MaxCount"}
+		}
+	}`)
+	midSpec, err := codegen.NewMiddlewareSpec("example", exampleMiddleware, "schema.json", dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	endpoint := writeTempFile(t, dir, "endpoint.json", `{
+		"endpointType": "http",
+		"endpointId": "bar",
+		"handleId": "normal",
+		"thriftFile": "endpoints/bar/bar.thrift",
+		"thriftFileSha": "{{placeholder}}",
+		"thriftMethodName": "Bar::normal",
+		"workflowType": "httpClient",
+		"clientID": "bar",
+		"clientMethod": "normal",
+		"testFixtures": [],
+		"middlewares": [{"name": "example", "options": {"max_count": 2}}],
+		"reqHeaderMap": {},
+		"resHeaderMap": {}
+	}`)
+	espec, err := codegen.NewEndpointSpec(
+		endpoint, newPackageHelper(t),
+		map[string]*codegen.MiddlewareSpec{"example": midSpec},
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t,
+		map[string]string{"MaxCount": "2"},
+		espec.Middlewares[0].OptionLiterals,
+	)
+}
+
+func TestEndpointMiddlewareOptions(t *testing.T) {
+	h := newPackageHelper(t)
+	gatewayDir, err := filepath.Abs("../examples/example-gateway")
+	if !assert.NoError(t, err) {
+		return
+	}
+	midSpec, err := codegen.NewMiddlewareSpec(
+		"example", exampleMiddleware,
+		"middlewares/example/example_schema.json", gatewayDir,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	midSpecs := map[string]*codegen.MiddlewareSpec{"example": midSpec}
+
+	dir, err := ioutil.TempDir("", "zanzibar-middleware")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		assert.NoError(t, os.RemoveAll(dir))
+	}()
+
+	endpoint := func(options string) string {
+		return writeTempFile(t, dir, "endpoint.json", `{
+			"endpointType": "http",
+			"endpointId": "bar",
+			"handleId": "normal",
+			"thriftFile": "endpoints/bar/bar.thrift",
+			"thriftFileSha": "{{placeholder}}",
+			"thriftMethodName": "Bar::normal",
+			"workflowType": "httpClient",
+			"clientID": "bar",
+			"clientMethod": "normal",
+			"testFixtures": [],
+			"middlewares": [{"name": "example", "options": `+options+`}],
+			"reqHeaderMap": {},
+			"resHeaderMap": {}
+		}`)
+	}
+
+	espec, err := codegen.NewEndpointSpec(
+		endpoint(`{"Foo": "te\"st", "Bar": 3}`), h, midSpecs,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]string{
+		"Foo": `"te\"st"`,
+		"Bar": "3",
+	}, espec.Middlewares[0].OptionLiterals)
+
+	_, err = codegen.NewEndpointSpec(
+		endpoint(`{"Bar": "3", "Qux": true}`), h, midSpecs,
+	)
+	diags, ok := errors.Cause(err).(codegen.Diagnostics)
+	if !assert.True(t, ok, "expected diagnostics, got %v", err) {
+		return
+	}
+	assert.Equal(t, 3, len(diags))
+	assert.Equal(t, "middlewares[0].options.Foo", diags[0].Location)
+	assert.Equal(t, "middlewares[0].options.Bar", diags[1].Location)
+	assert.Equal(t, "middlewares[0].options.Qux", diags[2].Location)
+}
+
+func TestEndpointMiddlewareOrder(t *testing.T) {
+	h := newPackageHelper(t)
+	gatewayDir, err := filepath.Abs("../examples/example-gateway")
+	if !assert.NoError(t, err) {
+		return
+	}
+	midSpec, err := codegen.NewMiddlewareSpec(
+		"example", exampleMiddleware,
+		"middlewares/example/example_schema.json", gatewayDir,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	readerSpec := *midSpec
+	readerSpec.Name = "example_reader"
+	readerSpec.MiddlewareDependencies = []string{"example"}
+	midSpecs := map[string]*codegen.MiddlewareSpec{
+		"example":        midSpec,
+		"example_reader": &readerSpec,
+	}
+
+	dir, err := ioutil.TempDir("", "zanzibar-middleware")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		assert.NoError(t, os.RemoveAll(dir))
+	}()
+
+	endpoint := func(middlewares string) string {
+		return writeTempFile(t, dir, "endpoint.json", `{
+			"endpointType": "http",
+			"endpointId": "bar",
+			"handleId": "normal",
+			"thriftFile": "endpoints/bar/bar.thrift",
+			"thriftFileSha": "{{placeholder}}",
+			"thriftMethodName": "Bar::normal",
+			"workflowType": "httpClient",
+			"clientID": "bar",
+			"clientMethod": "normal",
+			"testFixtures": [],
+			"middlewares": `+middlewares+`,
+			"reqHeaderMap": {},
+			"resHeaderMap": {}
+		}`)
+	}
+
+	_, err = codegen.NewEndpointSpec(endpoint(`[
+		{"name": "example", "options": {"Foo": "a", "Bar": 1}},
+		{"name": "example_reader", "options": {"Foo": "b", "Bar": 2}}
+	]`), h, midSpecs)
+	assert.NoError(t, err)
+
+	_, err = codegen.NewEndpointSpec(endpoint(`[
+		{"name": "example_reader", "options": {"Foo": "b", "Bar": 2}},
+		{"name": "example", "options": {"Foo": "a", "Bar": 1}}
+	]`), h, midSpecs)
+	diags, ok := errors.Cause(err).(codegen.Diagnostics)
+	if !assert.True(t, ok, "expected diagnostics, got %v", err) {
+		return
+	}
+	assert.Equal(t, 1, len(diags))
+	assert.Equal(t, "middlewares[0].name", diags[0].Location)
+}
@@ -58,6 +58,25 @@ func NewModuleSystem() *ModuleSystem {
 type ModuleSystem struct {
 	classes    map[string]*ModuleClass
 	classOrder []string
+	buildSalt  string
+	dryRun     *DryRun
+}
+
+// EnableIncrementalBuild makes GenerateBuild skip module instances whose
+// inputs are unchanged since the previous build. The salt identifies the
+// generators, so changing it invalidates every previously built instance.
+// Skipped instances have no GeneratedSpec unless a regenerated dependent
+// needs it.
+func (system *ModuleSystem) EnableIncrementalBuild(salt string) {
+	system.buildSalt = salt
+}
+
+// EnableDryRun makes GenerateBuild collect the generated files in the dry
+// run instead of writing them. Every module instance is generated and the
+// build manifest is left untouched, the outputs of the previous build that
+// are no longer generated are marked as removed.
+func (system *ModuleSystem) EnableDryRun(dryRun *DryRun) {
+	system.dryRun = dryRun
 }
 
 // RegisterClass defines a class of module in the module system
@@ -153,6 +172,8 @@ func (system *ModuleSystem) populateResolvedDependencies(
 	classInstances []*ModuleInstance,
 	resolvedModules map[string][]*ModuleInstance,
 ) error {
+	var diags Diagnostics
+
 	// Resolve the class dependencies
 	for _, classInstance := range classInstances {
 		for _, classDependency := range classInstance.Dependencies {
@@ -160,12 +181,15 @@ func (system *ModuleSystem) populateResolvedDependencies(
 				resolvedModules[classDependency.ClassName]
 
 			if !ok {
-				return errors.Errorf(
+				diags = append(diags, newDiagnostic(
+					classInstance.configPath(),
+					"dependencies."+classDependency.ClassName,
 					"Invalid class name %q in dependencies for %q %q",
 					classDependency.ClassName,
 					classInstance.ClassName,
 					classInstance.InstanceName,
-				)
+				))
+				continue
 			}
 
 			// TODO: We don't want to linear scan here
@@ -179,14 +203,17 @@ func (system *ModuleSystem) populateResolvedDependencies(
 			}
 
 			if dependencyInstance == nil {
-				return errors.Errorf(
-					"Unknown %q class depdendency %q"+
+				diags = append(diags, newDiagnostic(
+					classInstance.configPath(),
+					"dependencies."+classDependency.ClassName,
+					"Unknown %q class depdendency %q "+
 						"in dependencies for %q %q",
 					classDependency.ClassName,
 					classDependency.InstanceName,
 					classInstance.ClassName,
 					classInstance.InstanceName,
-				)
+				))
+				continue
 			}
 
 			resolvedDependencies, ok :=
@@ -201,7 +228,7 @@ func (system *ModuleSystem) populateResolvedDependencies(
 		}
 	}
 
-	return nil
+	return diags.Err()
 }
 
 func appendUniqueModule(
@@ -229,6 +256,7 @@ func (system *ModuleSystem) ResolveModules(
 ) (map[string][]*ModuleInstance, error) {
 
 	resolvedModules := map[string][]*ModuleInstance{}
+	var diags Diagnostics
 
 	for _, className := range system.classOrder {
 		class := system.classes[className]
@@ -245,26 +273,33 @@ func (system *ModuleSystem) ResolveModules(
 				class.Directory,
 			)
 			if instanceErr != nil {
-				return nil, errors.Wrapf(
+				diags.Add(fullInstanceDirectory, errors.Wrapf(
 					instanceErr,
 					"Error reading single instance %q in %q",
 					className,
 					class.Directory,
-				)
+				))
+			} else {
+				classInstances = append(classInstances, instance)
 			}
-			classInstances = append(classInstances, instance)
 		} else {
 
 			files, err := ioutil.ReadDir(fullInstanceDirectory)
 
+			// A gateway without a class directory has no instances of
+			// the class
+			if os.IsNotExist(err) {
+				err = nil
+			}
 			if err != nil {
-				// TODO: We should accumulate errors and list them all here
 				// Expected $path to be a class directory
-				return nil, errors.Wrapf(
+				diags.Add(fullInstanceDirectory, errors.Wrapf(
 					err,
 					"Error reading module instance directory %q",
 					fullInstanceDirectory,
-				)
+				))
+				resolvedModules[className] = classInstances
+				continue
 			}
 
 			for _, file := range files {
@@ -277,12 +312,16 @@ func (system *ModuleSystem) ResolveModules(
 						filepath.Join(class.Directory, file.Name()),
 					)
 					if instanceErr != nil {
-						return nil, errors.Wrapf(
-							instanceErr,
-							"Error reading multi instance %q in %q",
-							className,
-							filepath.Join(class.Directory, file.Name()),
+						diags.Add(
+							filepath.Join(fullInstanceDirectory, file.Name()),
+							errors.Wrapf(
+								instanceErr,
+								"Error reading multi instance %q in %q",
+								className,
+								filepath.Join(class.Directory, file.Name()),
+							),
 						)
+						continue
 					}
 					classInstances = append(classInstances, instance)
 				}
@@ -296,10 +335,14 @@ func (system *ModuleSystem) ResolveModules(
 			classInstances, resolvedModules,
 		)
 		if err != nil {
-			return nil, err
+			diags.Add(fullInstanceDirectory, err)
 		}
 	}
 
+	if err := diags.Err(); err != nil {
+		return nil, err
+	}
+
 	return resolvedModules, nil
 }
 
@@ -322,12 +365,9 @@ func (system *ModuleSystem) readInstance(
 	raw, err := jsonConfig.Read(classConfigPath)
 
 	if err != nil {
-		// TODO: We should accumulate errors and list them all here
 		// Expected $class-config.json to exist in ...
-		return nil, errors.Wrapf(
-			err,
-			"Error reading JSON Config %q",
-			classConfigPath,
+		return nil, newDiagnostic(
+			classConfigPath, "", "Error reading JSON Config: %s", err,
 		)
 	}
 
@@ -343,13 +383,12 @@ func (system *ModuleSystem) readInstance(
 	)
 
 	if err != nil {
-		// TODO: We should accumulate errors and list them all here
-		// Expected $class-config.json to exist in ...
-		return nil, errors.Wrapf(
-			err,
-			"Error reading class package info for %q %q",
+		return nil, newDiagnostic(
+			classConfigPath, "",
+			"Error reading class package info for %q %q: %s",
 			className,
 			jsonConfig.Name,
+			err,
 		)
 	}
 
@@ -477,6 +516,20 @@ func (system *ModuleSystem) GenerateBuild(
 		moduleCount += len(moduleList)
 	}
 
+	var diags Diagnostics
+	failed := map[*ModuleInstance]bool{}
+
+	incremental := system.buildSalt != "" && system.dryRun == nil
+	prevManifest := readBuildManifest(targetGenDir, system.buildSalt)
+	manifest := &buildManifest{
+		Salt:    system.buildSalt,
+		Modules: map[string]*moduleManifest{},
+	}
+	hashes := map[*ModuleInstance]string{}
+	skipped := map[*ModuleInstance]bool{}
+	regenerated := 0
+	removed := 0
+
 	moduleIndex := 0
 	for _, className := range system.classOrder {
 		classInstances := resolvedModules[className]
@@ -516,6 +569,58 @@ func (system *ModuleSystem) GenerateBuild(
 				continue
 			}
 
+			manifestKey := classInstance.ClassName + "/" +
+				classInstance.InstanceName
+			prevModule := prevManifest.Modules[manifestKey]
+			if prevModule != nil {
+				// Keep the previous outputs until the instance is rebuilt
+				manifest.Modules[manifestKey] = &moduleManifest{
+					Outputs: prevModule.Outputs,
+				}
+			}
+
+			if hasFailedDependency(classInstance, failed) {
+				fmt.Printf(
+					"Skipping generation of %q %q class of type %q "+
+						"as a dependency failed to generate\n",
+					classInstance.InstanceName,
+					classInstance.ClassName,
+					classInstance.ClassType,
+				)
+				failed[classInstance] = true
+				continue
+			}
+
+			var inputHash string
+			if incremental {
+				inputHash, err = system.inputHash(classInstance, hashes)
+				if err != nil {
+					diags.Add(classInstance.configPath(), err)
+					failed[classInstance] = true
+					continue
+				}
+
+				if prevModule != nil &&
+					prevModule.upToDate(targetGenDir, inputHash) {
+					fmt.Printf(
+						"Skipping generation of %q %q class of type %q "+
+							"as its inputs are unchanged\n",
+						classInstance.InstanceName,
+						classInstance.ClassName,
+						classInstance.ClassType,
+					)
+					manifest.Modules[manifestKey] = prevModule
+					skipped[classInstance] = true
+					continue
+				}
+			}
+
+			if err := system.restoreSpecs(classInstance, skipped); err != nil {
+				diags.Add(classInstance.configPath(), err)
+				failed[classInstance] = true
+				continue
+			}
+
 			buildResult, err := generator.Generate(classInstance)
 
 			if err != nil {
@@ -526,14 +631,19 @@ func (system *ModuleSystem) GenerateBuild(
 					classInstance.ClassType,
 					err.Error(),
 				)
-				return nil, err
-			}
-
-			if buildResult == nil {
+				diags.Add(classInstance.configPath(), err)
+				failed[classInstance] = true
 				continue
 			}
 
-			classInstance.genSpec = buildResult.Spec
+			regenerated++
+			outputs := map[string]string{}
+
+			if buildResult != nil {
+				classInstance.genSpec = buildResult.Spec
+			} else {
+				buildResult = &BuildResult{}
+			}
 
 			for filePath, content := range buildResult.Files {
 				filePath = filepath.Clean(filePath)
@@ -551,6 +661,14 @@ func (system *ModuleSystem) GenerateBuild(
 					filePath,
 				)
 
+				if system.dryRun != nil {
+					err := system.dryRun.writeFile(resolvedPath, content)
+					if err != nil {
+						return nil, err
+					}
+					continue
+				}
+
 				if err := writeFile(resolvedPath, content); err != nil {
 					return nil, errors.Wrapf(
 						err,
@@ -569,13 +687,123 @@ func (system *ModuleSystem) GenerateBuild(
 						return nil, err
 					}
 				}
+
+				if incremental {
+					outputPath := filepath.Join(
+						classInstance.Directory, filePath,
+					)
+					outputs[outputPath], err = hashFile(resolvedPath)
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			if !incremental {
+				continue
+			}
+
+			count, err := removeOrphans(targetGenDir, prevModule, outputs)
+			if err != nil {
+				return nil, err
+			}
+			removed += count
+			manifest.Modules[manifestKey] = &moduleManifest{
+				InputHash: inputHash,
+				Outputs:   outputs,
 			}
 		}
 	}
 
+	if system.dryRun != nil {
+		for _, prevModule := range prevManifest.Modules {
+			for outputPath := range prevModule.Outputs {
+				system.dryRun.removeFile(
+					filepath.Join(targetGenDir, outputPath),
+				)
+			}
+		}
+	}
+
+	if incremental {
+		for key, prevModule := range prevManifest.Modules {
+			if _, ok := manifest.Modules[key]; ok {
+				continue
+			}
+			count, err := removeOrphans(targetGenDir, prevModule, nil)
+			if err != nil {
+				return nil, err
+			}
+			removed += count
+		}
+
+		if err := manifest.write(targetGenDir); err != nil {
+			return nil, err
+		}
+
+		fmt.Printf(
+			"Regenerated %d of %d modules, removed %d orphaned files\n",
+			regenerated, moduleCount, removed,
+		)
+	}
+
+	if err := diags.Err(); err != nil {
+		return nil, err
+	}
+
 	return resolvedModules, nil
 }
 
+// restoreSpecs runs the generators of dependencies that were skipped by an
+// incremental build so their specs are available to the instance. The
+// generated files are discarded as they are unchanged.
+func (system *ModuleSystem) restoreSpecs(
+	instance *ModuleInstance,
+	skipped map[*ModuleInstance]bool,
+) error {
+	for _, dependencies := range instance.ResolvedDependencies {
+		for _, dependency := range dependencies {
+			if !skipped[dependency] {
+				continue
+			}
+			if err := system.restoreSpecs(dependency, skipped); err != nil {
+				return err
+			}
+
+			generator := system.classes[dependency.ClassName].
+				types[dependency.ClassType]
+			buildResult, err := generator.Generate(dependency)
+			if err != nil {
+				return errors.Wrapf(
+					err,
+					"Error generating spec of dependency %q %q",
+					dependency.ClassName,
+					dependency.InstanceName,
+				)
+			}
+			if buildResult != nil {
+				dependency.genSpec = buildResult.Spec
+			}
+			skipped[dependency] = false
+		}
+	}
+	return nil
+}
+
+func hasFailedDependency(
+	instance *ModuleInstance,
+	failed map[*ModuleInstance]bool,
+) bool {
+	for _, dependencies := range instance.ResolvedDependencies {
+		for _, dependency := range dependencies {
+			if failed[dependency] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func formatGoFile(filePath string) error {
 	gofmtCmd := exec.Command("gofmt", "-s", "-w", "-e", filePath)
 	gofmtCmd.Stdout = os.Stdout
@@ -712,6 +940,13 @@ type ModuleInstance struct {
 	JSONFileRaw []byte
 }
 
+// configPath returns the path of the instance json file
+func (instance *ModuleInstance) configPath() string {
+	return filepath.Join(
+		instance.BaseDirectory, instance.Directory, instance.JSONFileName,
+	)
+}
+
 // GeneratedSpec returns the last spec result returned for the module instance
 func (instance *ModuleInstance) GeneratedSpec() interface{} {
 	return instance.genSpec
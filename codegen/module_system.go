@@ -22,6 +22,10 @@ package codegen
 
 import (
 	"encoding/json"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
 	"io/ioutil"
 	"os"
 	"path"
@@ -46,6 +50,9 @@ type EndpointMeta struct {
 	ReqHeaderMapKeys   []string
 	ResHeaderMap       map[string]string
 	ResHeaderMapKeys   []string
+	// ClientDependencies are the qualified names of the clients declared in
+	// the dependencies of the endpoint module
+	ClientDependencies []string
 }
 
 // EndpointTestMeta saves meta data used to render an endpoint test.
@@ -58,46 +65,13 @@ type EndpointTestMeta struct {
 	IncludedPackages []GoPackageImport
 }
 
-// TestStub saves stubbed requests/responses for an endpoint test.
-type TestStub struct {
-	TestName               string
-	EndpointID             string
-	HandlerID              string
-	EndpointRequest        map[string]interface{} // Json blob
-	EndpointRequestString  string
-	EndpointReqHeaders     map[string]string      // Json blob
-	EndpointReqHeaderKeys  []string               // To keep in canonical order
-	EndpointResponse       map[string]interface{} // Json blob
-	EndpointResponseString string
-	EndpointResHeaders     map[string]string // Json blob
-	EndpointResHeaderKeys  []string          // To keep in canonical order
-
-	ClientStubs []ClientStub
-
-	TestServiceName string // The service module that mounts the endpoint
-}
-
-// ClientStub saves stubbed client request/response for an endpoint test.
-type ClientStub struct {
-	ClientID             string
-	ClientMethod         string
-	ClientRequest        map[string]interface{} // Json blob
-	ClientRequestString  string
-	ClientReqHeaders     map[string]string      // Json blob
-	ClientReqHeaderKeys  []string               // To keep in canonical order
-	ClientResponse       map[string]interface{} // Json blob
-	ClientResponseString string
-	ClientResHeaders     map[string]string // Json blob
-	ClientResHeaderKeys  []string          // To keep in canonical order
-}
-
 // NewDefaultModuleSystem creates a fresh instance of the default zanzibar
 // module system (clients, endpoints)
 func NewDefaultModuleSystem(
 	h *PackageHelper,
 ) (*ModuleSystem, error) {
 	system := NewModuleSystem()
-	tmpl, err := NewTemplate()
+	tmpl, err := NewTemplateWithOverrides(h.TemplateOverrideDir())
 
 	if err != nil {
 		return nil, err
@@ -180,11 +154,30 @@ func NewDefaultModuleSystem(
 		)
 	}
 
+	// Register middleware module class and type generators
+	if err := system.RegisterClass("middleware", ModuleClass{
+		Directory:         "middlewares",
+		ClassType:         MultiModule,
+		ClassDependencies: []string{"client", "middleware"},
+	}); err != nil {
+		return nil, errors.Wrapf(err, "Error registering middleware class")
+	}
+
+	if err := system.RegisterClassType("middleware", "default", &MiddlewareGenerator{
+		templates:     tmpl,
+		packageHelper: h,
+	}); err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Error registering default middleware class type",
+		)
+	}
+
 	// Register endpoint module class and type generators
 	if err := system.RegisterClass("endpoint", ModuleClass{
 		Directory:         "endpoints",
 		ClassType:         MultiModule,
-		ClassDependencies: []string{"client"},
+		ClassDependencies: []string{"client", "middleware"},
 	}); err != nil {
 		return nil, errors.Wrapf(err, "Error registering endpoint class")
 	}
@@ -208,6 +201,8 @@ func NewDefaultModuleSystem(
 			"Error registering HTTP endpoint class type",
 		)
 	}
+	system.EnableIncrementalBuild(generatorHash(h.TemplateOverrideDir()))
+
 	return system, nil
 }
 
@@ -273,6 +268,11 @@ func (g *HTTPClientGenerator) Generate(
 		)
 	}
 
+	exposedMethods, err := reverseExposedMethods(clientSpec, instance)
+	if err != nil {
+		return nil, err
+	}
+
 	clientMeta := &ClientMeta{
 		ExportName:       clientSpec.ExportName,
 		ExportType:       clientSpec.ExportType,
@@ -280,6 +280,13 @@ func (g *HTTPClientGenerator) Generate(
 		Services:         clientSpec.ModuleSpec.Services,
 		IncludedPackages: clientSpec.ModuleSpec.IncludedPackages,
 		ClientID:         clientSpec.ClientID,
+		ExposedMethods:   exposedMethods,
+		Methods: exposedClientMethods(
+			clientSpec.ExportType, clientSpec.ModuleSpec, exposedMethods,
+		),
+	}
+	for _, method := range clientMeta.Methods {
+		method.MethodName = strings.Title(method.MethodName)
 	}
 
 	client, err := g.templates.execTemplate(
@@ -312,11 +319,21 @@ func (g *HTTPClientGenerator) Generate(
 		)
 	}
 
+	mock, err := generateClientMock(g.templates, g.packageHelper, clientSpec, clientMeta)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Error executing HTTP client mock template for %q",
+			instance.InstanceName,
+		)
+	}
+
 	baseName := filepath.Base(instance.Directory)
 	clientFilePath := baseName + ".go"
 
 	files := map[string][]byte{
 		clientFilePath: client,
+		clientMockPath: mock,
 	}
 
 	if dependencies != nil {
@@ -329,6 +346,96 @@ func (g *HTTPClientGenerator) Generate(
 	}, nil
 }
 
+// InputFiles returns the thrift files of the HTTP client
+func (g *HTTPClientGenerator) InputFiles(instance *ModuleInstance) []string {
+	return clientThriftFiles(instance, g.packageHelper)
+}
+
+func clientThriftFiles(
+	instance *ModuleInstance,
+	packageHelper *PackageHelper,
+) []string {
+	var clientConfig struct {
+		Config struct {
+			ThriftFile string `json:"thriftFile"`
+		} `json:"config"`
+	}
+	err := json.Unmarshal(instance.JSONFileRaw, &clientConfig)
+	if err != nil || clientConfig.Config.ThriftFile == "" {
+		return nil
+	}
+	return thriftFileClosure(filepath.Join(
+		packageHelper.ThriftIDLPath(), clientConfig.Config.ThriftFile,
+	))
+}
+
+// reverseExposedMethods indexes the exposed methods of a client by
+// "$service::$method" and verifies that each of them exists.
+func reverseExposedMethods(
+	clientSpec *ClientSpec,
+	instance *ModuleInstance,
+) (map[string]string, error) {
+	exposedMethods := map[string]string{}
+	for k, v := range clientSpec.ExposedMethods {
+		exposedMethods[v] = k
+	}
+
+	serviceMethods := make([]string, 0, len(exposedMethods))
+	for serviceMethod := range exposedMethods {
+		serviceMethods = append(serviceMethods, serviceMethod)
+	}
+	sort.Strings(serviceMethods)
+
+	var diags Diagnostics
+	for _, serviceMethod := range serviceMethods {
+		segments := strings.Split(serviceMethod, "::")
+		if len(segments) != 2 ||
+			findMethod(clientSpec.ModuleSpec, segments[0], segments[1]) == nil {
+			diags = append(diags, newDiagnostic(
+				instance.configPath(),
+				"config.exposedMethods."+exposedMethods[serviceMethod],
+				"Invalid exposedMethods for %s client (%q). "+
+					"The exposedMethod (%q) does not exist in %q",
+				clientSpec.ClientType,
+				instance.InstanceName,
+				serviceMethod,
+				clientSpec.ThriftFile,
+			))
+		}
+	}
+	if err := diags.Err(); err != nil {
+		return nil, err
+	}
+
+	return exposedMethods, nil
+}
+
+// clientMockPath is where the mock of a generated client is written to,
+// relative to the client package.
+const clientMockPath = "mock-client/mock_client.go"
+
+// generateClientMock renders the mock implementing the interface of a
+// generated client.
+func generateClientMock(
+	t *Template, h *PackageHelper, clientSpec *ClientSpec, clientMeta *ClientMeta,
+) ([]byte, error) {
+	methods := make([]*ClientMethodMeta, len(clientMeta.Methods))
+	for i, method := range clientMeta.Methods {
+		methods[i] = newClientMethodMeta(
+			"MockClient", method.MethodName, method.Method,
+		)
+	}
+	return t.execTemplate("client_mock.tmpl", &ClientMockMeta{
+		ClientID: clientSpec.ClientID,
+		ClientPackage: GoPackageImport{
+			PackageName: clientSpec.ImportPackagePath,
+			AliasName:   clientSpec.ImportPackageAlias,
+		},
+		IncludedPackages: clientMeta.IncludedPackages,
+		Methods:          methods,
+	}, h)
+}
+
 /*
  * TChannel Client Generator
  */
@@ -367,45 +474,9 @@ func (g *TChannelClientGenerator) Generate(
 		)
 	}
 
-	// reverse index the exposed methods map
-	exposedMethods := map[string]string{}
-	for k, v := range clientSpec.ExposedMethods {
-		exposedMethods[v] = k
-	}
-
-	// TODO: Verify all exposedMethods exist and are valid.
-	for exposedMethod := range exposedMethods {
-		segments := strings.Split(exposedMethod, "::")
-		thriftService := segments[0]
-		thriftMethod := segments[1]
-		found := false
-
-		for _, candidateService := range clientSpec.ModuleSpec.Services {
-			if found {
-				break
-			}
-			if candidateService.Name != thriftService {
-				continue
-			}
-
-			for _, candidateMethod := range candidateService.Methods {
-				if candidateMethod.Name != thriftMethod {
-					continue
-				}
-
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			return nil, errors.Errorf(
-				"Invalid exposedMethods for tchannel client (%q). "+
-					"The exposedMethod (%q) does not exist",
-				instance.InstanceName,
-				exposedMethod,
-			)
-		}
+	exposedMethods, err := reverseExposedMethods(clientSpec, instance)
+	if err != nil {
+		return nil, err
 	}
 
 	clientMeta := &ClientMeta{
@@ -416,6 +487,9 @@ func (g *TChannelClientGenerator) Generate(
 		IncludedPackages: clientSpec.ModuleSpec.IncludedPackages,
 		ClientID:         clientSpec.ClientID,
 		ExposedMethods:   exposedMethods,
+		Methods: exposedClientMethods(
+			clientSpec.ExportType, clientSpec.ModuleSpec, exposedMethods,
+		),
 	}
 
 	client, err := g.templates.execTemplate(
@@ -461,6 +535,15 @@ func (g *TChannelClientGenerator) Generate(
 		)
 	}
 
+	mock, err := generateClientMock(g.templates, g.packageHelper, clientSpec, clientMeta)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Error executing TChannel client mock template for %q",
+			instance.InstanceName,
+		)
+	}
+
 	baseName := filepath.Base(instance.Directory)
 	clientFilePath := baseName + ".go"
 	serverFilePath := baseName + "_test_server.go"
@@ -468,6 +551,7 @@ func (g *TChannelClientGenerator) Generate(
 	files := map[string][]byte{
 		clientFilePath: client,
 		serverFilePath: server,
+		clientMockPath: mock,
 	}
 
 	if dependencies != nil {
@@ -480,6 +564,13 @@ func (g *TChannelClientGenerator) Generate(
 	}, nil
 }
 
+// InputFiles returns the thrift files of the TChannel client
+func (g *TChannelClientGenerator) InputFiles(
+	instance *ModuleInstance,
+) []string {
+	return clientThriftFiles(instance, g.packageHelper)
+}
+
 /*
  * Custom Client Generator
  */
@@ -610,13 +701,15 @@ func (g *ClientsInitGenerator) Generate(
 			continue
 		}
 
+		// Generated clients are held by their interface so that they can be
+		// replaced by their mocks in tests.
 		clientInfo = append(clientInfo, ClientInfoMeta{
-			IsPointerType: true,
+			IsPointerType: false,
 			FieldName:     strings.Title(client.ClientName),
 			PackagePath:   client.ImportPackagePath,
 			PackageAlias:  client.ImportPackageAlias,
 			ExportName:    client.ExportName,
-			ExportType:    client.ExportType,
+			ExportType:    "Client",
 		})
 	}
 
@@ -670,6 +763,11 @@ func (g *EndpointGenerator) Generate(
 		)
 	}
 
+	midSpecs, err := endpointMiddlewareSpecs(instance, g.packageHelper)
+	if err != nil {
+		return nil, err
+	}
+
 	endpointConfigDir := filepath.Join(
 		instance.BaseDirectory,
 		instance.Directory,
@@ -679,47 +777,259 @@ func (g *EndpointGenerator) Generate(
 			endpointJsons, filepath.Join(endpointConfigDir, fileName),
 		)
 	}
+	var diags Diagnostics
+	workflows := map[string]bool{}
 	for _, jsonFile := range endpointJsons {
-		espec, err := NewEndpointSpec(jsonFile, g.packageHelper, g.packageHelper.MiddlewareSpecs())
+		espec, err := NewEndpointSpec(jsonFile, g.packageHelper, midSpecs)
 		if err != nil {
-			return nil, errors.Wrapf(
+			diags.Add(jsonFile, errors.Wrapf(
 				err, "Error parsing endpoint json file: %s", jsonFile,
-			)
+			))
+			continue
 		}
 
 		endpointSpecs = append(endpointSpecs, espec)
 
+		workflowPath := espec.WorkflowImportPath
+		if workflowPath != "" && !workflows[workflowPath] {
+			workflows[workflowPath] = true
+			err = validateWorkflowClients(
+				workflowPath, instance, g.packageHelper,
+			)
+			if err != nil {
+				diags.Add(jsonFile, err)
+				continue
+			}
+		}
+
 		err = espec.SetDownstream(clientSpecs, g.packageHelper)
 		if err != nil {
-			return nil, errors.Wrapf(
+			diags.Add(jsonFile, errors.Wrapf(
 				err, "Error parsing downstream info for endpoint: %s", jsonFile,
-			)
+			))
+			continue
 		}
 
 		err = g.generateEndpointFile(espec, instance, ret)
 		if err != nil {
-			return nil, errors.Wrapf(
+			diags.Add(jsonFile, errors.Wrapf(
 				err,
 				"Error executing endpoint template %q",
 				instance.InstanceName,
-			)
+			))
+			continue
 		}
 
-		err = g.generateEndpointTestFile(espec, instance, ret)
+		err = g.generateEndpointTestFile(espec, instance, clientSpecs, ret)
 		if err != nil {
-			return nil, errors.Wrapf(
+			diags.Add(jsonFile, errors.Wrapf(
 				err,
 				"Error executing endpoint test template %q",
 				instance.InstanceName,
-			)
+			))
 		}
 	}
+	if err := diags.Err(); err != nil {
+		return nil, err
+	}
+
+	dependencies, err := g.templates.execTemplate(
+		"dependency_struct.tmpl",
+		endpointDependencies(instance),
+		g.packageHelper,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Error generating dependencies struct for %q %q",
+			instance.ClassName,
+			instance.InstanceName,
+		)
+	}
+	ret[path.Join(endpointModulePackage, "dependencies.go")] = dependencies
+
 	return &BuildResult{
 		Files: ret,
 		Spec:  endpointSpecs,
 	}, nil
 }
 
+// InputFiles returns the thrift files and custom workflows of the endpoints
+// and the schemas of the middlewares they can use
+func (g *EndpointGenerator) InputFiles(instance *ModuleInstance) []string {
+	files := []string{}
+	for _, midSpec := range g.packageHelper.MiddlewareSpecs() {
+		files = append(files, midSpec.SchemaFile)
+		files = append(files, packageGoFiles(
+			midSpec.Path, instance.BaseDirectory,
+		)...)
+	}
+
+	endpointConfig, err := readEndpointConfig(instance.JSONFileRaw)
+	if err != nil {
+		return files
+	}
+	for _, fileName := range endpointConfig.Config.Endpoints {
+		bytes, err := ioutil.ReadFile(filepath.Join(
+			instance.BaseDirectory, instance.Directory, fileName,
+		))
+		if err != nil {
+			continue
+		}
+		var endpoint struct {
+			ThriftFile         string `json:"thriftFile"`
+			WorkflowImportPath string `json:"workflowImportPath"`
+		}
+		if err := json.Unmarshal(bytes, &endpoint); err != nil ||
+			endpoint.ThriftFile == "" {
+			continue
+		}
+		files = append(files, thriftFileClosure(filepath.Join(
+			g.packageHelper.ThriftIDLPath(), endpoint.ThriftFile,
+		))...)
+		if endpoint.WorkflowImportPath != "" {
+			files = append(files, packageGoFiles(
+				endpoint.WorkflowImportPath, instance.BaseDirectory,
+			)...)
+		}
+	}
+	return files
+}
+
+// packageGoFiles returns the go files of a package the generated code
+// depends on, such as a middleware or a custom workflow.
+func packageGoFiles(importPath string, srcDir string) []string {
+	pkg, err := build.Import(importPath, srcDir, build.FindOnly)
+	if err != nil {
+		return nil
+	}
+	files, _ := filepath.Glob(filepath.Join(pkg.Dir, "*.go"))
+	return files
+}
+
+// endpointModulePackage is the package, relative to the generated package of
+// an endpoint module, of the dependencies struct of the endpoints.
+const endpointModulePackage = "module"
+
+// endpointDependencies returns the module instance of the dependencies
+// struct of an endpoint module. The struct is generated in a package of its
+// own, so the custom workflows of the endpoints can use it, and only holds
+// the clients declared in the dependencies of the module.
+func endpointDependencies(instance *ModuleInstance) *ModuleInstance {
+	packageInfo := *instance.PackageInfo
+	packageInfo.PackageName = endpointModulePackage
+	packageInfo.GeneratedPackagePath = path.Join(
+		packageInfo.GeneratedPackagePath, endpointModulePackage,
+	)
+
+	dependencies := *instance
+	dependencies.PackageInfo = &packageInfo
+	dependencies.ResolvedDependencies = map[string][]*ModuleInstance{
+		"client": instance.ResolvedDependencies["client"],
+	}
+	return &dependencies
+}
+
+// validateWorkflowClients verifies that a custom workflow package only uses
+// the clients declared in the dependencies of the endpoint module, and does
+// not reach for the clients of the whole gateway.
+func validateWorkflowClients(
+	importPath string,
+	instance *ModuleInstance,
+	packageHelper *PackageHelper,
+) error {
+	pkg, err := build.Import(importPath, instance.BaseDirectory, build.FindOnly)
+	if err != nil {
+		return errors.Wrapf(
+			err, "Cannot find workflow package %s", importPath,
+		)
+	}
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, pkg.Dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return errors.Wrapf(
+			err, "Cannot parse workflow package %s", importPath,
+		)
+	}
+
+	declared := map[string]bool{}
+	for _, client := range instance.ResolvedDependencies["client"] {
+		declared[client.PackageInfo.QualifiedInstanceName] = true
+	}
+	clientsPackage := packageHelper.GoGatewayPackageName() + "/clients"
+
+	fileNames := []string{}
+	files := map[string]*ast.File{}
+	for _, p := range pkgs {
+		for fileName, file := range p.Files {
+			fileNames = append(fileNames, fileName)
+			files[fileName] = file
+		}
+	}
+	sort.Strings(fileNames)
+
+	var diags Diagnostics
+	for _, fileName := range fileNames {
+		file := files[fileName]
+		for _, spec := range file.Imports {
+			if strings.Trim(spec.Path.Value, `"`) == clientsPackage {
+				diags = append(diags, newDiagnostic(
+					instance.configPath(), "dependencies.client",
+					"%s: workflow imports %s, it must use the clients "+
+						"declared in the endpoint dependencies",
+					fset.Position(spec.Pos()), clientsPackage,
+				))
+			}
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			sel, ok := node.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			clients, ok := sel.X.(*ast.SelectorExpr)
+			if !ok || clients.Sel.Name != "Clients" || declared[sel.Sel.Name] {
+				return true
+			}
+			diags = append(diags, newDiagnostic(
+				instance.configPath(), "dependencies.client",
+				"%s: workflow uses client %q, which is not declared in "+
+					"the endpoint dependencies",
+				fset.Position(sel.Sel.Pos()), sel.Sel.Name,
+			))
+			return true
+		})
+	}
+	return diags.Err()
+}
+
+// endpointMiddlewareSpecs returns the middlewares available to the
+// endpoints of an instance, the middlewares of the middleware config and the
+// middleware modules the instance depends on.
+func endpointMiddlewareSpecs(
+	instance *ModuleInstance,
+	packageHelper *PackageHelper,
+) (map[string]*MiddlewareSpec, error) {
+	midSpecs := map[string]*MiddlewareSpec{}
+	for name, midSpec := range packageHelper.MiddlewareSpecs() {
+		midSpecs[name] = midSpec
+	}
+	for _, middleware := range instance.ResolvedDependencies["middleware"] {
+		midSpec, err := NewMiddlewareModuleSpec(middleware)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"Error reading middleware %q of endpoint %q",
+				middleware.InstanceName,
+				instance.InstanceName,
+			)
+		}
+		midSpecs[midSpec.Name] = midSpec
+	}
+	return midSpecs, nil
+}
+
 func (g *EndpointGenerator) generateEndpointFile(
 	e *EndpointSpec, instance *ModuleInstance, out map[string][]byte,
 ) error {
@@ -759,7 +1069,19 @@ func (g *EndpointGenerator) generateEndpointFile(
 		)
 	}
 
-	includedPackages := m.IncludedPackages
+	dependencies := endpointDependencies(instance)
+	includedPackages := make([]GoPackageImport, len(m.IncludedPackages))
+	copy(includedPackages, m.IncludedPackages)
+	includedPackages = append(includedPackages, GoPackageImport{
+		PackageName: dependencies.PackageInfo.GeneratedPackagePath,
+		AliasName:   endpointModulePackage,
+	})
+	clientDependencies := []string{}
+	for _, client := range dependencies.ResolvedDependencies["client"] {
+		clientDependencies = append(
+			clientDependencies, client.PackageInfo.QualifiedInstanceName,
+		)
+	}
 	if e.WorkflowImportPath != "" {
 		includedPackages = append(includedPackages, GoPackageImport{
 			PackageName: e.WorkflowImportPath,
@@ -781,7 +1103,6 @@ func (g *EndpointGenerator) generateEndpointFile(
 		clientName = e.ClientSpec.ClientName
 	}
 
-	// TODO: http client needs to support multiple thrift services
 	meta := &EndpointMeta{
 		GatewayPackageName: g.packageHelper.GoGatewayPackageName(),
 		PackageName:        m.PackageName,
@@ -791,6 +1112,7 @@ func (g *EndpointGenerator) generateEndpointFile(
 		ReqHeaderMapKeys:   e.ReqHeaderMapKeys,
 		ResHeaderMap:       e.ResHeaderMap,
 		ResHeaderMapKeys:   e.ResHeaderMapKeys,
+		ClientDependencies: clientDependencies,
 		ClientID:           clientID,
 		ClientName:         clientName,
 		ClientMethodName:   e.ClientMethod,
@@ -825,7 +1147,8 @@ func (g *EndpointGenerator) generateEndpointFile(
 }
 
 func (g *EndpointGenerator) generateEndpointTestFile(
-	e *EndpointSpec, instance *ModuleInstance, out map[string][]byte,
+	e *EndpointSpec, instance *ModuleInstance, clientSpecs []*ClientSpec,
+	out map[string][]byte,
 ) error {
 	m := e.ModuleSpec
 	methodName := e.ThriftMethodName
@@ -843,97 +1166,30 @@ func (g *EndpointGenerator) generateEndpointTestFile(
 		)
 	}
 
-	// Read test configurations
-	testConfigPath := e.EndpointTestConfigPath()
-
-	var testStubs []TestStub
-	file, err := ioutil.ReadFile(testConfigPath)
+	testStubs, err := readTestStubs(e)
 	if err != nil {
-		// If the test file does not exist then skip test generation.
-		if os.IsNotExist(err) {
-			return nil
-		}
-
-		return errors.Wrapf(err,
-			"Error reading endpoint test config for service %q, method %q",
-			serviceName, method.Name)
+		return err
 	}
-	err = json.Unmarshal(file, &testStubs)
-	if err != nil {
-		return errors.Wrapf(err,
-			"Error parsing test config file.")
+	// Skip test generation if the endpoint has no fixtures.
+	if len(testStubs) == 0 {
+		return nil
 	}
 
-	for i := 0; i < len(testStubs); i++ {
-		testStub := &testStubs[i]
-		testStub.EndpointRequestString, err = jsonMarshal(
-			testStub.EndpointRequest)
-		if err != nil {
-			return errors.Wrapf(err,
-				"Error parsing JSON in test config.")
-		}
-		testStub.EndpointResponseString, err = jsonMarshal(
-			testStub.EndpointResponse)
-		if err != nil {
-			return errors.Wrapf(err,
-				"Error parsing JSON in test config.")
-		}
-		for j := 0; j < len(testStub.ClientStubs); j++ {
-			clientStub := &testStub.ClientStubs[j]
-			clientStub.ClientRequestString, err = jsonMarshal(
-				clientStub.ClientRequest)
-			if err != nil {
-				return errors.Wrapf(err,
-					"Error parsing JSON in test config.")
-			}
-			clientStub.ClientResponseString, err = jsonMarshal(
-				clientStub.ClientResponse)
-			if err != nil {
-				return errors.Wrapf(err,
-					"Error parsing JSON in test config.")
-			}
-			// Build canonical key list to keep templates in order
-			// when comparing to golden files.
-			clientStub.ClientReqHeaderKeys = make(
-				[]string,
-				len(clientStub.ClientReqHeaders))
-			i := 0
-			for k := range clientStub.ClientReqHeaders {
-				clientStub.ClientReqHeaderKeys[i] = k
-				i++
-			}
-			sort.Strings(clientStub.ClientReqHeaderKeys)
-			clientStub.ClientResHeaderKeys = make(
-				[]string,
-				len(clientStub.ClientResHeaders))
-			i = 0
-			for k := range clientStub.ClientResHeaders {
-				clientStub.ClientResHeaderKeys[i] = k
-				i++
+	// Client stubs of tchannel client tests are not resolved against the
+	// http clients.
+	var httpClients []*ClientSpec
+	if e.WorkflowType != "tchannelClient" {
+		httpClients = []*ClientSpec{}
+		for _, clientSpec := range clientSpecs {
+			if clientSpec.ClientType == "http" {
+				httpClients = append(httpClients, clientSpec)
 			}
-			sort.Strings(clientStub.ClientResHeaderKeys)
-
-		}
-		// Build canonical key list to keep templates in order
-		// when comparing to golden files.
-		testStub.EndpointReqHeaderKeys = make(
-			[]string,
-			len(testStub.EndpointReqHeaders))
-		i := 0
-		for k := range testStub.EndpointReqHeaders {
-			testStub.EndpointReqHeaderKeys[i] = k
-			i++
 		}
-		sort.Strings(testStub.EndpointReqHeaderKeys)
-		testStub.EndpointResHeaderKeys = make(
-			[]string,
-			len(testStub.EndpointResHeaders))
-		i = 0
-		for k := range testStub.EndpointResHeaders {
-			testStub.EndpointResHeaderKeys[i] = k
-			i++
+	}
+	for i := 0; i < len(testStubs); i++ {
+		if err := resolveTestStub(&testStubs[i], method, httpClients); err != nil {
+			return err
 		}
-		sort.Strings(testStub.EndpointResHeaderKeys)
 	}
 
 	meta := &EndpointTestMeta{
@@ -976,6 +1232,62 @@ func (g *EndpointGenerator) generateEndpointTestFile(
 	return nil
 }
 
+/*
+ * Middleware Generator
+ */
+
+// MiddlewareGenerator generates the dependencies struct of a middleware
+// module. The middlewares a middleware depends on are not part of the
+// struct, they must run before it on the endpoints using it.
+type MiddlewareGenerator struct {
+	templates     *Template
+	packageHelper *PackageHelper
+}
+
+// Generate returns the middleware build result, which contains the
+// dependencies struct, if the middleware depends on clients, and the
+// middleware spec
+func (g *MiddlewareGenerator) Generate(
+	instance *ModuleInstance,
+) (*BuildResult, error) {
+	spec, err := NewMiddlewareModuleSpec(instance)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Error initializing MiddlewareSpec for %q",
+			instance.InstanceName,
+		)
+	}
+
+	clientsInstance := *instance
+	clientsInstance.ResolvedDependencies = map[string][]*ModuleInstance{}
+	if clients := instance.ResolvedDependencies["client"]; len(clients) > 0 {
+		clientsInstance.ResolvedDependencies["client"] = clients
+	}
+	dependencies, err := GenerateDependencyStruct(
+		&clientsInstance,
+		g.packageHelper,
+		g.templates,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Error generating dependencies struct for %q %q",
+			instance.ClassName,
+			instance.InstanceName,
+		)
+	}
+
+	files := map[string][]byte{}
+	if dependencies != nil {
+		files["dependencies.go"] = dependencies
+	}
+	return &BuildResult{
+		Files: files,
+		Spec:  spec,
+	}, nil
+}
+
 /*
  * Gateway Service Generator
  */
@@ -987,6 +1299,15 @@ type GatewayServiceGenerator struct {
 	packageHelper *PackageHelper
 }
 
+// InputFiles returns the default config copied into the service
+func (generator *GatewayServiceGenerator) InputFiles(
+	instance *ModuleInstance,
+) []string {
+	return []string{
+		path.Join(getDirName(), "..", "config", "production.json"),
+	}
+}
+
 // Generate returns the gateway build result, which contains the service and
 // service test main files, and no spec
 func (generator *GatewayServiceGenerator) Generate(
@@ -21,10 +21,14 @@
 package codegen
 
 import (
+	"encoding/json"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -254,6 +258,181 @@ func TestExampleService(t *testing.T) {
 	}
 }
 
+type TestFilesGenerator struct {
+	calls int
+	files map[string][]byte
+}
+
+func (g *TestFilesGenerator) Generate(
+	instance *ModuleInstance,
+) (*BuildResult, error) {
+	g.calls++
+	return &BuildResult{
+		Files: g.files,
+		Spec: &TestClientSpec{
+			Info: instance.InstanceName,
+		},
+	}, nil
+}
+
+func TestIncrementalBuild(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "zanzibar-incremental")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %s", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(baseDir)
+	}()
+	buildDir := path.Join(baseDir, "build")
+
+	writeConfig := func(file string, content string) {
+		if err := writeFile(path.Join(baseDir, file), []byte(content)); err != nil {
+			t.Fatalf("Unexpected error writing %s: %s", file, err)
+		}
+	}
+	writeConfig("clients/example/client-config.json", `{
+		"name": "example", "type": "http", "dependencies": {}
+	}`)
+	writeConfig("endpoints/health/endpoint-config.json", `{
+		"name": "health", "type": "http",
+		"dependencies": {"client": ["example"]}
+	}`)
+
+	clientGenerator := &TestFilesGenerator{
+		files: map[string][]byte{"client.txt": []byte("client")},
+	}
+	endpointGenerator := &TestFilesGenerator{
+		files: map[string][]byte{"endpoint.txt": []byte("endpoint")},
+	}
+
+	moduleSystem := NewModuleSystem()
+	moduleSystem.EnableIncrementalBuild("test")
+	if err := moduleSystem.RegisterClass("client", ModuleClass{
+		ClassType: MultiModule,
+		Directory: "clients",
+	}); err != nil {
+		t.Fatalf("Unexpected error registering client class: %s", err)
+	}
+	if err := moduleSystem.RegisterClassType(
+		"client", "http", clientGenerator,
+	); err != nil {
+		t.Fatalf("Unexpected error registering client class type: %s", err)
+	}
+	if err := moduleSystem.RegisterClass("endpoint", ModuleClass{
+		ClassType:         MultiModule,
+		ClassDependencies: []string{"client"},
+		Directory:         "endpoints",
+	}); err != nil {
+		t.Fatalf("Unexpected error registering endpoint class: %s", err)
+	}
+	if err := moduleSystem.RegisterClassType(
+		"endpoint", "http", endpointGenerator,
+	); err != nil {
+		t.Fatalf("Unexpected error registering endpoint class type: %s", err)
+	}
+
+	build := func() map[string][]*ModuleInstance {
+		instances, err := moduleSystem.GenerateBuild(
+			"github.com/uber/zanzibar/codegen/test-service",
+			baseDir,
+			buildDir,
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error generating build %s", err)
+		}
+		return instances
+	}
+
+	build()
+	build()
+	if clientGenerator.calls != 1 || endpointGenerator.calls != 1 {
+		t.Errorf(
+			"Expected unchanged modules to be skipped but generated "+
+				"client %d times and endpoint %d times",
+			clientGenerator.calls,
+			endpointGenerator.calls,
+		)
+	}
+
+	writeConfig("endpoints/health/endpoint-config.json", `{
+		"name": "health", "type": "http", "config": {"rateLimit": 1},
+		"dependencies": {"client": ["example"]}
+	}`)
+	endpointGenerator.files = map[string][]byte{
+		"other.txt": []byte("other"),
+	}
+	instances := build()
+
+	if endpointGenerator.calls != 2 {
+		t.Errorf("Expected changed endpoint to be regenerated")
+	}
+	endpoint := instances["endpoint"][0]
+	clientSpec, ok := endpoint.ResolvedDependencies["client"][0].
+		GeneratedSpec().(*TestClientSpec)
+	if !ok || clientSpec.Info != "example" {
+		t.Errorf("Expected skipped client spec to be restored for endpoint")
+	}
+	if _, err := os.Stat(path.Join(buildDir, "endpoints/health/endpoint.txt")); !os.IsNotExist(err) {
+		t.Errorf("Expected orphaned endpoint.txt to be removed")
+	}
+	if _, err := os.Stat(path.Join(buildDir, "endpoints/health/other.txt")); err != nil {
+		t.Errorf("Expected other.txt to be generated: %s", err)
+	}
+
+	if err := os.Remove(path.Join(buildDir, "clients/example/client.txt")); err != nil {
+		t.Fatalf("Unexpected error removing client.txt: %s", err)
+	}
+	build()
+	if _, err := os.Stat(path.Join(buildDir, "clients/example/client.txt")); err != nil {
+		t.Errorf("Expected removed output to be regenerated: %s", err)
+	}
+
+	dryRun := NewDryRun()
+	moduleSystem.EnableDryRun(dryRun)
+	build()
+	diffs, err := dryRun.Diff()
+	if err != nil {
+		t.Fatalf("Unexpected error diffing dry run: %s", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("Expected no diff for an up to date build but got %d", len(diffs))
+	}
+
+	endpointGenerator.files = map[string][]byte{
+		"endpoint.txt": []byte("endpoint"),
+	}
+	clientGenerator.files = map[string][]byte{
+		"client.txt": []byte("changed client"),
+	}
+	dryRun = NewDryRun()
+	moduleSystem.EnableDryRun(dryRun)
+	build()
+	diffs, err = dryRun.Diff()
+	if err != nil {
+		t.Fatalf("Unexpected error diffing dry run: %s", err)
+	}
+	paths := []string{}
+	for _, diff := range diffs {
+		rel, _ := filepath.Rel(buildDir, diff.Path)
+		paths = append(paths, rel)
+	}
+	expectedPaths := []string{
+		"clients/example/client.txt",
+		"endpoints/health/endpoint.txt",
+		"endpoints/health/other.txt",
+	}
+	if !reflect.DeepEqual(paths, expectedPaths) {
+		t.Errorf("Expected diffs for %v but got %v", expectedPaths, paths)
+	}
+	if len(diffs) == 3 && !strings.Contains(diffs[0].Diff, "+changed client") {
+		t.Errorf("Expected unified diff of client.txt but got %q", diffs[0].Diff)
+	}
+	content, err := ioutil.ReadFile(path.Join(buildDir, "clients/example/client.txt"))
+	if err != nil || string(content) != "client" {
+		t.Errorf("Expected dry run to leave client.txt unchanged")
+	}
+}
+
 func getTestDirName() string {
 	_, file, _, _ := runtime.Caller(0)
 	dirname := filepath.Dir(file)
@@ -450,3 +629,243 @@ func compareInstances(
 		)
 	}
 }
+
+func TestValidateWorkflowClients(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "zanzibar-workflow")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %s", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(baseDir)
+	}()
+
+	err = writeFile(path.Join(baseDir, "workflow", "workflow.go"), []byte(`
+package workflow
+
+import "example.com/gateway/build/clients"
+
+type Endpoint struct {
+	All     *clients.Clients
+	Clients *struct{ Bar, Baz interface{} }
+}
+
+func (w Endpoint) Handle() {
+	_, _ = w.Clients.Bar, w.Clients.Baz
+}
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error writing workflow: %s", err)
+	}
+
+	instance := &ModuleInstance{
+		ClassName:     "endpoint",
+		InstanceName:  "bar",
+		BaseDirectory: baseDir,
+		Directory:     "endpoints/bar",
+		JSONFileName:  "endpoint-config.json",
+		ResolvedDependencies: map[string][]*ModuleInstance{
+			"client": {{
+				InstanceName: "bar",
+				PackageInfo:  &PackageInfo{QualifiedInstanceName: "Bar"},
+			}},
+		},
+	}
+	h := &PackageHelper{goGatewayNamespace: "example.com/gateway/build"}
+
+	err = validateWorkflowClients("./workflow", instance, h)
+	diags, ok := err.(Diagnostics)
+	if !ok {
+		t.Fatalf("Expected diagnostics, got %v", err)
+	}
+	if len(diags) != 2 {
+		t.Fatalf("Expected 2 diagnostics, got %v", diags)
+	}
+	for i, expected := range []string{
+		"workflow imports example.com/gateway/build/clients",
+		`workflow uses client "Baz"`,
+	} {
+		if diags[i].Location != "dependencies.client" ||
+			!strings.Contains(diags[i].Message, expected) {
+			t.Errorf("Unexpected diagnostic %q, expected %q", diags[i], expected)
+		}
+	}
+}
+
+func TestGatewayConfigSchema(t *testing.T) {
+	endpoint := func(name string, raw string) *ModuleInstance {
+		return &ModuleInstance{
+			ClassName:     "endpoint",
+			InstanceName:  name,
+			BaseDirectory: "/gateway",
+			Directory:     "endpoints/" + name,
+			JSONFileName:  "endpoint-config.json",
+			JSONFileRaw:   []byte(raw),
+		}
+	}
+	clientSpecs := map[string]*ClientSpec{
+		"bar": {
+			ClientID:   "bar",
+			ClientType: "http",
+			JSONFile:   "/gateway/clients/bar/client-config.json",
+		},
+	}
+
+	schema, err := gatewayConfigSchema(map[string][]*ModuleInstance{
+		"endpoint": {
+			endpoint("bar", `{"configSchema": {"endpoints.bar.limit": "integer"}}`),
+			endpoint("baz", `{}`),
+		},
+	}, clientSpecs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := map[string]string{
+		"clients.bar.ip":      "string",
+		"clients.bar.port":    "integer",
+		"endpoints.bar.limit": "integer",
+	}
+	if !reflect.DeepEqual(schema, expected) {
+		t.Errorf("Expected schema %v but got %v", expected, schema)
+	}
+
+	_, err = gatewayConfigSchema(map[string][]*ModuleInstance{
+		"endpoint": {
+			endpoint("bar", `{"configSchema": {"clients.bar.port": "string"}}`),
+			endpoint("baz", `{"configSchema": {"endpoints.baz.limit": "int"}}`),
+		},
+	}, clientSpecs)
+	diags, ok := err.(Diagnostics)
+	if !ok {
+		t.Fatalf("Expected diagnostics, got %v", err)
+	}
+	if len(diags) != 2 {
+		t.Fatalf("Expected 2 diagnostics, got %v", diags)
+	}
+	for i, expected := range []string{
+		"configSchema.clients.bar.port: declared as string but as integer",
+		`configSchema.endpoints.baz.limit: unknown type "int"`,
+	} {
+		if !strings.Contains(diags[i].Error(), expected) {
+			t.Errorf("Unexpected diagnostic %q, expected %q", diags[i], expected)
+		}
+	}
+}
+
+func TestEndpointInputFilesIncludeMiddlewares(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	gatewayDir := filepath.Join(filepath.Dir(file), "..", "examples", "example-gateway")
+
+	generator := &EndpointGenerator{
+		packageHelper: &PackageHelper{
+			middlewareSpecs: map[string]*MiddlewareSpec{
+				"example": {
+					Name:       "example",
+					Path:       "github.com/uber/zanzibar/examples/example-gateway/middlewares/example",
+					SchemaFile: filepath.Join(gatewayDir, "middlewares/example/example_schema.json"),
+				},
+			},
+		},
+	}
+	files := generator.InputFiles(&ModuleInstance{
+		BaseDirectory: gatewayDir,
+		JSONFileRaw:   []byte(`{}`),
+	})
+
+	found := false
+	for _, inputFile := range files {
+		if filepath.Base(inputFile) == "example.go" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the middleware go files in the inputs, got %v", files)
+	}
+}
+
+func TestExampleThriftFileShas(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	gatewayDir := filepath.Join(filepath.Dir(file), "..", "examples", "example-gateway")
+
+	configs, err := filepath.Glob(filepath.Join(gatewayDir, "*", "*", "*.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error listing configs: %s", err)
+	}
+	checked := 0
+	for _, config := range configs {
+		var spec struct {
+			ThriftFile    string `json:"thriftFile"`
+			ThriftFileSha string `json:"thriftFileSha"`
+			Config        struct {
+				ThriftFile    string `json:"thriftFile"`
+				ThriftFileSha string `json:"thriftFileSha"`
+			} `json:"config"`
+		}
+		bytes, err := ioutil.ReadFile(config)
+		if err != nil {
+			t.Fatalf("Unexpected error reading %s: %s", config, err)
+		}
+		if err := json.Unmarshal(bytes, &spec); err != nil {
+			continue
+		}
+		if spec.ThriftFile == "" {
+			spec.ThriftFile = spec.Config.ThriftFile
+			spec.ThriftFileSha = spec.Config.ThriftFileSha
+		}
+		if spec.ThriftFile == "" {
+			continue
+		}
+
+		sha, err := hashFile(filepath.Join(gatewayDir, "idl", spec.ThriftFile))
+		if err != nil {
+			t.Fatalf("Unexpected error hashing %s: %s", spec.ThriftFile, err)
+		}
+		if spec.ThriftFileSha != sha {
+			t.Errorf(
+				"Expected thriftFileSha %s in %s but found %s",
+				sha, config, spec.ThriftFileSha,
+			)
+		}
+		checked++
+	}
+	if checked == 0 {
+		t.Errorf("Expected example configs with thrift files")
+	}
+}
+
+func TestFindExposedMethod(t *testing.T) {
+	normal := &MethodSpec{Name: "normal"}
+	clientSpec := &ClientSpec{
+		ClientID: "bar",
+		ModuleSpec: &ModuleSpec{Services: []*ServiceSpec{
+			{Name: "Bar", Methods: []*MethodSpec{normal}},
+			{Name: "Bar2", Methods: []*MethodSpec{{Name: "normal"}}},
+		}},
+		ExposedMethods: map[string]string{
+			"Normal":      "Bar::normal",
+			"NormalAlias": "Bar::normal",
+			"Other":       "Bar2::normal",
+		},
+	}
+
+	method, err := findExposedMethod(clientSpec, "normalAlias")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if method != normal {
+		t.Errorf("Expected Bar::normal but found %v", method)
+	}
+
+	method, err = findExposedMethod(clientSpec, "missing")
+	if err != nil || method != nil {
+		t.Errorf("Expected no method but found %v, %v", method, err)
+	}
+
+	for i := 0; i < 10; i++ {
+		_, err = findExposedMethod(clientSpec, "normal")
+		expected := `method "normal" of client "bar" is ambiguous, ` +
+			`it matches Bar::normal, Bar2::normal`
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q but found %v", expected, err)
+		}
+	}
+}
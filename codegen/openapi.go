@@ -0,0 +1,471 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package codegen
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/thriftrw/ast"
+	"go.uber.org/thriftrw/compile"
+)
+
+// openAPIVersion is the OpenAPI specification version of the documents.
+const openAPIVersion = "3.0.0"
+
+// OpenAPIDocument is an OpenAPI 3 description of the http endpoints of a
+// gateway.
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                       `json:"components"`
+}
+
+// OpenAPIInfo describes the gateway.
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPIComponents holds the schemas of the thrift structs.
+type OpenAPIComponents struct {
+	Schemas map[string]*OpenAPISchema `json:"schemas"`
+}
+
+// OpenAPIOperation describes an endpoint.
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Tags        []string                    `json:"tags"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter is a path, query or header parameter.
+type OpenAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Schema   *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIRequestBody describes the request body and its content types.
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIMediaType is the schema of a body in a content type.
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIResponse describes a response status code.
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Headers     map[string]*OpenAPIHeader    `json:"headers,omitempty"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIHeader describes a response header.
+type OpenAPIHeader struct {
+	Required bool           `json:"required"`
+	Schema   *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema is the subset of json schema used for thrift types.
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	UniqueItems          bool                      `json:"uniqueItems,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+}
+
+// requestContentTypes maps the request encodings to their content type.
+var requestContentTypes = map[string]string{
+	"json":      "application/json",
+	"form":      "application/x-www-form-urlencoded",
+	"multipart": "multipart/form-data",
+}
+
+// thriftContentTypes are the thrift protocols struct bodies may also be
+// encoded with, see runtime.ThriftBinaryContentType.
+var thriftContentTypes = []string{
+	"application/x-thrift",
+	"application/vnd.apache.thrift.compact",
+}
+
+// isStruct returns true if struct bodies of the type may be thrift encoded.
+func isStruct(spec compile.TypeSpec) bool {
+	_, ok := compile.RootTypeSpec(spec).(*compile.StructSpec)
+	return ok
+}
+
+// bodyContent describes a body of the schema in the content type, and in
+// the thrift content types if thrift is true.
+func bodyContent(
+	contentType string, thrift bool, schema *OpenAPISchema,
+) map[string]*OpenAPIMediaType {
+	content := map[string]*OpenAPIMediaType{
+		contentType: {Schema: schema},
+	}
+	if thrift {
+		for _, thriftType := range thriftContentTypes {
+			content[thriftType] = &OpenAPIMediaType{Schema: schema}
+		}
+	}
+	return content
+}
+
+// openAPIBuilder collects the component schemas of the thrift structs
+// referenced by the endpoints.
+type openAPIBuilder struct {
+	schemas map[string]*OpenAPISchema
+	// names maps the thrift structs to their schema name.
+	names map[*compile.StructSpec]string
+	// files maps the schema names to the thrift file of the struct.
+	files map[string]string
+}
+
+// NewOpenAPIDocument describes the http endpoints of the gateway.
+func NewOpenAPIDocument(
+	gatewayName string, endpoints map[string]*EndpointSpec,
+) (*OpenAPIDocument, error) {
+	doc := &OpenAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info: OpenAPIInfo{
+			Title:   gatewayName,
+			Version: "1.0.0",
+		},
+		Paths: map[string]map[string]*OpenAPIOperation{},
+	}
+	b := &openAPIBuilder{
+		schemas: map[string]*OpenAPISchema{},
+		names:   map[*compile.StructSpec]string{},
+		files:   map[string]string{},
+	}
+
+	keys := make([]string, 0, len(endpoints))
+	for key := range endpoints {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		espec := endpoints[key]
+		if espec.EndpointType != "http" {
+			continue
+		}
+		method := findMethod(
+			espec.ModuleSpec, espec.ThriftServiceName, espec.ThriftMethodName,
+		)
+		if method == nil {
+			return nil, errors.Errorf(
+				"Could not find serviceName %q + methodName %q in module",
+				espec.ThriftServiceName, espec.ThriftMethodName,
+			)
+		}
+
+		path := openAPIPath(method)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*OpenAPIOperation{}
+		}
+		doc.Paths[path][strings.ToLower(method.HTTPMethod)] = b.operation(
+			espec, method,
+		)
+	}
+
+	doc.Components.Schemas = b.schemas
+	return doc, nil
+}
+
+// openAPIPath converts the ":param" segments of a method path to "{param}".
+func openAPIPath(method *MethodSpec) string {
+	segments := make([]string, len(method.PathSegments))
+	for i, segment := range method.PathSegments {
+		if segment.Type == "param" {
+			segments[i] = "{" + segment.ParamName + "}"
+		} else {
+			segments[i] = segment.Text
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+func (b *openAPIBuilder) operation(
+	espec *EndpointSpec, method *MethodSpec,
+) *OpenAPIOperation {
+	funcSpec := method.CompiledThriftSpec
+	op := &OpenAPIOperation{
+		OperationID: espec.EndpointID + "." + espec.HandleID,
+		Tags:        []string{espec.EndpointID},
+		Responses:   map[string]*OpenAPIResponse{},
+	}
+
+	// refs are the arguments, and boxedRefs the fields of a boxed request
+	// struct, that are read from the path, query or headers instead of the
+	// body.
+	refs := map[string]bool{}
+	boxedRefs := map[string]bool{}
+	boxedPrefix := ""
+	if method.RequestBoxed {
+		boxedPrefix = "." + strings.Title(funcSpec.ArgsSpec[0].Name)
+	}
+	walkFieldGroups(compile.FieldGroup(funcSpec.ArgsSpec), func(
+		prefix string, field *compile.FieldSpec,
+	) bool {
+		ref := field.Annotations[antHTTPRef]
+		parts := strings.SplitN(ref, ".", 2)
+		if len(parts) != 2 {
+			return false
+		}
+		in := map[string]string{
+			"params":  "path",
+			"query":   "query",
+			"headers": "header",
+		}[parts[0]]
+		if in == "" || in == "path" && !hasPathParam(method, parts[1]) {
+			return false
+		}
+		switch {
+		case prefix == "":
+			refs[field.Name] = true
+		case prefix == boxedPrefix:
+			boxedRefs[field.Name] = true
+		}
+		op.Parameters = append(op.Parameters, &OpenAPIParameter{
+			Name:     parts[1],
+			In:       in,
+			Required: field.Required || in == "path",
+			Schema:   b.schema(field.Type),
+		})
+		return false
+	})
+	for _, header := range method.ReqHeaders {
+		if !hasParameter(op.Parameters, header, "header") {
+			op.Parameters = append(op.Parameters, &OpenAPIParameter{
+				Name:     header,
+				In:       "header",
+				Required: true,
+				Schema:   &OpenAPISchema{Type: "string"},
+			})
+		}
+	}
+	sort.SliceStable(op.Parameters, func(i, j int) bool {
+		return op.Parameters[i].In < op.Parameters[j].In
+	})
+
+	if method.RequestType != "" {
+		// Only json bodies fall back to the thrift protocols, unboxed
+		// arguments are sent as the thrift args struct.
+		thrift := method.RequestEncoding == "json"
+		var schema *OpenAPISchema
+		if method.RequestBoxed {
+			schema = b.schema(funcSpec.ArgsSpec[0].Type)
+			thrift = thrift && isStruct(funcSpec.ArgsSpec[0].Type)
+		} else {
+			schema = b.objectSchema(
+				compile.FieldGroup(funcSpec.ArgsSpec), refs,
+			)
+		}
+		contentType := requestContentTypes[method.RequestEncoding]
+		op.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content:  bodyContent(contentType, thrift, schema),
+		}
+		// Thrift bodies still carry the bound fields of a boxed struct.
+		if method.RequestBoxed && len(boxedRefs) > 0 {
+			argType := compile.RootTypeSpec(funcSpec.ArgsSpec[0].Type)
+			if argStruct, ok := argType.(*compile.StructSpec); ok {
+				op.RequestBody.Content[contentType] = &OpenAPIMediaType{
+					Schema: b.objectSchema(argStruct.Fields, boxedRefs),
+				}
+			}
+		}
+	}
+
+	success := &OpenAPIResponse{
+		Description: http.StatusText(method.OKStatusCode.Code),
+	}
+	if returnType := funcSpec.ResultSpec.ReturnType; returnType != nil {
+		success.Content = bodyContent(
+			"application/json", isStruct(returnType), b.schema(returnType),
+		)
+	}
+	for _, header := range method.ResHeaders {
+		if success.Headers == nil {
+			success.Headers = map[string]*OpenAPIHeader{}
+		}
+		success.Headers[header] = &OpenAPIHeader{
+			Required: true,
+			Schema:   &OpenAPISchema{Type: "string"},
+		}
+	}
+	op.Responses[strconv.Itoa(method.OKStatusCode.Code)] = success
+
+	for _, exception := range funcSpec.ResultSpec.Exceptions {
+		// Exceptions without a status are described by the default
+		// response, the first one wins.
+		code, ok := exception.Annotations[antHTTPStatus]
+		if !ok || code == "" {
+			code = "default"
+			if op.Responses[code] != nil {
+				continue
+			}
+		}
+		op.Responses[code] = &OpenAPIResponse{
+			Description: exception.Name,
+			Content: bodyContent(
+				"application/json", true, b.schema(exception.Type),
+			),
+		}
+	}
+
+	return op
+}
+
+func hasPathParam(method *MethodSpec, name string) bool {
+	for _, segment := range method.PathSegments {
+		if segment.Type == "param" && segment.ParamName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasParameter(params []*OpenAPIParameter, name, in string) bool {
+	for _, param := range params {
+		if param.In == in && strings.EqualFold(param.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// objectSchema returns the schema of a group of fields, skipping the fields
+// in the skip set.
+func (b *openAPIBuilder) objectSchema(
+	fields compile.FieldGroup, skip map[string]bool,
+) *OpenAPISchema {
+	schema := &OpenAPISchema{
+		Type:       "object",
+		Properties: map[string]*OpenAPISchema{},
+	}
+	for _, field := range fields {
+		if skip[field.Name] {
+			continue
+		}
+		schema.Properties[field.Name] = b.schema(field.Type)
+		if field.Required {
+			schema.Required = append(schema.Required, field.Name)
+		}
+	}
+	return schema
+}
+
+// schema returns the json schema of a thrift type, structs are referenced
+// as component schemas.
+func (b *openAPIBuilder) schema(spec compile.TypeSpec) *OpenAPISchema {
+	switch t := compile.RootTypeSpec(spec).(type) {
+	case *compile.BoolSpec:
+		return &OpenAPISchema{Type: "boolean"}
+	case *compile.I8Spec, *compile.I16Spec, *compile.I32Spec:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case *compile.I64Spec:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case *compile.DoubleSpec:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case *compile.StringSpec:
+		return &OpenAPISchema{Type: "string"}
+	case *compile.BinarySpec:
+		return &OpenAPISchema{Type: "string", Format: "byte"}
+	case *compile.EnumSpec:
+		names := make([]string, len(t.Items))
+		for i, item := range t.Items {
+			names[i] = item.Name
+		}
+		return &OpenAPISchema{Type: "string", Enum: names}
+	case *compile.ListSpec:
+		return &OpenAPISchema{Type: "array", Items: b.schema(t.ValueSpec)}
+	case *compile.SetSpec:
+		return &OpenAPISchema{
+			Type:        "array",
+			Items:       b.schema(t.ValueSpec),
+			UniqueItems: true,
+		}
+	case *compile.MapSpec:
+		return &OpenAPISchema{
+			Type:                 "object",
+			AdditionalProperties: b.schema(t.ValueSpec),
+		}
+	case *compile.StructSpec:
+		return &OpenAPISchema{Ref: "#/components/schemas/" + b.structName(t)}
+	}
+	return &OpenAPISchema{}
+}
+
+// structName registers the schema of a struct and returns its name, the
+// thrift file name qualifies the struct name.
+func (b *openAPIBuilder) structName(spec *compile.StructSpec) string {
+	if name, ok := b.names[spec]; ok {
+		return name
+	}
+
+	module := strings.TrimSuffix(filepath.Base(spec.File), ".thrift")
+	name := module + "." + spec.Name
+	for i := 2; b.files[name] != "" && b.files[name] != spec.File; i++ {
+		name = module + strconv.Itoa(i) + "." + spec.Name
+	}
+	b.names[spec] = name
+	b.files[name] = spec.File
+
+	schema := b.objectSchema(spec.Fields, nil)
+	if spec.Type == ast.UnionType {
+		schema.Required = nil
+	}
+	b.schemas[name] = schema
+	return name
+}
+
+// GenerateOpenAPIDocument returns the OpenAPI document of the gateway
+// endpoints as indented json.
+func (gateway *GatewaySpec) GenerateOpenAPIDocument() ([]byte, error) {
+	doc, err := NewOpenAPIDocument(gateway.gatewayName, gateway.EndpointModules)
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := json.MarshalIndent(doc, "", "\t")
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot serialize OpenAPI document")
+	}
+	return append(bytes, '\n'), nil
+}
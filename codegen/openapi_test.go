@@ -0,0 +1,245 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package codegen_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/zanzibar/codegen"
+)
+
+const openAPIThrift = `
+namespace java com.uber.zanzibar.items
+
+enum Kind { SMALL, LARGE }
+
+typedef i64 ID
+
+struct Item {
+	1: required string name
+	2: optional list<Item> children
+	3: optional map<string, double> prices
+	4: optional binary photo
+}
+
+struct Rename {
+	1: required ID id (zanzibar.http.ref = "params.id")
+	2: optional string token (zanzibar.http.ref = "headers.x-token")
+	3: required string name
+}
+
+exception NotFound {
+	1: required string message
+}
+
+exception Unavailable {
+	1: required string message
+}
+
+service Items {
+	Item get(
+		1: required ID id (zanzibar.http.ref = "params.id")
+		2: required Kind kind (zanzibar.http.ref = "query.kind")
+		3: optional string token (zanzibar.http.ref = "headers.x-token")
+		4: required set<string> fields
+	) throws (
+		1: NotFound notFound (zanzibar.http.status = "404")
+		2: Unavailable unavailable (zanzibar.http.status = "503")
+	) (
+		zanzibar.http.method = "POST"
+		zanzibar.http.path = "/items/:id"
+		zanzibar.http.status = "200"
+		zanzibar.http.reqHeaders = "x-caller"
+		zanzibar.http.resHeaders = "x-version"
+		zanzibar.http.req.encoding = "form"
+	)
+
+	void put(
+		1: required Item item
+	) (
+		zanzibar.http.method = "PUT"
+		zanzibar.http.path = "/items"
+		zanzibar.http.status = "204"
+	)
+
+	void rename(
+		1: required Rename rename
+	) (
+		zanzibar.http.method = "POST"
+		zanzibar.http.path = "/items/:id/name"
+		zanzibar.http.status = "204"
+		zanzibar.http.req.def = "true"
+	)
+}
+`
+
+func TestOpenAPIDocument(t *testing.T) {
+	m, err := newTempModuleSpec(t, openAPIThrift)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// NewModuleSpec requires exception statuses, specs compiled without
+	// annotations may still have exceptions without one.
+	for _, method := range m.Services[0].Methods {
+		if method.Name == "get" {
+			exceptions := method.CompiledThriftSpec.ResultSpec.Exceptions
+			delete(exceptions[1].Annotations, "zanzibar.http.status")
+		}
+	}
+
+	doc, err := codegen.NewOpenAPIDocument("items-gateway", map[string]*codegen.EndpointSpec{
+		"items::get": {
+			ModuleSpec:        m,
+			EndpointType:      "http",
+			EndpointID:        "items",
+			HandleID:          "get",
+			ThriftServiceName: "Items",
+			ThriftMethodName:  "get",
+		},
+		"items::rename": {
+			ModuleSpec:        m,
+			EndpointType:      "http",
+			EndpointID:        "items",
+			HandleID:          "rename",
+			ThriftServiceName: "Items",
+			ThriftMethodName:  "rename",
+		},
+		"items::put": {
+			ModuleSpec:        m,
+			EndpointType:      "http",
+			EndpointID:        "items",
+			HandleID:          "put",
+			ThriftServiceName: "Items",
+			ThriftMethodName:  "put",
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "items-gateway", doc.Info.Title)
+	op := doc.Paths["/items/{id}"]["post"]
+	if !assert.NotNil(t, op) {
+		return
+	}
+	assert.Equal(t, "items.get", op.OperationID)
+
+	assert.Equal(t, []*codegen.OpenAPIParameter{
+		{
+			Name: "x-token", In: "header",
+			Schema: &codegen.OpenAPISchema{Type: "string"},
+		},
+		{
+			Name: "x-caller", In: "header", Required: true,
+			Schema: &codegen.OpenAPISchema{Type: "string"},
+		},
+		{
+			Name: "id", In: "path", Required: true,
+			Schema: &codegen.OpenAPISchema{Type: "integer", Format: "int64"},
+		},
+		{
+			Name: "kind", In: "query", Required: true,
+			Schema: &codegen.OpenAPISchema{
+				Type: "string", Enum: []string{"SMALL", "LARGE"},
+			},
+		},
+	}, op.Parameters)
+
+	assert.Len(t, op.RequestBody.Content, 1, "form bodies are not thrift")
+	body := op.RequestBody.Content["application/x-www-form-urlencoded"]
+	if assert.NotNil(t, body) {
+		assert.Equal(t, &codegen.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*codegen.OpenAPISchema{
+				"fields": {
+					Type:        "array",
+					Items:       &codegen.OpenAPISchema{Type: "string"},
+					UniqueItems: true,
+				},
+			},
+			Required: []string{"fields"},
+		}, body.Schema)
+	}
+
+	ok := op.Responses["200"]
+	assert.Equal(t, "#/components/schemas/items.Item",
+		ok.Content["application/json"].Schema.Ref)
+	assert.Contains(t, ok.Headers, "x-version")
+	assert.Equal(t, "#/components/schemas/items.NotFound",
+		op.Responses["404"].Content["application/json"].Schema.Ref)
+	assert.NotContains(t, op.Responses, "")
+	assert.Equal(t, "#/components/schemas/items.Unavailable",
+		op.Responses["default"].Content["application/json"].Schema.Ref)
+	for _, contentType := range []string{
+		"application/x-thrift", "application/vnd.apache.thrift.compact",
+	} {
+		assert.Equal(t, "#/components/schemas/items.Item",
+			ok.Content[contentType].Schema.Ref)
+		assert.Equal(t, "#/components/schemas/items.NotFound",
+			op.Responses["404"].Content[contentType].Schema.Ref)
+	}
+
+	put := doc.Paths["/items"]["put"]
+	if assert.NotNil(t, put) {
+		assert.Equal(t, []string{
+			"application/json",
+			"application/vnd.apache.thrift.compact",
+			"application/x-thrift",
+		}, sortedContentTypes(put.RequestBody.Content))
+		assert.Nil(t, put.Responses["204"].Content)
+	}
+
+	rename := doc.Paths["/items/{id}/name"]["post"]
+	if assert.NotNil(t, rename) {
+		assert.Len(t, rename.Parameters, 2)
+		assert.Equal(t, &codegen.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*codegen.OpenAPISchema{
+				"name": {Type: "string"},
+			},
+			Required: []string{"name"},
+		}, rename.RequestBody.Content["application/json"].Schema)
+		assert.Equal(t, "#/components/schemas/items.Rename",
+			rename.RequestBody.Content["application/x-thrift"].Schema.Ref)
+	}
+
+	item := doc.Components.Schemas["items.Item"]
+	if assert.NotNil(t, item) {
+		assert.Equal(t, []string{"name"}, item.Required)
+		assert.Equal(t, "#/components/schemas/items.Item",
+			item.Properties["children"].Items.Ref)
+		assert.Equal(t, "double",
+			item.Properties["prices"].AdditionalProperties.Format)
+		assert.Equal(t, "byte", item.Properties["photo"].Format)
+	}
+}
+
+func sortedContentTypes(content map[string]*codegen.OpenAPIMediaType) []string {
+	types := make([]string, 0, len(content))
+	for contentType := range content {
+		types = append(types, contentType)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -47,6 +47,8 @@ type PackageHelper struct {
 	copyrightHeader string
 	// The middlewares available for the endpoints
 	middlewareSpecs map[string]*MiddlewareSpec
+	// The directory of the templates overriding the bundled templates
+	templateOverrideDir string
 }
 
 // NewPackageHelper creates a package helper.
@@ -58,6 +60,7 @@ func NewPackageHelper(
 	genCodePackage string,
 	targetGenDir string,
 	copyrightHeader string,
+	templateOverrideDir string,
 ) (*PackageHelper, error) {
 	genDir, err := filepath.Abs(targetGenDir)
 	if err != nil {
@@ -87,13 +90,14 @@ func NewPackageHelper(
 	}
 
 	p := &PackageHelper{
-		packageRoot:        packageRoot,
-		thriftRootDir:      path.Clean(thriftRootDir),
-		genCodePackage:     genCodePackage,
-		goGatewayNamespace: goGatewayNamespace,
-		targetGenDir:       genDir,
-		copyrightHeader:    copyrightHeader,
-		middlewareSpecs:    middlewareSpecs,
+		packageRoot:         packageRoot,
+		thriftRootDir:       path.Clean(thriftRootDir),
+		genCodePackage:      genCodePackage,
+		goGatewayNamespace:  goGatewayNamespace,
+		targetGenDir:        genDir,
+		copyrightHeader:     copyrightHeader,
+		middlewareSpecs:     middlewareSpecs,
+		templateOverrideDir: templateOverrideDir,
 	}
 	return p, nil
 }
@@ -108,6 +112,12 @@ func (p PackageHelper) MiddlewareSpecs() map[string]*MiddlewareSpec {
 	return p.middlewareSpecs
 }
 
+// TemplateOverrideDir returns the directory of the templates overriding the
+// bundled templates, empty if there is none
+func (p PackageHelper) TemplateOverrideDir() string {
+	return p.templateOverrideDir
+}
+
 // TypeImportPath returns the Go import path for types defined in a thrift file.
 func (p PackageHelper) TypeImportPath(thrift string) (string, error) {
 	if !strings.HasSuffix(thrift, ".thrift") {
@@ -188,6 +198,24 @@ func (p PackageHelper) TargetEndpointsRegisterPath() string {
 	return path.Join(p.targetGenDir, "endpoints", "register.go")
 }
 
+// TargetEndpointsOpenAPIPath returns where the go file embedding the OpenAPI
+// document of the gateway should be written to
+func (p PackageHelper) TargetEndpointsOpenAPIPath() string {
+	return path.Join(p.targetGenDir, "endpoints", "openapi.go")
+}
+
+// TargetOpenAPIPath returns where the OpenAPI document of the gateway
+// should be written to
+func (p PackageHelper) TargetOpenAPIPath() string {
+	return path.Join(p.targetGenDir, "openapi.json")
+}
+
+// TargetSDKPath returns where the go client of an endpoint group should be
+// written to
+func (p PackageHelper) TargetSDKPath(endpointID string) string {
+	return path.Join(p.targetGenDir, "sdk", endpointID, endpointID+".go")
+}
+
 // EndpointTestConfigPath returns the path for the endpoint test configs
 func (p PackageHelper) EndpointTestConfigPath(
 	serviceName, methodName string,
@@ -0,0 +1,321 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// GeneratorFactory creates the generator of a module class type defined in
+// the gateway config. The options are the "options" of the class type.
+type GeneratorFactory func(
+	t *Template, h *PackageHelper, options json.RawMessage,
+) (BuildGenerator, error)
+
+var (
+	generatorFactoriesMutex sync.Mutex
+	generatorFactories      = map[string]GeneratorFactory{}
+)
+
+// RegisterGenerator makes a generator available by name to the module
+// classes of gateway configs. It is meant to be called from the init
+// function of a codegen plugin and panics if the name is already taken.
+func RegisterGenerator(name string, factory GeneratorFactory) {
+	generatorFactoriesMutex.Lock()
+	defer generatorFactoriesMutex.Unlock()
+
+	if factory == nil {
+		panic(fmt.Sprintf("Generator %q must not be nil", name))
+	}
+	if _, ok := generatorFactories[name]; ok {
+		panic(fmt.Sprintf("Generator %q is already registered", name))
+	}
+	generatorFactories[name] = factory
+}
+
+func lookupGenerator(name string) GeneratorFactory {
+	generatorFactoriesMutex.Lock()
+	defer generatorFactoriesMutex.Unlock()
+
+	return generatorFactories[name]
+}
+
+// PluginClassConfig is a module class defined in the "moduleClasses" of the
+// gateway config.
+type PluginClassConfig struct {
+	// Name is the name of the class, used in the dependencies of modules.
+	Name string `json:"name"`
+	// Directory is the directory of the class relative to the config dir.
+	Directory string `json:"directory"`
+	// Type is "single" for a class with one module in its directory or
+	// "multi" for a class with a module per sub directory.
+	Type string `json:"type"`
+	// Dependencies are the classes the modules of the class can depend on.
+	Dependencies []string `json:"dependencies"`
+	// ClassTypes are the generators of the class by type.
+	ClassTypes map[string]*PluginClassTypeConfig `json:"classTypes"`
+}
+
+// PluginClassTypeConfig is the generator of a module class type, either a
+// registered generator or a set of templates.
+type PluginClassTypeConfig struct {
+	// Generator is the name of a registered generator.
+	Generator string `json:"generator"`
+	// Templates maps the files generated for each module to the templates
+	// rendering them, relative to the config dir.
+	Templates map[string]string `json:"templates"`
+	// Options are passed to the registered generator.
+	Options json.RawMessage `json:"options"`
+}
+
+// RegisterModuleClasses registers the module classes of the gateway config
+// and their class types in the module system. A class can only depend on
+// classes registered before it.
+func RegisterModuleClasses(
+	system *ModuleSystem,
+	h *PackageHelper,
+	configDirName string,
+	classes []*PluginClassConfig,
+) error {
+	tmpl, err := NewTemplateWithOverrides(h.TemplateOverrideDir())
+	if err != nil {
+		return err
+	}
+
+	for _, class := range classes {
+		var classType moduleClassType
+		switch class.Type {
+		case "single":
+			classType = SingleModule
+		case "multi", "":
+			classType = MultiModule
+		default:
+			return errors.Errorf(
+				"Module class %q has unknown type %q, expected single or multi",
+				class.Name, class.Type,
+			)
+		}
+
+		dependencies := class.Dependencies
+		if dependencies == nil {
+			dependencies = []string{}
+		}
+		if err := system.RegisterClass(class.Name, ModuleClass{
+			Directory:         class.Directory,
+			ClassType:         classType,
+			ClassDependencies: dependencies,
+		}); err != nil {
+			return errors.Wrapf(
+				err, "Error registering %s class", class.Name,
+			)
+		}
+
+		typeNames := make([]string, 0, len(class.ClassTypes))
+		for typeName := range class.ClassTypes {
+			typeNames = append(typeNames, typeName)
+		}
+		sort.Strings(typeNames)
+
+		for _, typeName := range typeNames {
+			generator, err := newClassTypeGenerator(
+				tmpl, h, configDirName, class.ClassTypes[typeName],
+			)
+			if err != nil {
+				return errors.Wrapf(
+					err,
+					"Error creating generator of %s class type %q",
+					class.Name, typeName,
+				)
+			}
+			if err := system.RegisterClassType(
+				class.Name, typeName, generator,
+			); err != nil {
+				return errors.Wrapf(
+					err,
+					"Error registering %s class type %q",
+					class.Name, typeName,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+func newClassTypeGenerator(
+	t *Template,
+	h *PackageHelper,
+	configDirName string,
+	config *PluginClassTypeConfig,
+) (BuildGenerator, error) {
+	if config == nil {
+		return nil, errors.Errorf("class type config must not be null")
+	}
+	if config.Generator != "" && len(config.Templates) > 0 {
+		return nil, errors.Errorf(
+			"class type config must have a generator or templates, not both",
+		)
+	}
+
+	if config.Generator == "" {
+		if len(config.Templates) == 0 {
+			return nil, errors.Errorf(
+				"class type config must have a generator or templates",
+			)
+		}
+		return NewTemplateGenerator(t, h, configDirName, config.Templates)
+	}
+
+	factory := lookupGenerator(config.Generator)
+	if factory == nil {
+		return nil, errors.Errorf(
+			"generator %q is not registered, is its plugin loaded?",
+			config.Generator,
+		)
+	}
+	return factory(t, h, config.Options)
+}
+
+/*
+ * Template Generator
+ */
+
+// TemplateGenerator generates the files of a module instance from templates
+// of the gateway, with the module instance as the template data. The
+// templates can use the templates of zanzibar. A dependencies.go file with
+// the dependencies struct is generated for modules with dependencies.
+type TemplateGenerator struct {
+	templates     *Template
+	packageHelper *PackageHelper
+	// files maps the generated files to their template names
+	files map[string]string
+	// templateFiles are the absolute paths of the templates
+	templateFiles []string
+}
+
+// NewTemplateGenerator creates a generator rendering the given templates,
+// which are mapped by generated file and relative to the config dir.
+func NewTemplateGenerator(
+	t *Template,
+	h *PackageHelper,
+	configDirName string,
+	templateFiles map[string]string,
+) (*TemplateGenerator, error) {
+	base, err := t.template.Clone()
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not clone templates")
+	}
+
+	g := &TemplateGenerator{
+		templates:     &Template{template: base},
+		packageHelper: h,
+		files:         map[string]string{},
+	}
+	for filePath, templateFile := range templateFiles {
+		absPath := filepath.Join(configDirName, templateFile)
+		g.files[filePath] = templateFile
+		if base.Lookup(templateFile) != nil {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(absPath)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "Could not read template %s", templateFile,
+			)
+		}
+		_, err = base.New(templateFile).Parse(string(content))
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "Could not parse template %s", templateFile,
+			)
+		}
+		g.templateFiles = append(g.templateFiles, absPath)
+	}
+	sort.Strings(g.templateFiles)
+	return g, nil
+}
+
+// Generate renders the templates of the module instance
+func (g *TemplateGenerator) Generate(
+	instance *ModuleInstance,
+) (*BuildResult, error) {
+	files := map[string][]byte{}
+
+	dependencies, err := GenerateDependencyStruct(
+		instance,
+		g.packageHelper,
+		g.templates,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Error generating dependencies struct for %q %q",
+			instance.ClassName,
+			instance.InstanceName,
+		)
+	}
+	if dependencies != nil {
+		files["dependencies.go"] = dependencies
+	}
+
+	for filePath, templateName := range g.files {
+		var content []byte
+		if filepath.Ext(filePath) == ".go" {
+			content, err = g.templates.execTemplate(
+				templateName, instance, g.packageHelper,
+			)
+		} else {
+			buffer := bytes.NewBuffer(nil)
+			err = g.templates.template.ExecuteTemplate(
+				buffer, templateName, instance,
+			)
+			content = buffer.Bytes()
+		}
+		if err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"Error executing template %s for %q %q",
+				templateName,
+				instance.ClassName,
+				instance.InstanceName,
+			)
+		}
+		files[filePath] = content
+	}
+
+	return &BuildResult{
+		Files: files,
+	}, nil
+}
+
+// InputFiles returns the templates of the generator, so that modules are
+// regenerated when they change
+func (g *TemplateGenerator) InputFiles(instance *ModuleInstance) []string {
+	return g.templateFiles
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package codegen_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/zanzibar/codegen"
+)
+
+type testCacheGenerator struct {
+	suffix string
+}
+
+func (g *testCacheGenerator) Generate(
+	instance *codegen.ModuleInstance,
+) (*codegen.BuildResult, error) {
+	return &codegen.BuildResult{
+		Files: map[string][]byte{
+			"cache.txt": []byte(instance.InstanceName + g.suffix),
+		},
+	}, nil
+}
+
+func init() {
+	codegen.RegisterGenerator("testCache", func(
+		t *codegen.Template, h *codegen.PackageHelper, options json.RawMessage,
+	) (codegen.BuildGenerator, error) {
+		var config struct {
+			Suffix string `json:"suffix"`
+		}
+		if err := json.Unmarshal(options, &config); err != nil {
+			return nil, err
+		}
+		return &testCacheGenerator{suffix: config.Suffix}, nil
+	})
+}
+
+func writeTestFiles(t *testing.T, baseDir string, files map[string]string) {
+	for name, content := range files {
+		filePath := filepath.Join(baseDir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+			t.Fatalf("Unexpected error creating dir of %s: %s", name, err)
+		}
+		if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error writing %s: %s", name, err)
+		}
+	}
+}
+
+func TestRegisterModuleClasses(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "zanzibar-plugin")
+	if !assert.NoError(t, err, "failed to create temp dir") {
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(baseDir)
+	}()
+	writeTestFiles(t, baseDir, map[string]string{
+		"templates/task.tmpl": "package {{.PackageInfo.PackageName}}\n\n" +
+			"// Name is the name of the task\n" +
+			"const Name = \"{{.InstanceName}}\"\n",
+		"templates/task.json.tmpl": `{"cache": "{{(index .ResolvedDependencies "cache" 0).InstanceName}}"}`,
+		"caches/users/cache-config.json": `{
+			"name": "users", "type": "memory", "dependencies": {}
+		}`,
+		"tasks/cleanup/task-config.json": `{
+			"name": "cleanup", "type": "cron",
+			"dependencies": {"cache": ["users"]}
+		}`,
+	})
+
+	packageHelper, err := codegen.NewPackageHelper(
+		"github.com/uber/zanzibar/examples/plugin-gateway",
+		baseDir,
+		"",
+		filepath.Join(baseDir, "idl"),
+		"github.com/uber/zanzibar/examples/plugin-gateway/build/gen-code",
+		filepath.Join(baseDir, "build"),
+		testCopyrightHeader,
+		"",
+	)
+	if !assert.NoError(t, err, "failed to create package helper") {
+		return
+	}
+
+	var classes []*codegen.PluginClassConfig
+	err = json.Unmarshal([]byte(`[{
+		"name": "cache",
+		"directory": "caches",
+		"classTypes": {
+			"memory": {"generator": "testCache", "options": {"suffix": "-cache"}}
+		}
+	}, {
+		"name": "task",
+		"directory": "tasks",
+		"type": "multi",
+		"dependencies": ["cache"],
+		"classTypes": {
+			"cron": {"templates": {
+				"task.go": "templates/task.tmpl",
+				"task.json": "templates/task.json.tmpl"
+			}}
+		}
+	}]`), &classes)
+	if !assert.NoError(t, err, "failed to parse module classes") {
+		return
+	}
+
+	moduleSystem := codegen.NewModuleSystem()
+	err = codegen.RegisterModuleClasses(
+		moduleSystem, packageHelper, baseDir, classes,
+	)
+	if !assert.NoError(t, err, "failed to register module classes") {
+		return
+	}
+
+	_, err = moduleSystem.GenerateBuild(
+		"github.com/uber/zanzibar/examples/plugin-gateway",
+		baseDir,
+		packageHelper.CodeGenTargetPath(),
+	)
+	if !assert.NoError(t, err, "failed to generate build") {
+		return
+	}
+
+	readBuildFile := func(name string) string {
+		content, err := ioutil.ReadFile(filepath.Join(baseDir, "build", name))
+		assert.NoError(t, err, "failed to read %s", name)
+		return string(content)
+	}
+	assert.Equal(t, "users-cache", readBuildFile("caches/users/cache.txt"))
+	assert.Contains(t, readBuildFile("tasks/cleanup/task.go"), `const Name = "cleanup"`)
+	assert.Equal(t, `{"cache": "users"}`, readBuildFile("tasks/cleanup/task.json"))
+	assert.Contains(t, readBuildFile("tasks/cleanup/dependencies.go"), "type Dependencies struct")
+}
+
+func TestRegisterModuleClassesErrors(t *testing.T) {
+	tests := map[string]*codegen.PluginClassConfig{
+		"unknown type": {
+			Name: "task", Directory: "tasks", Type: "many",
+		},
+		"unknown dependency": {
+			Name: "task", Directory: "tasks", Dependencies: []string{"job"},
+		},
+		"unregistered generator": {
+			Name: "task", Directory: "tasks",
+			ClassTypes: map[string]*codegen.PluginClassTypeConfig{
+				"cron": {Generator: "unknown"},
+			},
+		},
+		"no generator": {
+			Name: "task", Directory: "tasks",
+			ClassTypes: map[string]*codegen.PluginClassTypeConfig{
+				"cron": {},
+			},
+		},
+		"missing template": {
+			Name: "task", Directory: "tasks",
+			ClassTypes: map[string]*codegen.PluginClassTypeConfig{
+				"cron": {Templates: map[string]string{"task.go": "missing.tmpl"}},
+			},
+		},
+	}
+
+	packageHelper, err := codegen.NewPackageHelper(
+		"github.com/uber/zanzibar/examples/plugin-gateway",
+		".",
+		"",
+		"idl",
+		"github.com/uber/zanzibar/examples/plugin-gateway/build/gen-code",
+		"build",
+		testCopyrightHeader,
+		"",
+	)
+	if !assert.NoError(t, err, "failed to create package helper") {
+		return
+	}
+
+	for name, class := range tests {
+		err := codegen.RegisterModuleClasses(
+			codegen.NewModuleSystem(), packageHelper, ".",
+			[]*codegen.PluginClassConfig{class},
+		)
+		assert.Error(t, err, "expected error for %s", name)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"plugin"
 
 	"github.com/pkg/errors"
 	"github.com/uber/zanzibar/codegen"
@@ -33,9 +34,32 @@ import (
 )
 
 var configFile = flag.String("config", "", "the config file path")
+var diff = flag.Bool(
+	"diff", false,
+	"print the diff of the generated code instead of writing it, "+
+		"exits with 1 if it differs",
+)
+var check = flag.Bool(
+	"check", false,
+	"list the out of date generated files instead of writing them, "+
+		"exits with 1 if any",
+)
 
 const templateDir = "./codegen/templates/*.tmpl"
 
+const usage = `Usage:
+  runner -config <gateway.json> [-diff|-check]
+	generates the gateway, -diff and -check compare the generated code
+	with the build directory without writing it
+  runner new-client -config <gateway.json> -name <id> [-type http|tchannel]
+	scaffolds a new client
+  runner new-endpoint -config <gateway.json> -endpoint <id> -handle <id>
+	-thrift <file> -method <service::method> -client <id> -client-method <name>
+	scaffolds a new endpoint handler
+  runner new-middleware -config <gateway.json> -name <name>
+	scaffolds a new middleware
+`
+
 type stackTracer interface {
 	StackTrace() errors.StackTrace
 }
@@ -53,17 +77,17 @@ func checkError(err error, message string) {
 	}
 }
 
-func main() {
-	flag.Parse()
-	if *configFile == "" {
-		flag.Usage()
-		os.Exit(1)
-		return
-	}
+// gatewayConfig is the gateway config and the package helper built from it.
+type gatewayConfig struct {
+	config        *zanzibar.StaticConfig
+	configDirName string
+	packageHelper *codegen.PackageHelper
+}
 
-	configDirName := filepath.Dir(*configFile)
+func loadGatewayConfig(configFile string) *gatewayConfig {
+	configDirName := filepath.Dir(configFile)
 	config := zanzibar.NewStaticConfigOrDie([]string{
-		*configFile,
+		configFile,
 	}, nil)
 
 	configDirName, err := filepath.Abs(configDirName)
@@ -80,6 +104,13 @@ func main() {
 		copyright = []byte("")
 	}
 
+	templateOverrideDir := ""
+	if config.ContainsKey("templateOverrides") {
+		templateOverrideDir = filepath.Join(
+			configDirName, config.MustGetString("templateOverrides"),
+		)
+	}
+
 	packageHelper, err := codegen.NewPackageHelper(
 		config.MustGetString("packageRoot"),
 		configDirName,
@@ -88,15 +119,171 @@ func main() {
 		config.MustGetString("genCodePackage"),
 		filepath.Join(configDirName, config.MustGetString("targetGenDir")),
 		string(copyright),
+		templateOverrideDir,
 	)
 	checkError(
 		err, fmt.Sprintf("Can't build package helper %s", configDirName),
 	)
 
+	return &gatewayConfig{
+		config:        config,
+		configDirName: configDirName,
+		packageHelper: packageHelper,
+	}
+}
+
+// registerModuleClasses loads the codegen plugins of the gateway config and
+// registers its module classes in the module system.
+func registerModuleClasses(g *gatewayConfig, moduleSystem *codegen.ModuleSystem) {
+	if g.config.ContainsKey("codegenPlugins") {
+		var plugins []string
+		g.config.MustGetStruct("codegenPlugins", &plugins)
+		for _, pluginFile := range plugins {
+			_, err := plugin.Open(filepath.Join(g.configDirName, pluginFile))
+			checkError(
+				err, fmt.Sprintf("Error loading codegen plugin %s", pluginFile),
+			)
+		}
+	}
+
+	if g.config.ContainsKey("moduleClasses") {
+		var classes []*codegen.PluginClassConfig
+		g.config.MustGetStruct("moduleClasses", &classes)
+		err := codegen.RegisterModuleClasses(
+			moduleSystem, g.packageHelper, g.configDirName, classes,
+		)
+		checkError(err, "Error registering module classes")
+	}
+}
+
+// scaffoldCommands are the subcommands that scaffold new modules.
+var scaffoldCommands = map[string]func(args []string){
+	"new-client":     newClient,
+	"new-endpoint":   newEndpoint,
+	"new-middleware": newMiddleware,
+}
+
+// newScaffolder parses the scaffold command flags and returns a scaffolder
+// of the gateway in the -config flag.
+func newScaffolder(flags *flag.FlagSet, args []string) *codegen.Scaffolder {
+	configFile := flags.String("config", "", "the config file path")
+	serviceConfig := flags.String(
+		"service-config", "config/production.json",
+		"the service config file, relative to the config file dir",
+	)
+	if err := flags.Parse(args); err != nil || *configFile == "" {
+		fmt.Print(usage)
+		os.Exit(1)
+	}
+
+	g := loadGatewayConfig(*configFile)
+	moduleSystem, err := codegen.NewDefaultModuleSystem(g.packageHelper)
+	checkError(
+		err, fmt.Sprintf("Error creating module system %s", g.configDirName),
+	)
+	registerModuleClasses(g, moduleSystem)
+	scaffolder, err := codegen.NewScaffolder(
+		moduleSystem,
+		g.packageHelper,
+		g.configDirName,
+		g.config.MustGetString("middlewareConfig"),
+		*serviceConfig,
+		g.config.MustGetString("gatewayName"),
+	)
+	checkError(err, "Error creating scaffolder")
+	return scaffolder
+}
+
+func printFiles(files []string) {
+	for _, file := range files {
+		fmt.Printf("  %s\n", file)
+	}
+}
+
+func newClient(args []string) {
+	flags := flag.NewFlagSet("new-client", flag.ExitOnError)
+	c := &codegen.ClientScaffold{}
+	flags.StringVar(&c.ClientID, "name", "", "the client id")
+	flags.StringVar(&c.Type, "type", "http", "the client type, http or tchannel")
+	flags.StringVar(&c.ServiceName, "service", "", "the thrift service name")
+	flags.StringVar(&c.IP, "ip", "127.0.0.1", "the client ip")
+	flags.IntVar(&c.Port, "port", 0, "the client port")
+	scaffolder := newScaffolder(flags, args)
+
+	files, err := scaffolder.NewClient(c)
+	checkError(err, fmt.Sprintf("Failed to scaffold client %q", c.ClientID))
+	fmt.Printf("Scaffolded client %q:\n", c.ClientID)
+	printFiles(files)
+}
+
+func newEndpoint(args []string) {
+	flags := flag.NewFlagSet("new-endpoint", flag.ExitOnError)
+	e := &codegen.EndpointScaffold{}
+	flags.StringVar(&e.EndpointID, "endpoint", "", "the endpoint id")
+	flags.StringVar(&e.HandleID, "handle", "", "the handle id")
+	flags.StringVar(
+		&e.ThriftFile, "thrift", "",
+		"the endpoint thrift file, relative to the thrift root dir",
+	)
+	flags.StringVar(
+		&e.ThriftMethodName, "method", "", "the thrift service::method",
+	)
+	flags.StringVar(&e.ClientID, "client", "", "the client id")
+	flags.StringVar(&e.ClientMethod, "client-method", "", "the client method")
+	scaffolder := newScaffolder(flags, args)
+
+	files, err := scaffolder.NewEndpoint(e)
+	checkError(err, fmt.Sprintf(
+		"Failed to scaffold endpoint %q handler %q", e.EndpointID, e.HandleID,
+	))
+	fmt.Printf("Scaffolded endpoint %q handler %q:\n", e.EndpointID, e.HandleID)
+	printFiles(files)
+}
+
+func newMiddleware(args []string) {
+	flags := flag.NewFlagSet("new-middleware", flag.ExitOnError)
+	m := &codegen.MiddlewareScaffold{}
+	flags.StringVar(&m.Name, "name", "", "the middleware name")
+	scaffolder := newScaffolder(flags, args)
+
+	files, err := scaffolder.NewMiddleware(m)
+	checkError(err, fmt.Sprintf("Failed to scaffold middleware %q", m.Name))
+	fmt.Printf("Scaffolded middleware %q:\n", m.Name)
+	printFiles(files)
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if command, ok := scaffoldCommands[os.Args[1]]; ok {
+			command(os.Args[2:])
+			return
+		}
+	}
+
+	flag.Usage = func() { fmt.Print(usage) }
+	flag.Parse()
+	if *configFile == "" {
+		flag.Usage()
+		os.Exit(1)
+		return
+	}
+
+	g := loadGatewayConfig(*configFile)
+	config := g.config
+	configDirName := g.configDirName
+	packageHelper := g.packageHelper
+
 	moduleSystem, err := codegen.NewDefaultModuleSystem(packageHelper)
 	checkError(
 		err, fmt.Sprintf("Error creating module system %s", configDirName),
 	)
+	registerModuleClasses(g, moduleSystem)
+
+	var dryRun *codegen.DryRun
+	if *diff || *check {
+		dryRun = codegen.NewDryRun()
+		moduleSystem.EnableDryRun(dryRun)
+	}
 
 	// TODO: All components should be generated by the module system
 	fmt.Printf("Generating module system components:\n")
@@ -121,8 +308,40 @@ func main() {
 	checkError(
 		err, fmt.Sprintf("Cannot create gateway spec %#v", gatewaySpec),
 	)
+	if dryRun != nil {
+		gatewaySpec.EnableDryRun(dryRun)
+	}
 
 	fmt.Printf("Generating endpoint index code for gateway \n")
 	err = gatewaySpec.GenerateEndpointRegisterFile()
 	checkError(err, "Failed to generate endpoint index file.")
+
+	fmt.Printf("Generating endpoint sdk code for gateway \n")
+	err = gatewaySpec.GenerateSDK()
+	checkError(err, "Failed to generate endpoint sdk files.")
+
+	if dryRun != nil {
+		reportDrift(dryRun)
+	}
+}
+
+// reportDrift prints the generated files that differ from the build
+// directory, with their diff if -diff is set, and exits with 1 if any.
+func reportDrift(dryRun *codegen.DryRun) {
+	diffs, err := dryRun.Diff()
+	checkError(err, "Failed to compare the generated code.")
+	if len(diffs) == 0 {
+		fmt.Printf("Generated code is up to date\n")
+		return
+	}
+
+	for _, fileDiff := range diffs {
+		if *diff {
+			fmt.Print(fileDiff.Diff)
+		} else {
+			fmt.Println(fileDiff.Path)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d generated files are out of date\n", len(diffs))
+	os.Exit(1)
 }
@@ -0,0 +1,759 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ClientScaffold describes a new client module.
+type ClientScaffold struct {
+	// ClientID is the instance name of the client.
+	ClientID string
+	// Type is the client type, "http" or "tchannel".
+	Type string
+	// ServiceName is the thrift service of the client, the pascal case
+	// client id if empty.
+	ServiceName string
+	// IP and Port are the address of the client in the service config.
+	IP   string
+	Port int
+}
+
+// EndpointScaffold describes a new endpoint handler. The endpoint group is
+// created if it does not exist.
+type EndpointScaffold struct {
+	EndpointID string
+	HandleID   string
+	// ThriftFile is the endpoint thrift file relative to the thrift root.
+	ThriftFile string
+	// ThriftMethodName is the "$service::$method" of the handler.
+	ThriftMethodName string
+	// ClientID and ClientMethod are the client method called by the
+	// handler.
+	ClientID     string
+	ClientMethod string
+}
+
+// MiddlewareScaffold describes a new middleware.
+type MiddlewareScaffold struct {
+	Name string
+}
+
+// Scaffolder creates the config and stub files of new modules of a gateway
+// and validates them with the same parsing code as the generator. Nothing
+// is changed if the new module is not valid.
+type Scaffolder struct {
+	system           *ModuleSystem
+	templates        *Template
+	packageHelper    *PackageHelper
+	configDirName    string
+	middlewareConfig string
+	serviceConfig    string
+	gatewayName      string
+}
+
+// NewScaffolder returns a scaffolder for the gateway in configDirName. The
+// middleware and service config files are relative to configDirName, the
+// config keys of new clients are added to the service config.
+func NewScaffolder(
+	system *ModuleSystem,
+	h *PackageHelper,
+	configDirName string,
+	middlewareConfig string,
+	serviceConfig string,
+	gatewayName string,
+) (*Scaffolder, error) {
+	tmpl, err := NewTemplateWithOverrides(h.TemplateOverrideDir())
+	if err != nil {
+		return nil, err
+	}
+	return &Scaffolder{
+		system:           system,
+		templates:        tmpl,
+		packageHelper:    h,
+		configDirName:    configDirName,
+		middlewareConfig: middlewareConfig,
+		serviceConfig:    serviceConfig,
+		gatewayName:      gatewayName,
+	}, nil
+}
+
+// scaffoldClassConfig is a module class config as written by the scaffolder.
+type scaffoldClassConfig struct {
+	Name              string                 `json:"name"`
+	Type              string                 `json:"type"`
+	Config            map[string]interface{} `json:"config"`
+	Dependencies      map[string][]string    `json:"dependencies,omitempty"`
+	IsExportGenerated *bool                  `json:"IsExportGenerated,omitempty"`
+}
+
+// scaffoldHandlerConfig is an endpoint handler config as written by the
+// scaffolder.
+type scaffoldHandlerConfig struct {
+	EndpointType     string            `json:"endpointType"`
+	EndpointID       string            `json:"endpointId"`
+	HandleID         string            `json:"handleId"`
+	ThriftFile       string            `json:"thriftFile"`
+	ThriftFileSha    string            `json:"thriftFileSha"`
+	ThriftMethodName string            `json:"thriftMethodName"`
+	WorkflowType     string            `json:"workflowType"`
+	ClientID         string            `json:"clientID"`
+	ClientMethod     string            `json:"clientMethod"`
+	TestFixtures     []interface{}     `json:"testFixtures"`
+	Middlewares      []interface{}     `json:"middlewares"`
+	ReqHeaderMap     map[string]string `json:"reqHeaderMap"`
+	ResHeaderMap     map[string]string `json:"resHeaderMap"`
+}
+
+// scaffoldTestStub is an endpoint test fixture as written by the
+// scaffolder.
+type scaffoldTestStub struct {
+	TestName           string                 `json:"testName"`
+	TestServiceName    string                 `json:"testServiceName"`
+	EndpointID         string                 `json:"endpointId"`
+	HandlerID          string                 `json:"handlerId"`
+	EndpointRequest    map[string]interface{} `json:"endpointRequest"`
+	EndpointReqHeaders map[string]string      `json:"endpointReqHeaders"`
+	EndpointResponse   map[string]interface{} `json:"endpointResponse"`
+	EndpointResHeaders map[string]string      `json:"endpointResHeaders"`
+	ClientStubs        []scaffoldClientStub   `json:"clientStubs"`
+}
+
+type scaffoldClientStub struct {
+	ClientID         string                 `json:"clientId"`
+	ClientMethod     string                 `json:"clientMethod"`
+	ClientRequest    map[string]interface{} `json:"clientRequest"`
+	ClientReqHeaders map[string]string      `json:"clientReqHeaders"`
+	ClientResponse   map[string]interface{} `json:"clientResponse"`
+	ClientResHeaders map[string]string      `json:"clientResHeaders"`
+}
+
+// scaffoldFiles tracks the files written by a scaffold so that they can be
+// restored if the new module is not valid.
+type scaffoldFiles struct {
+	paths    []string
+	dirs     []string
+	original map[string][]byte
+	created  map[string]bool
+}
+
+func newScaffoldFiles() *scaffoldFiles {
+	return &scaffoldFiles{
+		original: map[string][]byte{},
+		created:  map[string]bool{},
+	}
+}
+
+func (f *scaffoldFiles) write(filePath string, content []byte) error {
+	if _, ok := f.original[filePath]; !ok && !f.created[filePath] {
+		original, err := ioutil.ReadFile(filePath)
+		switch {
+		case os.IsNotExist(err):
+			f.created[filePath] = true
+			f.trackDirs(filepath.Dir(filePath))
+		case err != nil:
+			return errors.Wrapf(err, "Could not read %q", filePath)
+		default:
+			f.original[filePath] = original
+		}
+		f.paths = append(f.paths, filePath)
+	}
+	return writeFile(filePath, content)
+}
+
+func (f *scaffoldFiles) writeJSON(filePath string, value interface{}) error {
+	content, err := json.MarshalIndent(value, "", "\t")
+	if err != nil {
+		return errors.Wrapf(err, "Could not serialize %q", filePath)
+	}
+	return f.write(filePath, append(content, '\n'))
+}
+
+// trackDirs records dir and its ancestors that do not exist yet, as they are
+// about to be created for a new file.
+func (f *scaffoldFiles) trackDirs(dir string) {
+	for !fileExists(dir) && !containsString(f.dirs, dir) {
+		f.dirs = append(f.dirs, dir)
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return
+		}
+		dir = parent
+	}
+}
+
+// restore removes the created files and directories and restores the
+// changed files.
+func (f *scaffoldFiles) restore() {
+	for i := len(f.paths) - 1; i >= 0; i-- {
+		filePath := f.paths[i]
+		if f.created[filePath] {
+			_ = os.Remove(filePath)
+			continue
+		}
+		_ = ioutil.WriteFile(filePath, f.original[filePath], 0644)
+	}
+
+	// Removes the deepest directories first, so that their parents are
+	// empty when they are removed.
+	dirs := append([]string(nil), f.dirs...)
+	sort.SliceStable(dirs, func(i, j int) bool {
+		return strings.Count(dirs[i], string(filepath.Separator)) >
+			strings.Count(dirs[j], string(filepath.Separator))
+	})
+	for _, dir := range dirs {
+		_ = os.Remove(dir)
+	}
+}
+
+// NewClient creates the client config, thrift stub and service config keys
+// of a new client and returns the written files.
+func (s *Scaffolder) NewClient(c *ClientScaffold) ([]string, error) {
+	if c.ClientID == "" {
+		return nil, errors.New("client id is required")
+	}
+	if c.Type != "http" && c.Type != "tchannel" {
+		return nil, errors.Errorf(
+			"client type must be \"http\" or \"tchannel\", got %q", c.Type,
+		)
+	}
+	serviceName := c.ServiceName
+	if serviceName == "" {
+		serviceName = pascalCase(c.ClientID)
+	}
+
+	clientsDir := s.system.classes["client"].Directory
+	configPath := filepath.Join(
+		s.configDirName, clientsDir, c.ClientID, "client-config.json",
+	)
+	if fileExists(configPath) {
+		return nil, errors.Errorf(
+			"client %q already exists: %s", c.ClientID, configPath,
+		)
+	}
+
+	thriftFile := path.Join("clients", c.ClientID, c.ClientID+".thrift")
+	config := map[string]interface{}{
+		"thriftFile": thriftFile,
+	}
+	configKeys := []string{"ip", "port"}
+	configValues := map[string]interface{}{"ip": c.IP, "port": c.Port}
+	if c.Type == "http" {
+		config["serviceName"] = serviceName
+	} else {
+		config["exposedMethods"] = map[string]string{
+			"Echo": serviceName + "::echo",
+		}
+		configKeys = append(configKeys,
+			"serviceName", "timeout", "timeoutPerAttempt",
+		)
+		configValues["serviceName"] = c.ClientID
+		configValues["timeout"] = 1000
+		configValues["timeoutPerAttempt"] = 1000
+	}
+
+	files := newScaffoldFiles()
+	err := s.newClient(files, c, serviceName, configPath, thriftFile, config)
+	if err == nil {
+		err = s.addServiceConfig(
+			files, "clients."+c.ClientID+".", configKeys, configValues,
+		)
+	}
+	if err == nil {
+		err = s.validateClient(c.ClientID)
+	}
+	if err != nil {
+		files.restore()
+		return nil, err
+	}
+	return files.paths, nil
+}
+
+func (s *Scaffolder) newClient(
+	files *scaffoldFiles,
+	c *ClientScaffold,
+	serviceName string,
+	configPath string,
+	thriftFile string,
+	config map[string]interface{},
+) error {
+	thriftPath := filepath.Join(s.packageHelper.ThriftIDLPath(), thriftFile)
+	if !fileExists(thriftPath) {
+		thrift, err := s.execScaffoldTemplate(
+			"client_scaffold_thrift.tmpl",
+			map[string]string{"ServiceName": serviceName, "Type": c.Type},
+		)
+		if err != nil {
+			return err
+		}
+		if err := files.write(thriftPath, thrift); err != nil {
+			return err
+		}
+	}
+
+	thriftFileSha, err := hashFile(thriftPath)
+	if err != nil {
+		return err
+	}
+	config["thriftFileSha"] = thriftFileSha
+	err = files.writeJSON(configPath, &scaffoldClassConfig{
+		Name:   c.ClientID,
+		Type:   c.Type,
+		Config: config,
+	})
+	if err != nil {
+		return err
+	}
+
+	initPath := filepath.Join(
+		s.configDirName, s.system.classes["clients"].Directory,
+		"clients-config.json",
+	)
+	if !fileExists(initPath) {
+		return nil
+	}
+	// Round-trips the config as a map to keep the fields the scaffolder
+	// does not know about.
+	initConfig := map[string]interface{}{}
+	if err := readJSONFile(initPath, &initConfig); err != nil {
+		return err
+	}
+	addDependency(initConfig, "client", c.ClientID)
+	return files.writeJSON(initPath, initConfig)
+}
+
+// NewEndpoint creates the handler config and test fixture of a new
+// endpoint handler, and the endpoint group config if needed, and returns
+// the written files.
+func (s *Scaffolder) NewEndpoint(e *EndpointScaffold) ([]string, error) {
+	required := []struct{ name, value string }{
+		{"endpoint id", e.EndpointID},
+		{"handle id", e.HandleID},
+		{"thrift file", e.ThriftFile},
+		{"thrift method name", e.ThriftMethodName},
+		{"client id", e.ClientID},
+		{"client method", e.ClientMethod},
+	}
+	for _, field := range required {
+		if field.value == "" {
+			return nil, errors.Errorf("%s is required", field.name)
+		}
+	}
+
+	instances, err := s.resolveModules()
+	if err != nil {
+		return nil, err
+	}
+	client := findModuleInstance(instances["client"], e.ClientID)
+	if client == nil {
+		return nil, errors.Errorf("unknown client %q", e.ClientID)
+	}
+	if client.ClassType != "http" && client.ClassType != "tchannel" {
+		return nil, errors.Errorf(
+			"client %q of type %q cannot be called by a new endpoint",
+			e.ClientID, client.ClassType,
+		)
+	}
+
+	endpointDir := filepath.Join(
+		s.configDirName,
+		s.system.classes["endpoint"].Directory,
+		e.EndpointID,
+	)
+	handlerPath := filepath.Join(endpointDir, e.HandleID+".json")
+	testPath := filepath.Join(endpointDir, e.HandleID+"_test.json")
+	for _, filePath := range []string{handlerPath, testPath} {
+		if fileExists(filePath) {
+			return nil, errors.Errorf(
+				"endpoint handler %q already exists: %s", e.HandleID, filePath,
+			)
+		}
+	}
+
+	files := newScaffoldFiles()
+	err = s.newEndpoint(files, e, client.ClassType, endpointDir, handlerPath, testPath)
+	if err == nil {
+		err = s.validateEndpoint(e, handlerPath)
+	}
+	if err != nil {
+		files.restore()
+		return nil, err
+	}
+	return files.paths, nil
+}
+
+func (s *Scaffolder) newEndpoint(
+	files *scaffoldFiles,
+	e *EndpointScaffold,
+	clientType string,
+	endpointDir string,
+	handlerPath string,
+	testPath string,
+) error {
+	configPath := filepath.Join(endpointDir, "endpoint-config.json")
+	// Round-trips an existing config as a map to keep the fields the
+	// scaffolder does not know about.
+	config := map[string]interface{}{
+		"name": e.EndpointID,
+		"type": "http",
+	}
+	if fileExists(configPath) {
+		if err := readJSONFile(configPath, &config); err != nil {
+			return err
+		}
+	}
+	classConfig, ok := config["config"].(map[string]interface{})
+	if !ok {
+		classConfig = map[string]interface{}{}
+		config["config"] = classConfig
+	}
+	classConfig["endpoints"] = appendSorted(
+		stringList(classConfig["endpoints"]), filepath.Base(handlerPath),
+	)
+	addDependency(config, "client", e.ClientID)
+	if err := files.writeJSON(configPath, config); err != nil {
+		return err
+	}
+
+	thriftFileSha, err := hashFile(
+		filepath.Join(s.packageHelper.ThriftIDLPath(), e.ThriftFile),
+	)
+	if err != nil {
+		return err
+	}
+	err = files.writeJSON(handlerPath, &scaffoldHandlerConfig{
+		EndpointType:     "http",
+		EndpointID:       e.EndpointID,
+		HandleID:         e.HandleID,
+		ThriftFile:       e.ThriftFile,
+		ThriftFileSha:    thriftFileSha,
+		ThriftMethodName: e.ThriftMethodName,
+		WorkflowType:     clientType + "Client",
+		ClientID:         e.ClientID,
+		ClientMethod:     e.ClientMethod,
+		TestFixtures:     []interface{}{},
+		Middlewares:      []interface{}{},
+		ReqHeaderMap:     map[string]string{},
+		ResHeaderMap:     map[string]string{},
+	})
+	if err != nil {
+		return err
+	}
+
+	return files.writeJSON(testPath, []*scaffoldTestStub{{
+		TestName:           "successfulRequest",
+		TestServiceName:    s.gatewayName,
+		EndpointID:         e.EndpointID,
+		HandlerID:          e.HandleID,
+		EndpointRequest:    map[string]interface{}{},
+		EndpointReqHeaders: map[string]string{},
+		EndpointResponse:   map[string]interface{}{},
+		EndpointResHeaders: map[string]string{},
+		ClientStubs: []scaffoldClientStub{{
+			ClientID:         e.ClientID,
+			ClientMethod:     e.ClientMethod,
+			ClientRequest:    map[string]interface{}{},
+			ClientReqHeaders: map[string]string{},
+			ClientResponse:   map[string]interface{}{},
+			ClientResHeaders: map[string]string{},
+		}},
+	}})
+}
+
+// NewMiddleware creates the go skeleton, options schema and module config of
+// a new middleware and returns the written files.
+func (s *Scaffolder) NewMiddleware(m *MiddlewareScaffold) ([]string, error) {
+	if m.Name == "" {
+		return nil, errors.New("middleware name is required")
+	}
+	legacySpecs, err := parseMiddlewareConfig(
+		s.middlewareConfig, s.configDirName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := legacySpecs[m.Name]; ok {
+		return nil, errors.Errorf("middleware %q already exists", m.Name)
+	}
+
+	middlewaresDir := s.system.classes["middleware"].Directory
+	middlewareDir := filepath.Join(s.configDirName, middlewaresDir, m.Name)
+	configPath := filepath.Join(middlewareDir, "middleware-config.json")
+	goPath := filepath.Join(middlewareDir, m.Name+".go")
+	schemaPath := filepath.Join(middlewareDir, m.Name+"_schema.json")
+	for _, filePath := range []string{configPath, goPath, schemaPath} {
+		if fileExists(filePath) {
+			return nil, errors.Errorf(
+				"middleware %q already exists: %s", m.Name, filePath,
+			)
+		}
+	}
+	config := &scaffoldClassConfig{
+		Name: m.Name,
+		Type: "default",
+		Config: map[string]interface{}{
+			"schema": m.Name + "_schema.json",
+		},
+	}
+
+	files := newScaffoldFiles()
+	err = s.newMiddleware(files, m, goPath, schemaPath, configPath, config)
+	if err == nil {
+		err = s.validateMiddleware(m.Name)
+	}
+	if err != nil {
+		files.restore()
+		return nil, err
+	}
+	return files.paths, nil
+}
+
+func (s *Scaffolder) newMiddleware(
+	files *scaffoldFiles,
+	m *MiddlewareScaffold,
+	goPath string,
+	schemaPath string,
+	configPath string,
+	config *scaffoldClassConfig,
+) error {
+	src, err := s.execScaffoldTemplate("middleware_scaffold.tmpl", map[string]string{
+		"Name":        m.Name,
+		"PackageName": camelCase(m.Name),
+	})
+	if err != nil {
+		return err
+	}
+	src = append([]byte(s.packageHelper.copyrightHeader+"\n\n"), src...)
+	formatted, err := format.Source(src)
+	if err != nil {
+		return errors.Wrapf(err, "Could not format middleware %q", m.Name)
+	}
+	if err := files.write(goPath, formatted); err != nil {
+		return err
+	}
+
+	err = files.writeJSON(schemaPath, map[string]interface{}{
+		"$schema":    "http://json-schema.org/schema#",
+		"type":       "object",
+		"properties": map[string]interface{}{},
+		"required":   []string{},
+	})
+	if err != nil {
+		return err
+	}
+	return files.writeJSON(configPath, config)
+}
+
+// addServiceConfig adds the given config keys under prefix to the service
+// config, keeping the existing keys and the formatting of the file.
+func (s *Scaffolder) addServiceConfig(
+	files *scaffoldFiles,
+	prefix string,
+	keys []string,
+	values map[string]interface{},
+) error {
+	if s.serviceConfig == "" {
+		return nil
+	}
+	configPath := filepath.Join(s.configDirName, s.serviceConfig)
+	content := []byte("{}\n")
+	if fileExists(configPath) {
+		var err error
+		if content, err = ioutil.ReadFile(configPath); err != nil {
+			return errors.Wrapf(err, "Could not read %q", configPath)
+		}
+	}
+	existing := map[string]interface{}{}
+	if err := json.Unmarshal(content, &existing); err != nil {
+		return errors.Wrapf(err, "Could not parse %q", configPath)
+	}
+
+	var lines []string
+	for _, key := range keys {
+		if _, ok := existing[prefix+key]; ok {
+			continue
+		}
+		entry, err := json.Marshal(map[string]interface{}{
+			prefix + key: values[key],
+		})
+		if err != nil {
+			return errors.Wrapf(err, "Could not serialize %q", prefix+key)
+		}
+		entry = bytes.Replace(entry, []byte(`":`), []byte(`": `), 1)
+		lines = append(lines, "\t"+string(entry[1:len(entry)-1]))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	body := bytes.TrimRight(content, " \t\r\n")
+	body = bytes.TrimRight(body[:len(body)-1], " \t\r\n")
+	separator := ",\n\n"
+	if bytes.HasSuffix(body, []byte("{")) {
+		separator = "\n"
+	}
+	updated := string(body) + separator + strings.Join(lines, ",\n") + "\n}\n"
+	return files.write(configPath, []byte(updated))
+}
+
+func (s *Scaffolder) resolveModules() (map[string][]*ModuleInstance, error) {
+	return s.system.ResolveModules(
+		s.packageHelper.PackageRoot(),
+		s.configDirName,
+		s.packageHelper.CodeGenTargetPath(),
+	)
+}
+
+func (s *Scaffolder) validateClient(clientID string) error {
+	_, err := s.resolveClientSpec(clientID)
+	return err
+}
+
+func (s *Scaffolder) resolveClientSpec(clientID string) (*ClientSpec, error) {
+	instances, err := s.resolveModules()
+	if err != nil {
+		return nil, err
+	}
+	instance := findModuleInstance(instances["client"], clientID)
+	if instance == nil {
+		return nil, errors.Errorf("unknown client %q", clientID)
+	}
+	return NewClientSpec(instance, s.packageHelper)
+}
+
+func (s *Scaffolder) validateEndpoint(
+	e *EndpointScaffold, handlerPath string,
+) error {
+	clientSpec, err := s.resolveClientSpec(e.ClientID)
+	if err != nil {
+		return err
+	}
+	espec, err := NewEndpointSpec(
+		handlerPath, s.packageHelper, s.packageHelper.MiddlewareSpecs(),
+	)
+	if err != nil {
+		return err
+	}
+	if err := espec.SetDownstream(
+		[]*ClientSpec{clientSpec}, s.packageHelper,
+	); err != nil {
+		return err
+	}
+	_, err = readTestStubs(espec)
+	return err
+}
+
+func (s *Scaffolder) validateMiddleware(name string) error {
+	instances, err := s.resolveModules()
+	if err != nil {
+		return err
+	}
+	instance := findModuleInstance(instances["middleware"], name)
+	if instance == nil {
+		return errors.Errorf("unknown middleware %q", name)
+	}
+	_, err = NewMiddlewareModuleSpec(instance)
+	return err
+}
+
+// execScaffoldTemplate renders a template without the generated code
+// header, the scaffolded files are owned by the gateway.
+func (s *Scaffolder) execScaffoldTemplate(
+	tplName string, tplData interface{},
+) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := s.templates.template.ExecuteTemplate(
+		&buf, tplName, tplData,
+	); err != nil {
+		return nil, errors.Wrapf(err, "Could not execute template %s", tplName)
+	}
+	return buf.Bytes(), nil
+}
+
+func findModuleInstance(
+	instances []*ModuleInstance, name string,
+) *ModuleInstance {
+	for _, instance := range instances {
+		if instance.InstanceName == name {
+			return instance
+		}
+	}
+	return nil
+}
+
+func readJSONFile(filePath string, value interface{}) error {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return errors.Wrapf(err, "Could not read %q", filePath)
+	}
+	if err := json.Unmarshal(content, value); err != nil {
+		return errors.Wrapf(err, "Could not parse %q", filePath)
+	}
+	return nil
+}
+
+// addDependency adds the named module of the given class to the
+// dependencies of a class config read as a map.
+func addDependency(config map[string]interface{}, class, name string) {
+	dependencies, ok := config["dependencies"].(map[string]interface{})
+	if !ok {
+		dependencies = map[string]interface{}{}
+		config["dependencies"] = dependencies
+	}
+	dependencies[class] = appendSorted(stringList(dependencies[class]), name)
+}
+
+// stringList returns the strings of a json list, ignoring other values.
+func stringList(value interface{}) []string {
+	var values []string
+	list, _ := value.([]interface{})
+	for _, item := range list {
+		if item, ok := item.(string); ok {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func fileExists(filePath string) bool {
+	_, err := os.Stat(filePath)
+	return err == nil
+}
+
+func appendSorted(values []string, value string) []string {
+	if containsString(values, value) {
+		return values
+	}
+	values = append(values, value)
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,278 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package codegen_test
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/zanzibar/codegen"
+)
+
+// newTestScaffolder creates an empty gateway in the codegen package dir, so
+// that the packages of scaffolded middlewares can be found.
+func newTestScaffolder(t *testing.T) (*codegen.Scaffolder, string, func()) {
+	dir, err := ioutil.TempDir(".", "scaffold")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	cleanup := func() { assert.NoError(t, os.RemoveAll(dir)) }
+
+	files := map[string]string{
+		"clients/clients-config.json": `{
+			"name": "clients", "type": "init", "config": {},
+			"dependencies": {"client": []},
+			"description": "all the clients"
+		}`,
+		"middlewares/middleware-config.json": `{"middlewares": []}`,
+		"config/production.json":             "{\n\t\"serviceName\": \"test\"\n}\n",
+	}
+	for file, content := range files {
+		path := filepath.Join(dir, file)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	for _, subDir := range []string{"endpoints", "services", "idl"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, subDir), os.ModePerm))
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if !assert.NoError(t, err) {
+		cleanup()
+		t.FailNow()
+	}
+	packageRoot := "github.com/uber/zanzibar/codegen/" + filepath.Base(dir)
+	h, err := codegen.NewPackageHelper(
+		packageRoot,
+		absDir,
+		"./middlewares/middleware-config.json",
+		filepath.Join(absDir, "idl"),
+		packageRoot+"/build/gen-code",
+		filepath.Join(absDir, "build"),
+		"",
+		"",
+	)
+	if !assert.NoError(t, err) {
+		cleanup()
+		t.FailNow()
+	}
+	system, err := codegen.NewDefaultModuleSystem(h)
+	if !assert.NoError(t, err) {
+		cleanup()
+		t.FailNow()
+	}
+	scaffolder, err := codegen.NewScaffolder(
+		system, h, absDir,
+		"./middlewares/middleware-config.json", "config/production.json",
+		"test-gateway",
+	)
+	if !assert.NoError(t, err) {
+		cleanup()
+		t.FailNow()
+	}
+	return scaffolder, absDir, cleanup
+}
+
+func readTestJSON(t *testing.T, path string) map[string]interface{} {
+	content, err := ioutil.ReadFile(path)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	value := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(content, &value))
+	return value
+}
+
+func TestScaffoldClientAndEndpoint(t *testing.T) {
+	scaffolder, dir, cleanup := newTestScaffolder(t)
+	defer cleanup()
+
+	files, err := scaffolder.NewClient(&codegen.ClientScaffold{
+		ClientID: "echo",
+		Type:     "http",
+		IP:       "127.0.0.1",
+		Port:     4000,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{
+		filepath.Join(dir, "idl/clients/echo/echo.thrift"),
+		filepath.Join(dir, "clients/echo/client-config.json"),
+		filepath.Join(dir, "clients/clients-config.json"),
+		filepath.Join(dir, "config/production.json"),
+	}, files)
+
+	thrift, err := ioutil.ReadFile(filepath.Join(dir, "idl/clients/echo/echo.thrift"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	thriftSha := fmt.Sprintf("%x", sha256.Sum256(thrift))
+	client := readTestJSON(t, filepath.Join(dir, "clients/echo/client-config.json"))
+	assert.Equal(t,
+		thriftSha, client["config"].(map[string]interface{})["thriftFileSha"],
+	)
+
+	config := readTestJSON(t, filepath.Join(dir, "config/production.json"))
+	assert.Equal(t, map[string]interface{}{
+		"serviceName":       "test",
+		"clients.echo.ip":   "127.0.0.1",
+		"clients.echo.port": float64(4000),
+	}, config)
+	clients := readTestJSON(t, filepath.Join(dir, "clients/clients-config.json"))
+	assert.Equal(t, map[string]interface{}{
+		"client": []interface{}{"echo"},
+	}, clients["dependencies"])
+	assert.Equal(t, "all the clients", clients["description"])
+
+	_, err = scaffolder.NewClient(&codegen.ClientScaffold{
+		ClientID: "echo",
+		Type:     "http",
+	})
+	assert.Error(t, err, "expected duplicate client error")
+
+	_, err = scaffolder.NewClient(&codegen.ClientScaffold{
+		ClientID: "other",
+		Type:     "grpc",
+	})
+	assert.Error(t, err, "expected unknown client type error")
+
+	files, err = scaffolder.NewEndpoint(&codegen.EndpointScaffold{
+		EndpointID:       "echo",
+		HandleID:         "echo",
+		ThriftFile:       "clients/echo/echo.thrift",
+		ThriftMethodName: "Echo::echo",
+		ClientID:         "echo",
+		ClientMethod:     "echo",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{
+		filepath.Join(dir, "endpoints/echo/endpoint-config.json"),
+		filepath.Join(dir, "endpoints/echo/echo.json"),
+		filepath.Join(dir, "endpoints/echo/echo_test.json"),
+	}, files)
+
+	endpoint := readTestJSON(t, filepath.Join(dir, "endpoints/echo/endpoint-config.json"))
+	assert.Equal(t, map[string]interface{}{
+		"endpoints": []interface{}{"echo.json"},
+	}, endpoint["config"])
+	handler := readTestJSON(t, filepath.Join(dir, "endpoints/echo/echo.json"))
+	assert.Equal(t, "httpClient", handler["workflowType"])
+	assert.Equal(t, thriftSha, handler["thriftFileSha"])
+}
+
+func TestScaffoldInvalidEndpointIsRestored(t *testing.T) {
+	scaffolder, dir, cleanup := newTestScaffolder(t)
+	defer cleanup()
+
+	_, err := scaffolder.NewClient(&codegen.ClientScaffold{
+		ClientID: "echo",
+		Type:     "tchannel",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	config := readTestJSON(t, filepath.Join(dir, "config/production.json"))
+	assert.Equal(t, "echo", config["clients.echo.serviceName"])
+
+	_, err = scaffolder.NewEndpoint(&codegen.EndpointScaffold{
+		EndpointID:       "echo",
+		HandleID:         "echo",
+		ThriftFile:       "clients/echo/echo.thrift",
+		ThriftMethodName: "Echo::echo",
+		ClientID:         "echo",
+		ClientMethod:     "Missing",
+	})
+	assert.Error(t, err, "expected unknown client method error")
+
+	_, err = os.Stat(filepath.Join(dir, "endpoints/echo"))
+	assert.True(t, os.IsNotExist(err), "expected endpoint dir to be removed")
+}
+
+func TestScaffoldInvalidClientIsRestored(t *testing.T) {
+	scaffolder, dir, cleanup := newTestScaffolder(t)
+	defer cleanup()
+
+	configPath := filepath.Join(dir, "config/production.json")
+	assert.NoError(t, ioutil.WriteFile(configPath, []byte("{"), 0644))
+	clientsPath := filepath.Join(dir, "clients/clients-config.json")
+	clients, err := ioutil.ReadFile(clientsPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = scaffolder.NewClient(&codegen.ClientScaffold{
+		ClientID: "echo",
+		Type:     "http",
+	})
+	assert.Error(t, err, "expected invalid service config error")
+
+	for _, created := range []string{"idl/clients", "clients/echo"} {
+		_, err = os.Stat(filepath.Join(dir, created))
+		assert.True(t, os.IsNotExist(err), "expected %s to be removed", created)
+	}
+	_, err = os.Stat(filepath.Join(dir, "idl"))
+	assert.NoError(t, err, "expected existing idl dir to be kept")
+	restored, err := ioutil.ReadFile(clientsPath)
+	assert.NoError(t, err)
+	assert.Equal(t, string(clients), string(restored))
+}
+
+func TestScaffoldMiddleware(t *testing.T) {
+	scaffolder, dir, cleanup := newTestScaffolder(t)
+	defer cleanup()
+
+	files, err := scaffolder.NewMiddleware(&codegen.MiddlewareScaffold{
+		Name: "audit_log",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{
+		filepath.Join(dir, "middlewares/audit_log/audit_log.go"),
+		filepath.Join(dir, "middlewares/audit_log/audit_log_schema.json"),
+		filepath.Join(dir, "middlewares/audit_log/middleware-config.json"),
+	}, files)
+
+	config := readTestJSON(t, filepath.Join(
+		dir, "middlewares/audit_log/middleware-config.json",
+	))
+	assert.Equal(t, map[string]interface{}{
+		"name": "audit_log",
+		"type": "default",
+		"config": map[string]interface{}{
+			"schema": "audit_log_schema.json",
+		},
+	}, config)
+
+	_, err = scaffolder.NewMiddleware(&codegen.MiddlewareScaffold{
+		Name: "audit_log",
+	})
+	assert.Error(t, err, "expected duplicate middleware error")
+}
@@ -145,6 +145,8 @@ func (ms *ModuleSpec) SetDownstream(
 	methodName string,
 	clientSpec *ClientSpec,
 	clientMethod string,
+	reqTransforms map[string]FieldMapperEntry,
+	resTransforms map[string]FieldMapperEntry,
 	h *PackageHelper,
 ) error {
 	var service *ServiceSpec
@@ -175,24 +177,13 @@ func (ms *ModuleSpec) SetDownstream(
 		)
 	}
 
-	var err error
-	// TODO: we should do the same thing for http client as well
-	if clientSpec.ClientType == "tchannel" {
-		serviceMethod, ok := clientSpec.ExposedMethods[clientMethod]
-		if !ok {
-			return errors.Errorf("Client %q does not expose method %q", clientSpec.ClientName, clientMethod)
-		}
-		sm := strings.Split(serviceMethod, "::")
-
-		err = method.setDownstream(clientSpec.ModuleSpec, sm[0], sm[1])
-	} else {
-		err = method.setDownstream(
-			clientSpec.ModuleSpec,
-			clientSpec.ThriftServiceName,
-			clientMethod,
-		)
+	serviceMethod, ok := clientSpec.ExposedMethods[clientMethod]
+	if !ok {
+		return errors.Errorf("Client %q does not expose method %q", clientSpec.ClientName, clientMethod)
 	}
+	sm := strings.Split(serviceMethod, "::")
 
+	err := method.setDownstream(clientSpec.ModuleSpec, sm[0], sm[1])
 	if err != nil {
 		return err
 	}
@@ -200,27 +191,12 @@ func (ms *ModuleSpec) SetDownstream(
 	// If this is an endpoint then a downstream will be defined.
 	// If if it a client it will not be.
 	if method.Downstream != nil {
-		var downstreamMethod *MethodSpec
-
-		// TODO: once all client configs have "exposedMethods" field, we can find the exact
-		// service, instead of loop over for service looking for the first matching method,
-		// which could totally be wrong method
-	loop:
-		for _, s := range method.Downstream.Services {
-			for _, dsMethod := range s.Methods {
-				if method.Name == dsMethod.Name {
-					downstreamMethod = dsMethod
-					break loop
-				}
-			}
-		}
-		if downstreamMethod == nil {
-			return errors.Errorf("Failed to map %s to one of the downstream methods: %v  ", method.Name, method.Downstream.Services[0].Methods)
-		}
-		downstreamSpec := downstreamMethod.CompiledThriftSpec
+		downstreamSpec := method.DownstreamMethod.CompiledThriftSpec
 		funcSpec := method.CompiledThriftSpec
 
-		err := method.setTypeConverters(funcSpec, downstreamSpec, h)
+		err := method.setTypeConverters(
+			funcSpec, downstreamSpec, reqTransforms, resTransforms, h,
+		)
 		if err != nil {
 			return err
 		}
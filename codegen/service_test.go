@@ -23,6 +23,9 @@ package codegen_test
 import (
 	"encoding/json"
 	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -59,3 +62,149 @@ func convertThriftPathToRelative(m *codegen.ModuleSpec) {
 		}
 	}
 }
+
+const typedParamsThrift = `
+namespace java com.uber.zanzibar.items
+
+enum Kind { SMALL, LARGE }
+
+typedef i64 ID
+
+service Items {
+	string get(
+		1: required ID id (zanzibar.http.ref = "params.id")
+		2: required Kind kind (zanzibar.http.ref = "params.kind")
+		3: required bool fresh (zanzibar.http.ref = "params.fresh")
+		4: required string name (zanzibar.http.ref = "params.name")
+	) (
+		zanzibar.http.method = "POST"
+		zanzibar.http.path = "/items/:id/:kind/:fresh/:name"
+		zanzibar.http.status = "200"
+	)
+}
+`
+
+// newTempModuleSpec compiles the thrift content in a temporary directory
+// under the example idl root.
+func newTempModuleSpec(t *testing.T, content string) (*codegen.ModuleSpec, error) {
+	dir, err := ioutil.TempDir("../examples/example-gateway/idl", "items")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		assert.NoError(t, os.RemoveAll(dir))
+	}()
+
+	thriftFile := filepath.Join(dir, "items.thrift")
+	err = ioutil.WriteFile(thriftFile, []byte(content), 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return codegen.NewModuleSpec(thriftFile, true, newPackageHelper(t))
+}
+
+func TestTypedPathParams(t *testing.T) {
+	m, err := newTempModuleSpec(t, typedParamsThrift)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	segments := m.Services[0].Methods[0].PathSegments
+	if !assert.Len(t, segments, 5) {
+		return
+	}
+	pkg := m.Services[0].Methods[0].GenCodePkgName
+
+	assert.Equal(t, "strconv.FormatInt(int64(r.Id), 10)", segments[1].ClientValue)
+	assert.Equal(t, []string{
+		`idParam, err := strconv.ParseInt(req.Params.ByName("id"), 10, 64)`,
+		"if err != nil {",
+		`res.SendErrorString(400, "Invalid path parameter: id")`,
+		"return",
+		"}",
+		"requestBody.Id = " + pkg + ".ID(idParam)",
+	}, segments[1].ParseGoStatements)
+
+	assert.Equal(t, "url.PathEscape("+pkg+".Kind(r.Kind).String())", segments[2].ClientValue)
+	assert.Equal(t, []string{
+		"var kindParam " + pkg + ".Kind",
+		`if err := kindParam.UnmarshalText([]byte(req.Params.ByName("kind"))); err != nil {`,
+		`res.SendErrorString(400, "Invalid path parameter: kind")`,
+		"return",
+		"}",
+		"requestBody.Kind = kindParam",
+	}, segments[2].ParseGoStatements)
+
+	assert.Equal(t, "strconv.FormatBool(bool(r.Fresh))", segments[3].ClientValue)
+	assert.Equal(t, "url.PathEscape(string(r.Name))", segments[4].ClientValue)
+	assert.Equal(t, []string{
+		`requestBody.Name = req.Params.ByName("name")`,
+	}, segments[4].ParseGoStatements)
+}
+
+const requestEncodingThrift = `
+namespace java com.uber.zanzibar.items
+
+struct Photo {
+	1: required string name
+	2: optional binary content
+}
+
+service Items {
+	void upload(
+		1: required Photo photo
+	) (
+		zanzibar.http.method = "POST"
+		zanzibar.http.path = "/photos"
+		zanzibar.http.status = "204"
+		zanzibar.http.req.def = "true"
+		zanzibar.http.req.encoding = "multipart"
+	)
+	void rename(
+		1: required string name
+	) (
+		zanzibar.http.method = "POST"
+		zanzibar.http.path = "/rename"
+		zanzibar.http.status = "204"
+		zanzibar.http.req.encoding = "form"
+	)
+	void echo(
+		1: required string name
+	) (
+		zanzibar.http.method = "POST"
+		zanzibar.http.path = "/echo"
+		zanzibar.http.status = "204"
+	)
+}
+`
+
+func TestRequestEncoding(t *testing.T) {
+	m, err := newTempModuleSpec(t, requestEncodingThrift)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	methods := m.Services[0].Methods
+	if !assert.Len(t, methods, 3) {
+		return
+	}
+	assert.Equal(t, "echo", methods[0].Name)
+	assert.Equal(t, "json", methods[0].RequestEncoding)
+	assert.Equal(t, "form", methods[1].RequestEncoding)
+	assert.Equal(t, "multipart", methods[2].RequestEncoding)
+}
+
+func TestInvalidRequestEncoding(t *testing.T) {
+	_, err := newTempModuleSpec(t, strings.Replace(
+		requestEncodingThrift, `"form"`, `"xml"`, 1,
+	))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown encoding "xml"`)
+
+	_, err = newTempModuleSpec(t, strings.Replace(
+		requestEncodingThrift, "1: required Photo photo", "1: required string photo", 1,
+	))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "multipart encoding needs a struct body")
+}
@@ -24,6 +24,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -53,6 +54,7 @@ var funcMap = tmpl.FuncMap{
 	"pascal":       pascalCase,
 	"jsonMarshal":  jsonMarshal,
 	"unref":        unref,
+	"clientMethod": newClientMethodMeta,
 }
 
 func fullTypeName(typeName, packageName string) string {
@@ -78,13 +80,44 @@ func unref(t string) string {
 	return t
 }
 
+// ClientMethodMeta is the data of a http client method template.
+type ClientMethodMeta struct {
+	ClientName string
+	MethodName string
+	Method     *MethodSpec
+}
+
+func newClientMethodMeta(
+	clientName, methodName string, method *MethodSpec,
+) *ClientMethodMeta {
+	return &ClientMethodMeta{
+		ClientName: clientName,
+		MethodName: methodName,
+		Method:     method,
+	}
+}
+
 // Template generates code for edge gateway clients and edgegateway endpoints.
 type Template struct {
 	template *tmpl.Template
+	// dryRun collects the generated files instead of writing them, if set.
+	dryRun *DryRun
 }
 
+// baseTemplatePrefix prefixes the names of the bundled templates, so that
+// template overrides can render the templates they replace.
+const baseTemplatePrefix = "zanzibar/"
+
 // NewTemplate creates a bundle of templates.
 func NewTemplate() (*Template, error) {
+	return NewTemplateWithOverrides("")
+}
+
+// NewTemplateWithOverrides creates a bundle of templates where the *.tmpl
+// files of the override dir replace the bundled templates of the same name,
+// or the blocks they define. The bundled templates can still be rendered
+// with a "zanzibar/" prefix, e.g. {{template "zanzibar/endpoint.tmpl" .}}.
+func NewTemplateWithOverrides(overrideDir string) (*Template, error) {
 	t := tmpl.New("main").Funcs(funcMap)
 	for _, file := range templates.AssetNames() {
 		fileContent, err := templates.Asset(file)
@@ -94,12 +127,46 @@ func NewTemplate() (*Template, error) {
 		if _, err := t.New(file).Parse(string(fileContent)); err != nil {
 			return nil, errors.Wrapf(err, "Could not parse template %s", file)
 		}
+		if _, err := t.New(baseTemplatePrefix + file).Parse(string(fileContent)); err != nil {
+			return nil, errors.Wrapf(err, "Could not parse template %s", file)
+		}
+	}
+
+	overrideFiles, err := templateOverrideFiles(overrideDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range overrideFiles {
+		fileContent, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Could not read template override %s", file)
+		}
+		if _, err := t.New(filepath.Base(file)).Parse(string(fileContent)); err != nil {
+			return nil, errors.Wrapf(err, "Could not parse template override %s", file)
+		}
 	}
 	return &Template{
 		template: t,
 	}, nil
 }
 
+// templateOverrideFiles returns the sorted template files of the override
+// dir, none if the dir is empty.
+func templateOverrideFiles(overrideDir string) ([]string, error) {
+	if overrideDir == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(overrideDir); err != nil {
+		return nil, errors.Wrapf(err, "Could not read template override dir")
+	}
+	files, err := filepath.Glob(filepath.Join(overrideDir, "*.tmpl"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not list template overrides")
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
 // ClientMeta ...
 type ClientMeta struct {
 	PackageName      string
@@ -109,6 +176,37 @@ type ClientMeta struct {
 	IncludedPackages []GoPackageImport
 	Services         []*ServiceSpec
 	ExposedMethods   map[string]string
+	// Methods are the exposed methods in the order they are generated.
+	Methods []*ClientMethodMeta
+}
+
+// ClientMockMeta is the data of the client mock template.
+type ClientMockMeta struct {
+	ClientID         string
+	ClientPackage    GoPackageImport
+	IncludedPackages []GoPackageImport
+	Methods          []*ClientMethodMeta
+}
+
+// exposedClientMethods returns the exposed methods of a client module, the
+// exposedMethods map is keyed by "service::method" and names the generated
+// method.
+func exposedClientMethods(
+	clientName string, m *ModuleSpec, exposedMethods map[string]string,
+) []*ClientMethodMeta {
+	methods := []*ClientMethodMeta{}
+	for _, service := range m.Services {
+		for _, method := range service.Methods {
+			name, ok := exposedMethods[service.Name+"::"+method.Name]
+			if !ok {
+				continue
+			}
+			methods = append(
+				methods, newClientMethodMeta(clientName, name, method),
+			)
+		}
+	}
+	return methods
 }
 
 func findMethod(
@@ -173,6 +271,8 @@ type EndpointRegisterInfo struct {
 type EndpointsRegisterMeta struct {
 	IncludedPackages []GoPackageImport
 	Endpoints        []EndpointRegisterInfo
+	// ConfigSchema is the config schema of the gateway.
+	ConfigSchema map[string]string
 }
 
 type sortByEndpointName []*EndpointSpec
@@ -202,10 +302,37 @@ func contains(arr []GoPackageImport, value string) bool {
 	return false
 }
 
+// OpenAPIMeta is the data of the template embedding the OpenAPI document.
+type OpenAPIMeta struct {
+	// DocumentLiteral is the document as a go raw string literal.
+	DocumentLiteral string
+}
+
+// GenerateOpenAPIFile will generate the file embedding the OpenAPI
+// document in the endpoints package, which registers it in the router.
+func (t *Template) GenerateOpenAPIFile(
+	openAPIDocument []byte, h *PackageHelper,
+) (string, error) {
+	meta := &OpenAPIMeta{
+		DocumentLiteral: "`" + strings.Replace(
+			string(openAPIDocument), "`", "` + \"`\" + `", -1,
+		) + "`",
+	}
+
+	targetFile := h.TargetEndpointsOpenAPIPath()
+	err := t.execTemplateAndFmt("endpoint_openapi.tmpl", targetFile, meta, h)
+	if err != nil {
+		return "", err
+	}
+	return targetFile, nil
+}
+
 // GenerateEndpointRegisterFile will generate the registration file
-// that mounts all the endpoints in the router.
+// that mounts all the endpoints and the OpenAPI document in the router, and
+// declares the config schema of the gateway.
 func (t *Template) GenerateEndpointRegisterFile(
 	endpointsMap map[string]*EndpointSpec, h *PackageHelper,
+	configSchema map[string]string,
 ) (string, error) {
 	endpoints := make([]*EndpointSpec, 0, len(endpointsMap))
 	for _, v := range endpointsMap {
@@ -251,7 +378,7 @@ func (t *Template) GenerateEndpointRegisterFile(
 			endpointType + "Handler"
 
 		includedPkgs = addEndpointPackage(espec, includedPkgs)
-		includedPkgs = addMiddlewarePackages(espec.Middlewares, includedPkgs)
+		includedPkgs = addMiddlewarePackages(espec.Middlewares, includedPkgs, h)
 
 		info := EndpointRegisterInfo{
 			EndpointType: endpointType,
@@ -270,6 +397,7 @@ func (t *Template) GenerateEndpointRegisterFile(
 	meta := &EndpointsRegisterMeta{
 		IncludedPackages: includedPkgs,
 		Endpoints:        endpointsInfo,
+		ConfigSchema:     configSchema,
 	}
 
 	targetFile := h.TargetEndpointsRegisterPath()
@@ -285,6 +413,78 @@ func (t *Template) GenerateEndpointRegisterFile(
 	return targetFile, nil
 }
 
+// SDKMeta is the data of the gateway sdk template of an endpoint group.
+type SDKMeta struct {
+	PackageName      string
+	ExportName       string
+	ExportType       string
+	EndpointID       string
+	IncludedPackages []GoPackageImport
+	Methods          []*ClientMethodMeta
+}
+
+// GenerateSDKFiles will generate a go client package per http endpoint
+// group, with a method per endpoint handler, and returns the file paths.
+func (t *Template) GenerateSDKFiles(
+	endpointsMap map[string]*EndpointSpec, h *PackageHelper,
+) ([]string, error) {
+	endpoints := make([]*EndpointSpec, 0, len(endpointsMap))
+	for _, v := range endpointsMap {
+		endpoints = append(endpoints, v)
+	}
+	sort.Sort(sortByEndpointName(endpoints))
+
+	groups := map[string]*SDKMeta{}
+	groupIDs := []string{}
+	for _, espec := range endpoints {
+		if espec.EndpointType != "http" {
+			continue
+		}
+		method := findMethod(
+			espec.ModuleSpec,
+			espec.ThriftServiceName,
+			espec.ThriftMethodName,
+		)
+		if method == nil {
+			return nil, errors.Errorf(
+				"Could not find serviceName %q + methodName %q in module",
+				espec.ThriftServiceName, espec.ThriftMethodName,
+			)
+		}
+
+		meta, ok := groups[espec.EndpointID]
+		if !ok {
+			meta = &SDKMeta{
+				PackageName: camelCase(espec.EndpointID) + "SDK",
+				ExportName:  "NewClient",
+				ExportType:  pascalCase(espec.EndpointID) + "Client",
+				EndpointID:  espec.EndpointID,
+			}
+			groups[espec.EndpointID] = meta
+			groupIDs = append(groupIDs, espec.EndpointID)
+		}
+		for _, pkg := range espec.ModuleSpec.IncludedPackages {
+			if !contains(meta.IncludedPackages, pkg.PackageName) {
+				meta.IncludedPackages = append(meta.IncludedPackages, pkg)
+			}
+		}
+		meta.Methods = append(meta.Methods, newClientMethodMeta(
+			meta.ExportType, pascalCase(espec.HandleID), method,
+		))
+	}
+
+	files := make([]string, 0, len(groupIDs))
+	for _, endpointID := range groupIDs {
+		targetFile := h.TargetSDKPath(endpointID)
+		err := t.execTemplateAndFmt("sdk.tmpl", targetFile, groups[endpointID], h)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, targetFile)
+	}
+	return files, nil
+}
+
 func addEndpointPackage(espec *EndpointSpec, includedPkgs []GoPackageImport) []GoPackageImport {
 	var goPkg string
 	switch espec.WorkflowType {
@@ -303,7 +503,9 @@ func addEndpointPackage(espec *EndpointSpec, includedPkgs []GoPackageImport) []G
 	return includedPkgs
 }
 
-func addMiddlewarePackages(middlewares []MiddlewareSpec, includedPkgs []GoPackageImport) []GoPackageImport {
+func addMiddlewarePackages(
+	middlewares []MiddlewareSpec, includedPkgs []GoPackageImport, h *PackageHelper,
+) []GoPackageImport {
 	for _, m := range middlewares {
 		if !contains(includedPkgs, m.Path) {
 			includedPkgs = append(includedPkgs, GoPackageImport{
@@ -311,6 +513,23 @@ func addMiddlewarePackages(middlewares []MiddlewareSpec, includedPkgs []GoPackag
 				AliasName:   "",
 			})
 		}
+		if m.DependenciesPath == "" {
+			continue
+		}
+		// The dependencies of middlewares are taken from the clients
+		if !contains(includedPkgs, m.DependenciesPath) {
+			includedPkgs = append(includedPkgs, GoPackageImport{
+				PackageName: m.DependenciesPath,
+				AliasName:   m.DependenciesAlias,
+			})
+		}
+		clientsPkg := h.GoGatewayPackageName() + "/clients"
+		if !contains(includedPkgs, clientsPkg) {
+			includedPkgs = append(includedPkgs, GoPackageImport{
+				PackageName: clientsPkg,
+				AliasName:   "",
+			})
+		}
 	}
 	return includedPkgs
 }
@@ -328,6 +547,13 @@ func (t *Template) execTemplateAndFmt(
 	data interface{},
 	pkgHelper *PackageHelper,
 ) error {
+	if t.dryRun != nil {
+		content, err := t.execTemplate(templName, data, pkgHelper)
+		if err != nil {
+			return err
+		}
+		return t.dryRun.writeFile(filePath, content)
+	}
 
 	file, err := openFileOrCreate(filePath)
 	if err != nil {
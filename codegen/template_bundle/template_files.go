@@ -1,14 +1,21 @@
 // Code generated by go-bindata.
 // sources:
+// codegen/templates/client_interface.tmpl
+// codegen/templates/client_mock.tmpl
+// codegen/templates/client_scaffold_thrift.tmpl
 // codegen/templates/dependency_struct.tmpl
 // codegen/templates/endpoint.tmpl
+// codegen/templates/endpoint_openapi.tmpl
 // codegen/templates/endpoint_register.tmpl
 // codegen/templates/endpoint_test.tmpl
 // codegen/templates/endpoint_test_tchannel_client.tmpl
 // codegen/templates/http_client.tmpl
+// codegen/templates/http_client_method.tmpl
 // codegen/templates/init_clients.tmpl
 // codegen/templates/main.tmpl
 // codegen/templates/main_test.tmpl
+// codegen/templates/middleware_scaffold.tmpl
+// codegen/templates/sdk.tmpl
 // codegen/templates/structs.tmpl
 // codegen/templates/tchannel_client.tmpl
 // codegen/templates/tchannel_client_test_server.tmpl
@@ -57,6 +64,152 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
+var _client_interfaceTmpl = []byte(`{{- /* template to render the interface of a generated client */ -}}
+// Client defines {{.ClientID}} client interface.
+type Client interface {
+{{- range $m := .Methods}}
+	{{$m.MethodName}}(
+		ctx context.Context,
+		reqHeaders map[string]string,
+		{{if ne $m.Method.RequestType "" -}}
+		args {{$m.Method.RequestType}},
+		{{end -}}
+	) ({{- if ne $m.Method.ResponseType "" -}} {{$m.Method.ResponseType}}, {{- end -}}map[string]string, error)
+{{- end}}
+}
+`)
+
+func client_interfaceTmplBytes() ([]byte, error) {
+	return _client_interfaceTmpl, nil
+}
+
+func client_interfaceTmpl() (*asset, error) {
+	bytes, err := client_interfaceTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "client_interface.tmpl", size: 451, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _client_mockTmpl = []byte(`{{- /* template to render the mock of a generated client */ -}}
+
+package clientmock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	{{.ClientPackage.AliasName}} "{{.ClientPackage.PackageName}}"
+	{{range $idx, $pkg := .IncludedPackages -}}
+	{{$pkg.AliasName}} "{{$pkg.PackageName}}"
+	{{end}}
+)
+
+// MockClient is a mock of the {{.ClientID}} client. Expected calls and their
+// canned responses are recorded with On and verified with
+// AssertExpectations.
+type MockClient struct {
+	mock.Mock
+}
+
+var _ {{.ClientPackage.AliasName}}.Client = (*MockClient)(nil)
+
+// NewMockClient returns a mock of the {{.ClientID}} client.
+func NewMockClient() *MockClient {
+	return &MockClient{}
+}
+
+{{range $m := .Methods}}
+{{- $method := $m.Method}}
+// {{$m.MethodName}} returns the canned response of the expected call.
+func (m *MockClient) {{$m.MethodName}}(
+	ctx context.Context,
+	reqHeaders map[string]string,
+	{{if ne $method.RequestType "" -}}
+	args {{$method.RequestType}},
+	{{end -}}
+) ({{- if ne $method.ResponseType "" -}} {{$method.ResponseType}}, {{- end -}}map[string]string, error) {
+	{{if ne $method.RequestType "" -}}
+	ret := m.Called(ctx, reqHeaders, args)
+	{{- else -}}
+	ret := m.Called(ctx, reqHeaders)
+	{{- end}}
+	{{if ne $method.ResponseType "" -}}
+	var response {{$method.ResponseType}}
+	if v := ret.Get(0); v != nil {
+		response = v.({{$method.ResponseType}})
+	}
+	var resHeaders map[string]string
+	if v := ret.Get(1); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return response, resHeaders, ret.Error(2)
+	{{- else -}}
+	var resHeaders map[string]string
+	if v := ret.Get(0); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return resHeaders, ret.Error(1)
+	{{- end}}
+}
+{{end}}
+`)
+
+func client_mockTmplBytes() ([]byte, error) {
+	return _client_mockTmpl, nil
+}
+
+func client_mockTmpl() (*asset, error) {
+	bytes, err := client_mockTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "client_mock.tmpl", size: 1701, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _client_scaffold_thriftTmpl = []byte(`{{- /* template to render the thrift stub of a new client */ -}}
+{{- $http := eq .Type "http" -}}
+struct {{.ServiceName}}Request {
+    1: required string message
+}
+
+struct {{.ServiceName}}Response {
+    1: required string message
+}
+
+service {{.ServiceName}} {
+    {{.ServiceName}}Response echo(
+        1: required {{.ServiceName}}Request request
+    ){{if $http}} (
+        zanzibar.http.method = "POST"
+        zanzibar.http.path = "/echo"
+        zanzibar.http.status = "200"
+    ){{end}}
+}
+`)
+
+func client_scaffold_thriftTmplBytes() ([]byte, error) {
+	return _client_scaffold_thriftTmpl, nil
+}
+
+func client_scaffold_thriftTmpl() (*asset, error) {
+	bytes, err := client_scaffold_thriftTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "client_scaffold_thrift.tmpl", size: 494, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _dependency_structTmpl = []byte(`{{$instance := . -}}
 package {{$instance.PackageInfo.PackageName}}
 
@@ -79,8 +232,13 @@ type Dependencies struct {
 // {{$classType | pascal}}Dependencies contains {{$classType}} dependencies
 type {{$classType | pascal}}Dependencies struct {
 	{{ range $idx, $dependency := $moduleInstances -}}
+	{{- /* generated clients are held by their interface */}}
+	{{if and (eq $dependency.ClassName "client") $dependency.PackageInfo.IsExportGenerated -}}
+	{{$dependency.PackageInfo.QualifiedInstanceName}} {{$dependency.PackageInfo.ImportPackageAlias}}.Client
+	{{else -}}
 	{{$dependency.PackageInfo.QualifiedInstanceName}} *{{$dependency.PackageInfo.ImportPackageAlias}}.{{$dependency.PackageInfo.ExportType}}
 	{{end -}}
+	{{end -}}
 }
 {{end -}}
 `)
@@ -95,7 +253,7 @@ func dependency_structTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "dependency_struct.tmpl", size: 1033, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	info := bindataFileInfo{name: "dependency_struct.tmpl", size: 1312, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -136,11 +294,12 @@ import (
 {{ $handlerName := title .Method.Name | printf "%sHandler" }}
 {{ $responseType := .Method.ResponseType}}
 {{ $clientMethodName := title .ClientMethodName -}}
+{{ $clientDependencies := .ClientDependencies -}}
 {{with .Method -}}
 
 // {{$handlerName}} is the handler for "{{.HTTPPath}}"
 type {{$handlerName}} struct {
-	Clients *clients.Clients
+	Dependencies *module.Dependencies
 }
 
 // New{{title .Name}}Endpoint creates a handler
@@ -148,7 +307,13 @@ func New{{title .Name}}Endpoint(
 	gateway *zanzibar.Gateway,
 ) *{{$handlerName}} {
 	return &{{$handlerName}}{
-		Clients: gateway.Clients.(*clients.Clients),
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				{{range $clientDependencies -}}
+				{{.}}: gateway.Clients.(*clients.Clients).{{.}},
+				{{end -}}
+			},
+		},
 	}
 }
 
@@ -166,11 +331,21 @@ func (handler *{{$handlerName}}) HandleRequest(
 
 	{{if ne .RequestType ""}}
 	var requestBody {{unref .RequestType}}
+	{{- if eq .RequestEncoding "json"}}
 	if ok := req.ReadAndUnmarshalBody(&requestBody); !ok {
+	{{- else}}
+	if ok := req.ReadAndUnmarshalForm(&requestBody); !ok {
+	{{- end}}
 		return
 	}
 	{{end}}
 
+	{{- range $idx, $segment := .PathSegments -}}
+	{{- range $line := $segment.ParseGoStatements}}
+	{{$line}}
+	{{- end}}
+	{{- end}}
+
 	{{range $headerName, $headerInfo := .ReqHeaderFields}}
 	{{camel $headerName}}Value, _ := req.Header.Get("{{$headerName}}")
 	{{if $headerInfo.IsPointer}}
@@ -182,7 +357,7 @@ func (handler *{{$handlerName}}) HandleRequest(
 	{{end}}
 
 	workflow := {{$workflow}}{
-		Clients: handler.Clients,
+		Clients: &handler.Dependencies.Client,
 		Logger: req.Logger,
 		Request: req,
 	}
@@ -243,7 +418,7 @@ func (handler *{{$handlerName}}) HandleRequest(
 
 // {{$workflow}} calls thrift client {{$clientName}}.{{$clientMethodName}}
 type {{$workflow}} struct {
-	Clients *clients.Clients
+	Clients *module.ClientDependencies
 	Logger  *zap.Logger
 	Request *zanzibar.ServerHTTPRequest
 }
@@ -269,7 +444,7 @@ func (w {{$workflow}}) Handle(
 	r {{.RequestType}},
 ) ({{.ResponseType}}, zanzibar.Header, error) {
 {{- end}}
-	{{- if ne .RequestType "" -}}
+	{{- if and (ne .RequestType "") (not .ConvertRequestUsesHeaders) -}}
 	clientRequest := convertTo{{title .Name}}ClientRequest(r)
 	{{end}}
 	clientHeaders := map[string]string{}
@@ -283,6 +458,11 @@ func (w {{$workflow}}) Handle(
 		clientHeaders["{{index $reqHeaderMap $k}}"] = h
 	}
 	{{- end}}
+	{{- if and (ne .RequestType "") .ConvertRequestUsesHeaders}}
+	clientRequest := convertTo{{title .Name}}ClientRequest(
+		r, reqHeaders, zanzibar.ServerTChannelHeader(clientHeaders),
+	)
+	{{- end}}
 	{{if and (eq $clientReqType "") (eq $clientResType "")}}
 		{{if (eq (len $resHeaderMap) 0) -}}
 		_, err := w.Clients.{{$clientName}}.{{$clientMethodName}}(ctx, clientHeaders)
@@ -290,7 +470,7 @@ func (w {{$workflow}}) Handle(
 		cliRespHeaders, err := w.Clients.{{$clientName}}.{{$clientMethodName}}(ctx, clientHeaders)
 		{{- end }}
 	{{else if eq $clientReqType ""}}
-		{{if (eq (len $resHeaderMap) 0) -}}
+		{{if and (eq (len $resHeaderMap) 0) (not .ConvertResponseUsesHeaders) -}}
 		clientRespBody, _, err := w.Clients.{{$clientName}}.{{$clientMethodName}}(
 			ctx, clientHeaders,
 		)
@@ -310,7 +490,7 @@ func (w {{$workflow}}) Handle(
 		)
 		{{- end }}
 	{{else}}
-		{{if (eq (len $resHeaderMap) 0) -}}
+		{{if and (eq (len $resHeaderMap) 0) (not .ConvertResponseUsesHeaders) -}}
 		clientRespBody, _, err := w.Clients.{{$clientName}}.{{$clientMethodName}}(
 			ctx, clientHeaders, clientRequest,
 		)
@@ -360,13 +540,32 @@ func (w {{$workflow}}) Handle(
 	{{if eq .ResponseType "" -}}
 	return resHeaders, nil
 	{{- else -}}
+	{{if .ConvertResponseUsesHeaders -}}
+	// Client response headers are read case insensitively
+	clientResHeaders := zanzibar.ServerHTTPHeader{}
+	for k, v := range cliRespHeaders {
+		clientResHeaders.Set(k, v)
+	}
+	response := convert{{title .Name}}ClientResponse(
+		clientRespBody, clientResHeaders, resHeaders,
+	)
+	{{- else -}}
 	response := convert{{title .Name}}ClientResponse(clientRespBody)
+	{{- end}}
 	return response, resHeaders, nil
 	{{- end -}}
 }
 
 {{if and (ne .RequestType "") (ne $clientReqType "") -}}
+{{if .ConvertRequestUsesHeaders -}}
+func convertTo{{title .Name}}ClientRequest(
+	in {{.RequestType}},
+	inHeaders zanzibar.Header,
+	outHeaders zanzibar.Header,
+) {{$clientReqType}} {
+{{- else -}}
 func convertTo{{title .Name}}ClientRequest(in {{.RequestType}}) {{$clientReqType}} {
+{{- end}}
 	out := &{{unref $clientReqType}}{}
 
 	{{ range $key, $line := $method.ConvertRequestGoStatements -}}
@@ -391,7 +590,15 @@ func convert{{$methodName}}{{title $cException.Name}}(
 {{end}}
 
 {{if and (ne .ResponseType "") (ne $clientResType "") -}}
+{{if .ConvertResponseUsesHeaders -}}
+func convert{{title .Name}}ClientResponse(
+	in {{$clientResType}},
+	inHeaders zanzibar.Header,
+	outHeaders zanzibar.Header,
+) {{.ResponseType}} {
+{{- else -}}
 func convert{{title .Name}}ClientResponse(in {{$clientResType}}) {{.ResponseType}} {
+{{- end}}
 	out := &{{unref .ResponseType}}{}
 
 	{{ range $key, $line := $method.ConvertResponseGoStatements -}}
@@ -404,6 +611,7 @@ func convert{{title .Name}}ClientResponse(in {{$clientResType}}) {{.ResponseType
 
 {{end -}}
 {{end -}}
+{{block "endpoint_extra" .}}{{end}}
 `)
 
 func endpointTmplBytes() ([]byte, error) {
@@ -416,7 +624,30 @@ func endpointTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "endpoint.tmpl", size: 8520, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	info := bindataFileInfo{name: "endpoint.tmpl", size: 10087, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _endpoint_openapiTmpl = []byte(`{{- /* template to render the OpenAPI document of the gateway endpoints */ -}}
+
+package endpoints
+
+// openAPIDocument is the OpenAPI document of the http endpoints.
+var openAPIDocument = []byte({{.DocumentLiteral}})
+`)
+
+func endpoint_openapiTmplBytes() ([]byte, error) {
+	return _endpoint_openapiTmpl, nil
+}
+
+func endpoint_openapiTmpl() (*asset, error) {
+	bytes, err := endpoint_openapiTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "endpoint_openapi.tmpl", size: 216, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -472,8 +703,17 @@ func Register(g *zanzibar.Gateway) {
 				{{range $idx, $middleware := $e.Middlewares -}}
 				{{$middleware.Name}}.NewMiddleWare(
 					g,
+					{{- if $middleware.DependenciesPath}}
+					&{{$middleware.DependenciesAlias}}.Dependencies{
+						Client: {{$middleware.DependenciesAlias}}.ClientDependencies{
+							{{range $middleware.ClientDependencies -}}
+							{{.}}: g.Clients.(*clients.Clients).{{.}},
+							{{end -}}
+						},
+					},
+					{{- end}}
 						{{$middleware.Name}}.Options{
-						{{range $key, $value := $middleware.Options -}}
+						{{range $key, $value := $middleware.OptionLiterals -}}
 								{{$key}} : {{$value}},
 						{{end -}}
 						},
@@ -489,6 +729,15 @@ func Register(g *zanzibar.Gateway) {
 	g.TChannelRouter.Register("{{.Method.ThriftService}}", "{{.Method.Name}}", endpoints.{{.HandlerName}})
 	{{end -}}
 	{{end -}}
+	g.RegisterOpenAPI(openAPIDocument)
+}
+
+// ConfigSchema declares the config keys required by the modules of the
+// gateway, it is validated when the gateway is created.
+var ConfigSchema = zanzibar.ConfigSchema{
+	{{range $key, $type := .ConfigSchema -}}
+	"{{$key}}": "{{$type}}",
+	{{end -}}
 }
 `)
 
@@ -502,7 +751,7 @@ func endpoint_registerTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "endpoint_register.tmpl", size: 1728, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	info := bindataFileInfo{name: "endpoint_register.tmpl", size: 2338, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -513,9 +762,11 @@ package {{.PackageName}}
 
 import (
 	"bytes"
+	"io/ioutil"
 	"path/filepath"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/uber/zanzibar/test/lib/bench_gateway"
@@ -525,21 +776,24 @@ import (
 
 {{- $clientID := .ClientID }}
 {{with .Method -}}
-{{- $clientPackage := .Downstream.PackageName -}}
-{{- $clientMethod := .DownstreamMethod -}}
-{{- $clientMethodName := $clientMethod.Name | title -}}
-{{- $clientMethodRequestType := fullTypeName  ($clientMethod).RequestType ($clientPackage) -}}
-{{- $clientMethodResponseType := fullTypeName  ($clientMethod).ResponseType ($clientPackage) -}}
-{{- $headers := .ReqHeaders }}
+{{- $responseType := .ResponseType }}
 
 
 {{range $.TestStubs}}
+{{- $testStub := .}}
 
-func Test{{.HandlerID | Title}}{{.TestName | Title}}OKResponse(t *testing.T) {
+func Test{{.HandlerID | Title}}{{.TestName | Title}}{{if eq .EndpointStatusCode $.Method.OKStatusCode.Code}}OK{{else}}Error{{end}}Response(t *testing.T) {
 	var counter int
 
-	gateway, err := testGateway.CreateGateway(t, nil, &testGateway.Options{
-		KnownHTTPBackends: []string{"{{$clientID}}"},
+	gateway, err := testGateway.CreateGateway(t, {{if .ClientTimeout -}}
+	map[string]interface{}{
+		{{range .HTTPBackends -}}
+		"clients.{{.}}.responseHeaderTimeout": {{$testStub.ClientTimeout}},
+		{{end -}}
+	}
+	{{- else}}nil{{end}}, &testGateway.Options{
+		KnownHTTPBackends: []string{"{{$clientID}}"
+		{{- range .HTTPBackends}}{{if ne . $clientID}}, "{{.}}"{{end}}{{end}}},
 		TestBinary: filepath.Join(
 			getDirName(), "..", "..", "services", "{{.TestServiceName}}", "main.go",
 		),
@@ -550,7 +804,11 @@ func Test{{.HandlerID | Title}}{{.TestName | Title}}OKResponse(t *testing.T) {
 	defer gateway.Close()
 
 	{{range .ClientStubs}}
-	fake{{.ClientMethod | Title}} := func(w http.ResponseWriter, r *http.Request) {
+	{{.FuncName}} := func(w http.ResponseWriter, r *http.Request) {
+		{{if gt (len $testStub.ClientStubs) 1 -}}
+		assert.Equal(t, {{.Index}}, counter, "client calls out of order")
+		{{end -}}
+		counter++
 
 		{{range $k, $v := .ClientReqHeaders -}}
 		assert.Equal(
@@ -559,30 +817,55 @@ func Test{{.HandlerID | Title}}{{.TestName | Title}}OKResponse(t *testing.T) {
 			r.Header.Get("{{$k}}"))
 		{{end}}
 
+		{{- if len .ClientRequest}}
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err, "failed to read client request body") {
+			assert.JSONEq(t, ` + "`" + `{{.ClientRequestString}}` + "`" + `, string(body))
+		}
+		{{end}}
+
+		{{- if .ClientLatency}}
+		time.Sleep({{.ClientLatency}} * time.Millisecond)
+		{{end}}
+
 		{{range $k, $v := .ClientResHeaders -}}
 		w.Header().Set("{{$k}}", "{{$v}}")
 		{{end}}
-		w.WriteHeader({{$.Method.OKStatusCode.Code}})
-		
-		// TODO(zw): generate client response.
+		w.WriteHeader({{.StatusCode}})
+
 		if _, err := w.Write([]byte( ` + "`" + `{{.ClientResponseString}}` + "`" + `)); err != nil {
 			t.Fatal("can't write fake response")
 		}
-		counter++
 	}
+	{{end -}}
 
-	gateway.HTTPBackends()["{{$clientID}}"].HandleFunc(
-		"{{$clientMethod.HTTPMethod}}", "{{$clientMethod.HTTPPath}}", fake{{.ClientMethod | Title}},
+	{{range .ClientRoutes}}
+	{{if eq (len .Stubs) 1 -}}
+	gateway.HTTPBackends()["{{.ClientID}}"].HandleFunc(
+		"{{.HTTPMethod}}", "{{.HTTPPath}}", {{(index .Stubs 0).FuncName}},
 	)
-
-	{{end -}}
+	{{- else -}}
+	gateway.HTTPBackends()["{{.ClientID}}"].HandleFunc(
+		"{{.HTTPMethod}}", "{{.HTTPPath}}",
+		func(w http.ResponseWriter, r *http.Request) {
+			switch counter {
+			{{range .Stubs -}}
+			case {{.Index}}:
+				{{.FuncName}}(w, r)
+			{{end -}}
+			default:
+				t.Errorf("unexpected client call %d to %s", counter, r.URL.Path)
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		},
+	)
+	{{- end}}
+	{{end}}
 
 	headers := map[string]string{}
-	{{ if $headers -}}
 	{{range $k, $v := .EndpointReqHeaders -}}
 	headers["{{$k}}"] = "{{$v}}"
 	{{end}}
-	{{- end}}
 
 	res, err := gateway.MakeRequest(
 		"{{$.Method.HTTPMethod}}",
@@ -594,14 +877,22 @@ func Test{{.HandlerID | Title}}{{.TestName | Title}}OKResponse(t *testing.T) {
 		return
 	}
 
-	assert.Equal(t, {{$.Method.OKStatusCode.Code}}, res.StatusCode)
+	assert.Equal(t, {{.EndpointStatusCode}}, res.StatusCode)
 	{{range $k, $v := .EndpointResHeaders -}}
 	assert.Equal(
 		t,
 		"{{$v}}",
-		res.Header.Get("{{$k}}"))	
+		res.Header.Get("{{$k}}"))
+	{{end}}
+	{{- if and $responseType (len .EndpointResponse)}}
+	defer func() { _ = res.Body.Close() }()
+	data, err := ioutil.ReadAll(res.Body)
+	if !assert.NoError(t, err, "failed to read response body") {
+		return
+	}
+	assert.JSONEq(t, ` + "`" + `{{.EndpointResponseString}}` + "`" + `, string(data))
 	{{end}}
-	assert.Equal(t, 1, counter)
+	assert.Equal(t, {{len .ClientStubs}}, counter)
 }
 
 {{end -}}
@@ -618,7 +909,7 @@ func endpoint_testTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "endpoint_test.tmpl", size: 2501, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	info := bindataFileInfo{name: "endpoint_test.tmpl", size: 3590, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -630,9 +921,7 @@ package {{.PackageName}}
 import (
 	"bytes"
 	"context"
-	{{if .Method.DownstreamMethod.ResponseType -}}
 	"encoding/json"
-	{{end -}}
 	"path/filepath"
 	"testing"
 	{{if ne .Method.ResponseType "" -}}
@@ -661,7 +950,7 @@ import (
 {{- $counter := printf "test%sCounter" $clientMethodName -}}
 
 {{range $.TestStubs}}
-func Test{{title .HandlerID}}{{title .TestName}}OKResponse(t *testing.T) {
+func Test{{title .HandlerID}}{{title .TestName}}{{if eq .EndpointStatusCode $.Method.OKStatusCode.Code}}OK{{else}}Error{{end}}Response(t *testing.T) {
 	{{$counter}} := 0
 
 	gateway, err := testGateway.CreateGateway(t, map[string]interface{}{
@@ -704,7 +993,14 @@ func Test{{title .HandlerID}}{{title .TestName}}OKResponse(t *testing.T) {
 		resHeaders["{{$k}}"] = "{{$v}}"
 		{{end}}
 
-		{{if $clientMethod.ResponseType -}}
+		{{if .ExceptionType -}}
+		var exception {{.ExceptionType}}
+		err := json.Unmarshal([]byte(` + "`" + `{{.ClientResponseString}}` + "`" + `), &exception)
+		if err != nil {
+			t.Fatal("can't unmarshal client exception json to client exception struct")
+		}
+		return {{if $clientMethod.ResponseType}}nil, {{end}}resHeaders, &exception
+		{{else if $clientMethod.ResponseType -}}
 		var res {{unref $clientMethod.ResponseType}}
 		err := json.Unmarshal([]byte(` + "`" + `{{.ClientResponseString}}` + "`" + `), &res)
 		if err!= nil {
@@ -750,7 +1046,7 @@ func Test{{title .HandlerID}}{{title .TestName}}OKResponse(t *testing.T) {
 	{{end}}
 
 	assert.Equal(t, 1, {{$counter}})
-	assert.Equal(t, {{$.Method.OKStatusCode.Code}}, res.StatusCode)
+	assert.Equal(t, {{.EndpointStatusCode}}, res.StatusCode)
 	{{range $k, $v := .EndpointResHeaders -}}
 	assert.Equal(
 		t,
@@ -776,7 +1072,7 @@ func endpoint_test_tchannel_clientTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "endpoint_test_tchannel_client.tmpl", size: 3851, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	info := bindataFileInfo{name: "endpoint_test_tchannel_client.tmpl", size: 4178, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -799,52 +1095,87 @@ import (
 )
 
 {{- $clientID := .ClientID -}}
+{{- $exposedMethods := .ExposedMethods -}}
 {{- $clientName := .ExportType }}
 {{- $exportName := .ExportName}}
-{{range .Services}}
-// {{$clientName}} is the http client for service {{.Name}}.
+
+{{template "client_interface.tmpl" .}}
+
+// {{$clientName}} is the http client.
 type {{$clientName}} struct {
 	ClientID string
 	HTTPClient   *zanzibar.HTTPClient
 }
 
-// NewClient returns a new http client for service {{.Name}}.
+// NewClient returns a new http client.
 func {{$exportName}}(
 	gateway *zanzibar.Gateway,
 ) *{{$clientName}} {
 	ip := gateway.Config.MustGetString("clients.{{$clientID}}.ip")
 	port := gateway.Config.MustGetInt("clients.{{$clientID}}.port")
 
-	baseURL := "http://" + ip + ":" + strconv.Itoa(int(port))
+	opts := zanzibar.NewHTTPClientOptions(gateway.Config, "{{$clientID}}")
+	baseURL := opts.Scheme() + "://" + ip + ":" + strconv.Itoa(int(port))
 	return &{{$clientName}}{
 		ClientID: "{{$clientID}}",
-		HTTPClient: zanzibar.NewHTTPClient(gateway, baseURL),
+		HTTPClient: zanzibar.NewHTTPClientWithOptions(gateway, baseURL, opts),
 	}
 }
 
 {{/*  ========================= Method =========================  */ -}}
 
+{{range $svc := .Services}}
 {{range .Methods}}
+{{$serviceMethod := printf "%s::%s" $svc.Name .Name -}}
+{{$methodName := index $exposedMethods $serviceMethod | title -}}
+{{if $methodName -}}
+
+{{template "http_client_method.tmpl" (clientMethod $clientName $methodName .)}}
+{{end}} {{- /* <if $methodName> */ -}}
+{{end}} {{- /* <range .Methods> */ -}}
+{{end}} {{- /* <range .Services> */ -}}
+{{block "http_client_extra" .}}{{end}}
+`)
+
+func http_clientTmplBytes() ([]byte, error) {
+	return _http_clientTmpl, nil
+}
+
+func http_clientTmpl() (*asset, error) {
+	bytes, err := http_clientTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "http_client.tmpl", size: 1630, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
 
-// {{title .Name}} calls "{{.HTTPPath}}" endpoint.
+var _http_client_methodTmpl = []byte(`{{- /* template to render a method of a http client, shared by the http
+client and the gateway sdk templates */ -}}
+{{- $clientName := .ClientName -}}
+{{- $methodName := .MethodName -}}
+{{with .Method -}}
+// {{$methodName}} calls "{{.HTTPPath}}" endpoint.
 {{- if and (eq .RequestType "") (eq .ResponseType "") }}
-func (c *{{$clientName}}) {{title .Name}}(
+func (c *{{$clientName}}) {{$methodName}}(
 	ctx context.Context,
 	headers map[string]string,
 ) (map[string]string, error) {
 {{else if eq .RequestType "" }}
-func (c *{{$clientName}}) {{title .Name}}(
+func (c *{{$clientName}}) {{$methodName}}(
 	ctx context.Context,
 	headers map[string]string,
 ) ({{.ResponseType}}, map[string]string, error) {
 {{else if eq .ResponseType "" }}
-func (c *{{$clientName}}) {{title .Name}}(
+func (c *{{$clientName}}) {{$methodName}}(
 	ctx context.Context,
 	headers map[string]string,
 	r {{.RequestType}},
 ) (map[string]string, error) {
 {{else}}
-func (c *{{$clientName}}) {{title .Name}}(
+func (c *{{$clientName}}) {{$methodName}}(
 	ctx context.Context,
 	headers map[string]string,
 	r {{.RequestType}},
@@ -866,12 +1197,18 @@ func (c *{{$clientName}}) {{title .Name}}(
 	fullURL := c.HTTPClient.BaseURL
 	{{- range $k, $segment := .PathSegments -}}
 	{{- if eq $segment.Type "static" -}}+"/{{$segment.Text}}"
-	{{- else -}}+"/"+string(r{{$segment.BodyIdentifier | title}})
+	{{- else -}}+"/"+{{$segment.ClientValue}}
 	{{- end -}}
 	{{- end}}
 
 	{{if ne .RequestType ""}}
+	{{- if eq .RequestEncoding "form"}}
+	err := req.WriteForm("{{.HTTPMethod}}", fullURL, headers, r)
+	{{- else if eq .RequestEncoding "multipart"}}
+	err := req.WriteMultipart("{{.HTTPMethod}}", fullURL, headers, r)
+	{{- else}}
 	err := req.WriteJSON("{{.HTTPMethod}}", fullURL, headers, r)
+	{{- end}}
 	{{else}}
 	err := req.WriteJSON("{{.HTTPMethod}}", fullURL, headers, nil)
 	{{end}} {{- /* <if .RequestType ne ""> */ -}}
@@ -985,21 +1322,20 @@ func (c *{{$clientName}}) {{title .Name}}(
 		"Unexpected http client response (%d)", res.StatusCode,
 	)
 }
-{{end}} {{- /* <range .Methods> */ -}}
-{{end}} {{- /* <range .Services> */ -}}
+{{- end}}
 `)
 
-func http_clientTmplBytes() ([]byte, error) {
-	return _http_clientTmpl, nil
+func http_client_methodTmplBytes() ([]byte, error) {
+	return _http_client_methodTmpl, nil
 }
 
-func http_clientTmpl() (*asset, error) {
-	bytes, err := http_clientTmplBytes()
+func http_client_methodTmpl() (*asset, error) {
+	bytes, err := http_client_methodTmplBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "http_client.tmpl", size: 5436, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	info := bindataFileInfo{name: "http_client_method.tmpl", size: 4687, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -1016,7 +1352,8 @@ import (
 )
 
 // Clients datastructure that holds all the generated clients
-// This should only hold clients generate from specs
+// This should only hold clients generate from specs, generated clients
+// are held by their interface so they can be replaced by mocks
 type Clients struct {
 	{{range $idx, $clientInfo := .ClientInfo -}}
 	{{$clientInfo.FieldName}} {{if $clientInfo.IsPointerType}}*{{end}}{{$clientInfo.PackageAlias}}.{{$clientInfo.ExportType}}
@@ -1045,7 +1382,7 @@ func init_clientsTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "init_clients.tmpl", size: 802, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	info := bindataFileInfo{name: "init_clients.tmpl", size: 885, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -1055,6 +1392,7 @@ var _mainTmpl = []byte(`{{- /* template to render gateway main.go */ -}}
 package main
 
 import (
+	"flag"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -1079,19 +1417,22 @@ func getConfigDirName() string {
 	)
 }
 
+// configLayers selects the environment and datacenter config files
+var configLayers = zanzibar.NewConfigLayersFromEnv()
+
 func getConfig() *zanzibar.StaticConfig {
-	return zanzibar.NewStaticConfigOrDie([]string{
+	return configLayers.NewStaticConfigOrDie([]string{
 		// TODO: zanzibar-defaults should be bundled in the binary
 		filepath.Join(getDirName(), "zanzibar-defaults.json"),
-		filepath.Join(getConfigDirName(), "production.json"),
-		filepath.Join(os.Getenv("CONFIG_DIR"), "production.json"),
-	}, nil)
+	}, getConfigDirName(), os.Getenv("CONFIG_DIR"))
 }
 
 func createGateway() (*zanzibar.Gateway, error) {
 	config := getConfig()
 	
-	gateway, err := zanzibar.CreateGateway(config, nil)
+	gateway, err := zanzibar.CreateGateway(config, &zanzibar.Options{
+		ConfigSchema: endpoints.ConfigSchema,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -1107,6 +1448,7 @@ func logAndWait(server *zanzibar.Gateway) {
 		zap.String("realHTTPAddr", server.RealHTTPAddr),
 		zap.String("realTChannelAddr", server.RealTChannelAddr),
 		zap.Any("config", server.InspectOrDie()),
+		zap.Any("configSources", server.InspectConfigSourcesOrDie()),
 	)
 
 	// TODO: handle sigterm gracefully
@@ -1116,6 +1458,9 @@ func logAndWait(server *zanzibar.Gateway) {
 }
 
 func main() {
+	configLayers.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
 	server, err := createGateway()
 	if err != nil {
 		panic(err)
@@ -1139,7 +1484,7 @@ func mainTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "main.tmpl", size: 1679, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	info := bindataFileInfo{name: "main.tmpl", size: 1919, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -1238,6 +1583,141 @@ func main_testTmpl() (*asset, error) {
 	return a, nil
 }
 
+var _middleware_scaffoldTmpl = []byte(`{{- /* template to render the skeleton of a new middleware */ -}}
+{{- $typeName := printf "%sMiddleware" (camel .Name) -}}
+package {{.PackageName}}
+
+import (
+	"context"
+
+	"github.com/mcuadros/go-jsonschema-generator"
+	zanzibar "github.com/uber/zanzibar/runtime"
+)
+
+type {{$typeName}} struct {
+	options Options
+}
+
+// Options for middleware configuration
+type Options struct{}
+
+// MiddlewareState accessible by other middlewares and endpoint handler
+// though the context object.
+type MiddlewareState struct{}
+
+// NewMiddleWare creates a new middleware that executes the next middleware
+// after performing it's operations.
+func NewMiddleWare(
+	gateway *zanzibar.Gateway,
+	options Options) zanzibar.MiddlewareHandle {
+	return &{{$typeName}}{
+		options: options,
+	}
+}
+
+// HandleRequest handles the requests before calling lower level middlewares.
+func (m *{{$typeName}}) HandleRequest(
+	ctx context.Context,
+	req *zanzibar.ServerHTTPRequest,
+	res *zanzibar.ServerHTTPResponse,
+	shared zanzibar.SharedState,
+) bool {
+	return true
+}
+
+// HandleResponse handles the responses after the lower level middlewares.
+func (m *{{$typeName}}) HandleResponse(
+	ctx context.Context,
+	res *zanzibar.ServerHTTPResponse,
+	shared zanzibar.SharedState,
+) {
+}
+
+// JSONSchema returns a schema definition of the configuration options for a middlware
+func (m *{{$typeName}}) JSONSchema() *jsonschema.Document {
+	s := &jsonschema.Document{}
+	s.Read(&Options{})
+	return s
+}
+
+func (m *{{$typeName}}) Name() string {
+	return "{{.Name}}"
+}
+`)
+
+func middleware_scaffoldTmplBytes() ([]byte, error) {
+	return _middleware_scaffoldTmpl, nil
+}
+
+func middleware_scaffoldTmpl() (*asset, error) {
+	bytes, err := middleware_scaffoldTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "middleware_scaffold.tmpl", size: 1509, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sdkTmpl = []byte(`{{- /* template to render the go client of a gateway endpoint group */ -}}
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/uber/zanzibar/runtime"
+	{{range $idx, $pkg := .IncludedPackages -}}
+	{{$pkg.AliasName}} "{{$pkg.PackageName}}"
+	{{end}}
+)
+
+{{- $clientName := .ExportType }}
+{{- $exportName := .ExportName}}
+
+// {{$clientName}} is the http client of the {{.EndpointID}} endpoints.
+type {{$clientName}} struct {
+	ClientID string
+	HTTPClient   *zanzibar.HTTPClient
+}
+
+// {{$exportName}} returns a client of the gateway at baseURL, requests are
+// sent with http.DefaultClient if client is nil.
+func {{$exportName}}(
+	baseURL string, client *http.Client,
+) *{{$clientName}} {
+	return &{{$clientName}}{
+		ClientID: "{{.EndpointID}}",
+		HTTPClient: zanzibar.NewStandaloneHTTPClient(baseURL, client, nil),
+	}
+}
+
+{{/*  ========================= Method =========================  */ -}}
+
+{{range .Methods}}
+{{template "http_client_method.tmpl" .}}
+{{end}} {{- /* <range .Methods> */ -}}
+`)
+
+func sdkTmplBytes() ([]byte, error) {
+	return _sdkTmpl, nil
+}
+
+func sdkTmpl() (*asset, error) {
+	bytes, err := sdkTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sdk.tmpl", size: 1045, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _structsTmpl = []byte(`{{- /* template to render edge gateway http client code */ -}}
 
 package {{.PackageName}}
@@ -1295,6 +1775,9 @@ import (
 {{$exposedMethods := .ExposedMethods -}}
 {{- $clientName := .ExportType }}
 {{- $exportName := .ExportName}}
+
+{{template "client_interface.tmpl" .}}
+
 // NewClient returns a new TChannel client for service {{$clientID}}.
 func {{$exportName}}(gateway *zanzibar.Gateway) *{{$clientName}} {
 	{{- /* this is the service discovery service name */}}
@@ -1319,6 +1802,10 @@ func {{$exportName}}(gateway *zanzibar.Gateway) *{{$clientName}} {
 			ServiceName:       serviceName,
 			Timeout:           timeout,
 			TimeoutPerAttempt: timeoutPerAttempt,
+			ClientID:          "{{$clientID}}",
+			RecordDir: zanzibar.TChannelClientRecordDir(
+				gateway.Config, "{{$clientID}}",
+			),
 		},
 	)
 
@@ -1382,6 +1869,7 @@ type {{$clientName}} struct {
 {{end -}}
 {{end -}}
 {{end}}
+{{block "tchannel_client_extra" .}}{{end}}
 `)
 
 func tchannel_clientTmplBytes() ([]byte, error) {
@@ -1394,7 +1882,7 @@ func tchannel_clientTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tchannel_client.tmpl", size: 3254, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	info := bindataFileInfo{name: "tchannel_client.tmpl", size: 3468, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -1544,21 +2032,28 @@ import (
 {{$serviceMethod := printf "%s%s" .Method.ThriftService .Method.Name -}}
 {{$handlerName := printf "%sHandler"  $serviceMethod -}}
 {{$genCodePkg := .Method.GenCodePkgName -}}
+{{$clientDependencies := .ClientDependencies -}}
 {{with .Method -}}
 // New{{$handlerName}} creates a handler to be registered with a thrift server.
 func New{{$handlerName}}(
 	gateway *zanzibar.Gateway,
 ) zanzibar.TChannelHandler {
 	return &{{$handlerName}}{
-		Clients: gateway.Clients.(*clients.Clients),
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				{{range $clientDependencies -}}
+				{{.}}: gateway.Clients.(*clients.Clients).{{.}},
+				{{end -}}
+			},
+		},
 		Logger: gateway.Logger,
 	}
 }
 
 // {{$handlerName}} is the handler for "{{.ThriftService}}::{{.Name}}".
 type {{$handlerName}} struct {
-	Clients *clients.Clients
-	Logger *zap.Logger
+	Dependencies *module.Dependencies
+	Logger       *zap.Logger
 }
 
 // Handle handles RPC call of "{{.ThriftService}}::{{.Name}}".
@@ -1584,7 +2079,7 @@ func (h *{{$handlerName}}) Handle(
 	{{end -}}
 
 	workflow := {{$workflow}}{
-		Clients: h.Clients,
+		Clients: &h.Dependencies.Client,
 		Logger: h.Logger,
 	}
 
@@ -1651,7 +2146,7 @@ func tchannel_endpointTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tchannel_endpoint.tmpl", size: 3186, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	info := bindataFileInfo{name: "tchannel_endpoint.tmpl", size: 3406, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -1708,15 +2203,22 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
+	"client_interface.tmpl":              client_interfaceTmpl,
+	"client_mock.tmpl":                   client_mockTmpl,
+	"client_scaffold_thrift.tmpl":        client_scaffold_thriftTmpl,
 	"dependency_struct.tmpl":             dependency_structTmpl,
 	"endpoint.tmpl":                      endpointTmpl,
+	"endpoint_openapi.tmpl":              endpoint_openapiTmpl,
 	"endpoint_register.tmpl":             endpoint_registerTmpl,
 	"endpoint_test.tmpl":                 endpoint_testTmpl,
 	"endpoint_test_tchannel_client.tmpl": endpoint_test_tchannel_clientTmpl,
 	"http_client.tmpl":                   http_clientTmpl,
+	"http_client_method.tmpl":            http_client_methodTmpl,
 	"init_clients.tmpl":                  init_clientsTmpl,
 	"main.tmpl":                          mainTmpl,
 	"main_test.tmpl":                     main_testTmpl,
+	"middleware_scaffold.tmpl":           middleware_scaffoldTmpl,
+	"sdk.tmpl":                           sdkTmpl,
 	"structs.tmpl":                       structsTmpl,
 	"tchannel_client.tmpl":               tchannel_clientTmpl,
 	"tchannel_client_test_server.tmpl":   tchannel_client_test_serverTmpl,
@@ -1764,15 +2266,22 @@ type bintree struct {
 }
 
 var _bintree = &bintree{nil, map[string]*bintree{
+	"client_interface.tmpl":              {client_interfaceTmpl, map[string]*bintree{}},
+	"client_mock.tmpl":                   {client_mockTmpl, map[string]*bintree{}},
+	"client_scaffold_thrift.tmpl":        {client_scaffold_thriftTmpl, map[string]*bintree{}},
 	"dependency_struct.tmpl":             {dependency_structTmpl, map[string]*bintree{}},
 	"endpoint.tmpl":                      {endpointTmpl, map[string]*bintree{}},
+	"endpoint_openapi.tmpl":              {endpoint_openapiTmpl, map[string]*bintree{}},
 	"endpoint_register.tmpl":             {endpoint_registerTmpl, map[string]*bintree{}},
 	"endpoint_test.tmpl":                 {endpoint_testTmpl, map[string]*bintree{}},
 	"endpoint_test_tchannel_client.tmpl": {endpoint_test_tchannel_clientTmpl, map[string]*bintree{}},
 	"http_client.tmpl":                   {http_clientTmpl, map[string]*bintree{}},
+	"http_client_method.tmpl":            {http_client_methodTmpl, map[string]*bintree{}},
 	"init_clients.tmpl":                  {init_clientsTmpl, map[string]*bintree{}},
 	"main.tmpl":                          {mainTmpl, map[string]*bintree{}},
 	"main_test.tmpl":                     {main_testTmpl, map[string]*bintree{}},
+	"middleware_scaffold.tmpl":           {middleware_scaffoldTmpl, map[string]*bintree{}},
+	"sdk.tmpl":                           {sdkTmpl, map[string]*bintree{}},
 	"structs.tmpl":                       {structsTmpl, map[string]*bintree{}},
 	"tchannel_client.tmpl":               {tchannel_clientTmpl, map[string]*bintree{}},
 	"tchannel_client_test_server.tmpl":   {tchannel_client_test_serverTmpl, map[string]*bintree{}},
@@ -66,6 +66,7 @@ func TestGenerateBar(t *testing.T) {
 		"github.com/uber/zanzibar/examples/example-gateway/build/gen-code",
 		tmpDir,
 		testCopyrightHeader,
+		"",
 	)
 	if !assert.NoError(t, err, "failed to create package helper", err) {
 		return
@@ -103,11 +104,23 @@ func TestGenerateBar(t *testing.T) {
 		filepath.Join(tmpDir, "clients", "bar", "bar.go"),
 		"./test_data/clients",
 	)
+	cmpGoldenFile(
+		t,
+		filepath.Join(tmpDir, "clients", "bar", "mock-client", "mock_client.go"),
+		"./test_data/clients",
+	)
 
 	err = gateway.GenerateEndpointRegisterFile()
 	if !assert.NoError(t, err, "failed to create endpoint index %s", err) {
 		return
 	}
+	cmpGoldenFile(t, filepath.Join(tmpDir, "openapi.json"), "./test_data")
+
+	err = gateway.GenerateSDK()
+	if !assert.NoError(t, err, "failed to create endpoint sdk %s", err) {
+		return
+	}
+	cmpGoldenFile(t, filepath.Join(tmpDir, "sdk", "bar", "bar.go"), "./test_data/sdk")
 
 	endpoints, err := ioutil.ReadDir(
 		filepath.Join(tmpDir, "endpoints", "bar"),
@@ -117,6 +130,9 @@ func TestGenerateBar(t *testing.T) {
 	}
 
 	for _, file := range endpoints {
+		if file.IsDir() {
+			continue
+		}
 		footer := file.Name()[len(file.Name())-8 : len(file.Name())]
 		if footer == "_test.go" {
 			continue
@@ -133,8 +149,16 @@ func TestGenerateBar(t *testing.T) {
 		filepath.Join(tmpDir, "endpoints", "bar", "bar_structs.go"),
 		"./test_data/endpoints",
 	)
+	cmpGoldenFile(
+		t,
+		filepath.Join(tmpDir, "endpoints", "bar", "module", "dependencies.go"),
+		"./test_data/endpoints",
+	)
 
 	for _, file := range endpoints {
+		if file.IsDir() {
+			continue
+		}
 		footer := file.Name()[len(file.Name())-8 : len(file.Name())]
 		if footer != "_test.go" {
 			continue
@@ -147,3 +171,80 @@ func TestGenerateBar(t *testing.T) {
 		)
 	}
 }
+
+func TestTemplateOverrides(t *testing.T) {
+	overrideDir, err := ioutil.TempDir("", "zanzibar-overrides")
+	if !assert.NoError(t, err, "failed to create temp dir") {
+		return
+	}
+	genDir, err := ioutil.TempDir("", "zanzibar-overrides-build")
+	if !assert.NoError(t, err, "failed to create temp dir") {
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(overrideDir)
+		_ = os.RemoveAll(genDir)
+	}()
+
+	overrides := map[string]string{
+		"extras.tmpl": `{{define "http_client_extra"}}
+// ClientID is the id of the client
+const ClientID = "{{.ClientID}}"
+{{end}}`,
+		"endpoint.tmpl": `{{template "zanzibar/endpoint.tmpl" .}}
+// {{title .Method.Name}}Overridden is set by the endpoint override
+const {{title .Method.Name}}Overridden = true
+`,
+	}
+	for name, content := range overrides {
+		err := ioutil.WriteFile(
+			filepath.Join(overrideDir, name), []byte(content), 0644,
+		)
+		if !assert.NoError(t, err, "failed to write %s", name) {
+			return
+		}
+	}
+
+	absGatewayPath, err := filepath.Abs("../examples/example-gateway")
+	if !assert.NoError(t, err, "failed to get abs path %s", err) {
+		return
+	}
+	packageHelper, err := codegen.NewPackageHelper(
+		"github.com/uber/zanzibar/examples/example-gateway",
+		absGatewayPath,
+		"middlewares/middleware-config.json",
+		filepath.Join(absGatewayPath, "idl"),
+		"github.com/uber/zanzibar/examples/example-gateway/build/gen-code",
+		genDir,
+		testCopyrightHeader,
+		overrideDir,
+	)
+	if !assert.NoError(t, err, "failed to create package helper", err) {
+		return
+	}
+
+	moduleSystem, err := codegen.NewDefaultModuleSystem(packageHelper)
+	if !assert.NoError(t, err, "failed to create module system", err) {
+		return
+	}
+	_, err = moduleSystem.GenerateBuild(
+		"github.com/uber/zanzibar/examples/example-gateway",
+		absGatewayPath,
+		packageHelper.CodeGenTargetPath(),
+	)
+	if !assert.NoError(t, err, "failed to generate build %s", err) {
+		return
+	}
+
+	client, err := ioutil.ReadFile(filepath.Join(genDir, "clients", "bar", "bar.go"))
+	if assert.NoError(t, err, "failed to read bar client") {
+		assert.Contains(t, string(client), `const ClientID = "bar"`)
+	}
+	endpoint, err := ioutil.ReadFile(
+		filepath.Join(genDir, "endpoints", "bar", "bar_bar_method_normal.go"),
+	)
+	if assert.NoError(t, err, "failed to read bar endpoint") {
+		assert.Contains(t, string(endpoint), "func (handler *NormalHandler) HandleRequest(")
+		assert.Contains(t, string(endpoint), "const NormalOverridden = true")
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package codegen
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TestStub saves stubbed requests/responses for an endpoint test.
+type TestStub struct {
+	TestName               string
+	EndpointID             string
+	HandlerID              string
+	EndpointRequest        map[string]interface{} // Json blob
+	EndpointRequestString  string
+	EndpointReqHeaders     map[string]string      // Json blob
+	EndpointReqHeaderKeys  []string               // To keep in canonical order
+	EndpointResponse       map[string]interface{} // Json blob
+	EndpointResponseString string
+	EndpointResHeaders     map[string]string // Json blob
+	EndpointResHeaderKeys  []string          // To keep in canonical order
+	// EndpointStatusCode is the expected status code of the endpoint, the
+	// OK status code of the endpoint method if zero.
+	EndpointStatusCode int
+
+	// ClientStubs are the expected client calls in order.
+	ClientStubs []ClientStub
+	// ClientStubsFile is a file of recorded client calls, relative to the
+	// endpoint config file, that are expected after the ClientStubs.
+	ClientStubsFile string
+	// ClientTimeout is the response timeout of the stubbed http clients in
+	// milliseconds, the client default if zero.
+	ClientTimeout int
+	// ClientRoutes are the routes of the stubbed http clients.
+	ClientRoutes []*ClientStubRoute `json:"-"`
+	// HTTPBackends are the ids of the stubbed http clients.
+	HTTPBackends []string `json:"-"`
+
+	TestServiceName string // The service module that mounts the endpoint
+}
+
+// ClientStub saves stubbed client request/response for an endpoint test.
+type ClientStub struct {
+	ClientID             string
+	ClientMethod         string
+	ClientRequest        map[string]interface{} // Json blob
+	ClientRequestString  string
+	ClientReqHeaders     map[string]string      // Json blob
+	ClientReqHeaderKeys  []string               // To keep in canonical order
+	ClientResponse       map[string]interface{} // Json blob
+	ClientResponseString string
+	ClientResHeaders     map[string]string // Json blob
+	ClientResHeaderKeys  []string          // To keep in canonical order
+	// ClientException is returned by the stub instead of the response.
+	ClientException *ClientExceptionStub
+	// ClientLatency delays the stub response by milliseconds.
+	ClientLatency int
+	// ClientStatusCode is the status code of a recorded http response, the
+	// status code of the client method or exception if zero.
+	ClientStatusCode int
+
+	// Index is the position of the call in the expected client calls.
+	Index int `json:"-"`
+	// FuncName is the name of the stub function in the generated test.
+	FuncName string `json:"-"`
+	// StatusCode is the status code of the stub response.
+	StatusCode int `json:"-"`
+	// ExceptionType is the go type of the exception of a tchannel stub.
+	ExceptionType string `json:"-"`
+}
+
+// ClientExceptionStub is an exception returned by a client stub.
+type ClientExceptionStub struct {
+	// Name is the name of the exception in the thrift method.
+	Name string
+	Body map[string]interface{} // Json blob
+}
+
+// ClientStubRoute groups the client stubs of a http route, in order.
+type ClientStubRoute struct {
+	ClientID   string
+	HTTPMethod string
+	HTTPPath   string
+	Stubs      []*ClientStub
+}
+
+// readTestStubs reads the test fixtures of an endpoint, the fixtures of
+// the endpoint config followed by the fixtures of its test config file.
+func readTestStubs(e *EndpointSpec) ([]TestStub, error) {
+	testStubs := append([]TestStub{}, e.TestFixtures...)
+
+	file, err := ioutil.ReadFile(e.EndpointTestConfigPath())
+	if err != nil {
+		// If the test file does not exist then only use the fixtures of
+		// the endpoint config.
+		if os.IsNotExist(err) {
+			return testStubs, nil
+		}
+		return nil, errors.Wrapf(err,
+			"Error reading endpoint test config for service %q, method %q",
+			e.ThriftServiceName, e.ThriftMethodName)
+	}
+
+	var fileStubs []TestStub
+	if err := json.Unmarshal(file, &fileStubs); err != nil {
+		return nil, errors.Wrapf(err,
+			"Error parsing test config file.")
+	}
+	testStubs = append(testStubs, fileStubs...)
+
+	for i := range testStubs {
+		if err := readClientStubsFile(e, &testStubs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return testStubs, nil
+}
+
+// readClientStubsFile appends the recorded client calls of the client stubs
+// file of a test stub to its client stubs.
+func readClientStubsFile(e *EndpointSpec, testStub *TestStub) error {
+	if testStub.ClientStubsFile == "" {
+		return nil
+	}
+	path := filepath.Join(filepath.Dir(e.JSONFile), testStub.ClientStubsFile)
+	file, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err,
+			"Error reading client stubs file of test %q", testStub.TestName)
+	}
+	var clientStubs []ClientStub
+	if err := json.Unmarshal(file, &clientStubs); err != nil {
+		return errors.Wrapf(err,
+			"Error parsing client stubs file of test %q", testStub.TestName)
+	}
+	testStub.ClientStubs = append(testStub.ClientStubs, clientStubs...)
+	return nil
+}
+
+// resolveTestStub validates a test stub of an endpoint method and fills in
+// the values used by the endpoint test templates. The client stubs are
+// resolved against the given http clients, if any.
+func resolveTestStub(
+	testStub *TestStub, method *MethodSpec, httpClients []*ClientSpec,
+) error {
+	var err error
+	testStub.EndpointRequestString, err = jsonMarshal(
+		testStub.EndpointRequest)
+	if err != nil {
+		return errors.Wrapf(err,
+			"Error parsing JSON in test config.")
+	}
+	testStub.EndpointResponseString, err = jsonMarshal(
+		testStub.EndpointResponse)
+	if err != nil {
+		return errors.Wrapf(err,
+			"Error parsing JSON in test config.")
+	}
+	// Build canonical key list to keep templates in order
+	// when comparing to golden files.
+	testStub.EndpointReqHeaderKeys = sortedKeys(testStub.EndpointReqHeaders)
+	testStub.EndpointResHeaderKeys = sortedKeys(testStub.EndpointResHeaders)
+	if testStub.EndpointStatusCode == 0 {
+		testStub.EndpointStatusCode = method.OKStatusCode.Code
+	}
+
+	funcNames := map[string]int{}
+	for j := 0; j < len(testStub.ClientStubs); j++ {
+		clientStub := &testStub.ClientStubs[j]
+		clientStub.Index = j
+		clientStub.ClientRequestString, err = jsonMarshal(
+			clientStub.ClientRequest)
+		if err != nil {
+			return errors.Wrapf(err,
+				"Error parsing JSON in test config.")
+		}
+		clientStub.ClientResponseString, err = jsonMarshal(
+			clientStub.ClientResponse)
+		if err != nil {
+			return errors.Wrapf(err,
+				"Error parsing JSON in test config.")
+		}
+		clientStub.ClientReqHeaderKeys = sortedKeys(clientStub.ClientReqHeaders)
+		clientStub.ClientResHeaderKeys = sortedKeys(clientStub.ClientResHeaders)
+		funcNames[clientStub.ClientMethod]++
+	}
+
+	for j := 0; j < len(testStub.ClientStubs); j++ {
+		clientStub := &testStub.ClientStubs[j]
+		clientStub.FuncName = "fake" + strings.Title(clientStub.ClientMethod)
+		if funcNames[clientStub.ClientMethod] > 1 {
+			clientStub.FuncName += strconv.Itoa(j)
+		}
+
+		if httpClients == nil {
+			if clientStub.ClientLatency != 0 || testStub.ClientTimeout != 0 {
+				return errors.Errorf(
+					"Test %q: client latency and timeouts are only "+
+						"supported for http clients",
+					testStub.TestName,
+				)
+			}
+			err := resolveTChannelException(clientStub, method.DownstreamMethod)
+			if err != nil {
+				return errors.Wrapf(err, "Test %q", testStub.TestName)
+			}
+			continue
+		}
+		if err := resolveClientStub(testStub, clientStub, httpClients); err != nil {
+			return errors.Wrapf(err, "Test %q", testStub.TestName)
+		}
+	}
+	return nil
+}
+
+// resolveClientStub resolves the route and response of a http client stub
+// and adds it to the routes of the test stub.
+func resolveClientStub(
+	testStub *TestStub, clientStub *ClientStub, httpClients []*ClientSpec,
+) error {
+	var clientSpec *ClientSpec
+	for _, spec := range httpClients {
+		if spec.ClientID == clientStub.ClientID {
+			clientSpec = spec
+		}
+	}
+	if clientSpec == nil {
+		return errors.Errorf(
+			"client stub %d calls unknown http client %q",
+			clientStub.Index, clientStub.ClientID,
+		)
+	}
+
+	method, err := findExposedMethod(clientSpec, clientStub.ClientMethod)
+	if err != nil {
+		return errors.Wrapf(err, "client stub %d", clientStub.Index)
+	}
+	if method == nil {
+		return errors.Errorf(
+			"client stub %d calls unknown method %q of client %q",
+			clientStub.Index, clientStub.ClientMethod, clientStub.ClientID,
+		)
+	}
+
+	clientStub.StatusCode = method.OKStatusCode.Code
+	if clientStub.ClientStatusCode != 0 {
+		clientStub.StatusCode = clientStub.ClientStatusCode
+	}
+	if exception := clientStub.ClientException; exception != nil {
+		exceptionSpec, ok := method.ExceptionsIndex[exception.Name]
+		if !ok {
+			return errors.Errorf(
+				"client stub %d returns unknown exception %q of method %q",
+				clientStub.Index, exception.Name, method.Name,
+			)
+		}
+		clientStub.StatusCode = exceptionSpec.StatusCode.Code
+		clientStub.ClientResponseString, err = jsonMarshal(exception.Body)
+		if err != nil {
+			return errors.Wrapf(err,
+				"Error parsing JSON in test config.")
+		}
+	}
+
+	if !containsString(testStub.HTTPBackends, clientSpec.ClientID) {
+		testStub.HTTPBackends = append(testStub.HTTPBackends, clientSpec.ClientID)
+	}
+	for _, route := range testStub.ClientRoutes {
+		if route.ClientID == clientSpec.ClientID &&
+			route.HTTPMethod == method.HTTPMethod &&
+			route.HTTPPath == method.HTTPPath {
+			route.Stubs = append(route.Stubs, clientStub)
+			return nil
+		}
+	}
+	testStub.ClientRoutes = append(testStub.ClientRoutes, &ClientStubRoute{
+		ClientID:   clientSpec.ClientID,
+		HTTPMethod: method.HTTPMethod,
+		HTTPPath:   method.HTTPPath,
+		Stubs:      []*ClientStub{clientStub},
+	})
+	return nil
+}
+
+// resolveTChannelException resolves the exception type and body of a
+// tchannel client stub.
+func resolveTChannelException(clientStub *ClientStub, method *MethodSpec) error {
+	exception := clientStub.ClientException
+	if exception == nil {
+		return nil
+	}
+	if method == nil {
+		return errors.Errorf(
+			"client stub %d returns exception %q without a client method",
+			clientStub.Index, exception.Name,
+		)
+	}
+	exceptionSpec, ok := method.ExceptionsIndex[exception.Name]
+	if !ok {
+		return errors.Errorf(
+			"client stub %d returns unknown exception %q of method %q",
+			clientStub.Index, exception.Name, method.Name,
+		)
+	}
+
+	var err error
+	clientStub.ExceptionType = exceptionSpec.Type
+	clientStub.ClientResponseString, err = jsonMarshal(exception.Body)
+	if err != nil {
+		return errors.Wrapf(err,
+			"Error parsing JSON in test config.")
+	}
+	return nil
+}
+
+// findExposedMethod finds a method of a client by its exposed name or its
+// thrift method name, ignoring case. It fails if the name matches more than
+// one method.
+func findExposedMethod(clientSpec *ClientSpec, name string) (*MethodSpec, error) {
+	var matches []string
+	for _, exposedName := range sortedKeys(clientSpec.ExposedMethods) {
+		serviceMethod := clientSpec.ExposedMethods[exposedName]
+		segments := strings.Split(serviceMethod, "::")
+		if len(segments) != 2 {
+			continue
+		}
+		if (strings.EqualFold(exposedName, name) ||
+			strings.EqualFold(segments[1], name)) &&
+			!containsString(matches, serviceMethod) {
+			matches = append(matches, serviceMethod)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		segments := strings.Split(matches[0], "::")
+		return findMethod(clientSpec.ModuleSpec, segments[0], segments[1]), nil
+	}
+	return nil, errors.Errorf(
+		"method %q of client %q is ambiguous, it matches %s",
+		name, clientSpec.ClientID, strings.Join(matches, ", "),
+	)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
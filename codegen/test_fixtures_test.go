@@ -0,0 +1,111 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package codegen_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/zanzibar/codegen"
+)
+
+func writeEndpointJSON(t *testing.T, testFixtures string) (string, func()) {
+	dir, err := ioutil.TempDir("", "zanzibar-fixtures")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	jsonFile := filepath.Join(dir, "endpoint.json")
+	err = ioutil.WriteFile(jsonFile, []byte(`{
+		"endpointType": "http",
+		"endpointId": "bar",
+		"handleId": "normal",
+		"thriftFile": "endpoints/bar/bar.thrift",
+		"thriftFileSha": "{{placeholder}}",
+		"thriftMethodName": "Bar::normal",
+		"workflowType": "httpClient",
+		"clientID": "bar",
+		"clientMethod": "normal",
+		"middlewares": [],
+		"reqHeaderMap": {},
+		"resHeaderMap": {},
+		"testFixtures": `+testFixtures+`
+	}`), 0644)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return jsonFile, func() {
+		assert.NoError(t, os.RemoveAll(dir))
+	}
+}
+
+func TestEndpointTestFixtures(t *testing.T) {
+	jsonFile, cleanup := writeEndpointJSON(t, `[{
+		"testName": "clientException",
+		"endpointStatusCode": 403,
+		"clientTimeout": 50,
+		"clientStubs": [{
+			"clientId": "bar",
+			"clientMethod": "normal",
+			"clientLatency": 10,
+			"clientException": {
+				"name": "barException",
+				"body": {"stringField": "foo"}
+			}
+		}]
+	}]`)
+	defer cleanup()
+
+	espec, err := codegen.NewEndpointSpec(jsonFile, newPackageHelper(t), nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, espec.TestFixtures, 1) {
+		return
+	}
+
+	fixture := espec.TestFixtures[0]
+	assert.Equal(t, "clientException", fixture.TestName)
+	assert.Equal(t, 403, fixture.EndpointStatusCode)
+	assert.Equal(t, 50, fixture.ClientTimeout)
+	if assert.Len(t, fixture.ClientStubs, 1) {
+		stub := fixture.ClientStubs[0]
+		assert.Equal(t, 10, stub.ClientLatency)
+		assert.Equal(t, &codegen.ClientExceptionStub{
+			Name: "barException",
+			Body: map[string]interface{}{"stringField": "foo"},
+		}, stub.ClientException)
+	}
+}
+
+func TestInvalidEndpointTestFixtures(t *testing.T) {
+	jsonFile, cleanup := writeEndpointJSON(t, `{"testName": "notAList"}`)
+	defer cleanup()
+
+	_, err := codegen.NewEndpointSpec(jsonFile, newPackageHelper(t), nil)
+	diag, ok := errors.Cause(err).(*codegen.Diagnostic)
+	if assert.True(t, ok, "expected diagnostic, got %v", err) {
+		assert.Equal(t, jsonFile, diag.File)
+		assert.Equal(t, "testFixtures", diag.Location)
+	}
+}
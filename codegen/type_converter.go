@@ -22,18 +22,54 @@ package codegen
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
 	"go.uber.org/thriftrw/compile"
 )
 
+// fieldMapHeaderPrefix marks a field map path as a header name rather
+// than a thrift field path.
+const fieldMapHeaderPrefix = "headers."
+
 // TypeConverter can generate a function body that converts two thriftrw
 // FieldGroups from one to another. It's assumed that the converted code
 // operates on two variables, "in" and "out" and that both are a go struct.
+// If the FieldMap uses headers, the code also operates on "inHeaders" and
+// "outHeaders", both of type zanzibar.Header.
 type TypeConverter struct {
 	Lines  []string
 	Helper PackageNameResolver
+	// FieldMap overrides the by name mapping for target fields. It is keyed
+	// by the dotted thrift path of the target field, e.g. "request.userUUID",
+	// or by "headers.<name>" to write a header from a source field.
+	FieldMap map[string]FieldMapperEntry
+
+	fromFields  []*compile.FieldSpec
+	usedKeys    map[string]bool
+	usesHeaders bool
+	// guarded holds the nil checks of the enclosing struct converters
+	guarded map[string]bool
+}
+
+// FieldMapperEntry describes how a single target field is populated.
+// Only one of QualifiedName, Constant or Drop should be set.
+type FieldMapperEntry struct {
+	// QualifiedName is the dotted thrift path of the source field,
+	// or "headers.<name>" to read the value from a header.
+	QualifiedName string
+	// Constant is the json value assigned to the target field.
+	Constant interface{}
+	// Drop leaves the target field unset.
+	Drop bool
+}
+
+// UsesHeaders returns true if the generated lines read from "inHeaders"
+// or write to "outHeaders".
+func (c *TypeConverter) UsesHeaders() bool {
+	return c.usesHeaders
 }
 
 // PackageNameResolver interface allows for resolving what the
@@ -113,6 +149,8 @@ func (c *TypeConverter) genConverterForStruct(
 	fromFieldType compile.TypeSpec,
 	fromIdentifier string,
 	keyPrefix string,
+	fromPrefix string,
+	thriftPrefix string,
 	indent string,
 ) error {
 	toIdentifier := "out." + keyPrefix
@@ -136,13 +174,19 @@ func (c *TypeConverter) genConverterForStruct(
 
 	c.append(indent, "	", toIdentifier, " = &", typeName, "{}")
 
+	guard := fromIdentifier + " != nil"
+	outerGuarded := c.guarded[guard]
+	c.guarded[guard] = true
 	subFromFields := fromFieldStruct.Fields
 	err = c.genStructConverter(
 		keyPrefix+".",
+		fromPrefix+".",
+		thriftPrefix+".",
 		indent+"	",
 		subFromFields,
 		subToFields,
 	)
+	c.guarded[guard] = outerGuarded
 	if err != nil {
 		return err
 	}
@@ -158,18 +202,27 @@ func (c *TypeConverter) genConverterForStruct(
 
 func (c *TypeConverter) genConverterForPrimitive(
 	toField *compile.FieldSpec,
+	fromField *compile.FieldSpec,
 	toIdentifier string,
 	fromIdentifier string,
+	indent string,
 ) error {
 	typeName, err := c.getGoTypeName(toField.Type)
 	if err != nil {
 		return err
 	}
 
-	if toField.Required {
-		c.append(toIdentifier, " = ", typeName, "(", fromIdentifier, ")")
-	} else {
-		c.append(toIdentifier, " = (*", typeName, ")(", fromIdentifier, ")")
+	switch {
+	case toField.Required && !fromField.Required:
+		c.append(indent, "if ", fromIdentifier, " != nil {")
+		c.append(indent, "	", toIdentifier, " = ", typeName, "(*", fromIdentifier, ")")
+		c.append(indent, "}")
+	case !toField.Required && fromField.Required:
+		c.append(indent, toIdentifier, " = (*", typeName, ")(&", fromIdentifier, ")")
+	case toField.Required:
+		c.append(indent, toIdentifier, " = ", typeName, "(", fromIdentifier, ")")
+	default:
+		c.append(indent, toIdentifier, " = (*", typeName, ")(", fromIdentifier, ")")
 	}
 	return nil
 }
@@ -181,6 +234,8 @@ func (c *TypeConverter) genConverterForList(
 	toIdentifier string,
 	fromIdentifier string,
 	keyPrefix string,
+	fromKey string,
+	thriftPath string,
 	indent string,
 ) error {
 	typeName, err := c.getGoTypeName(toFieldType.ValueSpec)
@@ -218,6 +273,8 @@ func (c *TypeConverter) genConverterForList(
 			fromFieldType.ValueSpec,
 			"value",
 			keyPrefix+strings.Title(toField.Name)+"[index]",
+			fromKey+"[index]",
+			thriftPath+"[]",
 			"	"+indent,
 		)
 		if err != nil {
@@ -238,6 +295,8 @@ func (c *TypeConverter) genConverterForMap(
 	toIdentifier string,
 	fromIdentifier string,
 	keyPrefix string,
+	fromKey string,
+	thriftPath string,
 	indent string,
 ) error {
 	typeName, err := c.getGoTypeName(toFieldType.ValueSpec)
@@ -283,6 +342,8 @@ func (c *TypeConverter) genConverterForMap(
 			fromFieldType.ValueSpec,
 			"value",
 			keyPrefix+strings.Title(toField.Name)+"[key]",
+			fromKey+"[key]",
+			thriftPath+"[]",
 			"	"+indent,
 		)
 		if err != nil {
@@ -296,122 +357,361 @@ func (c *TypeConverter) genConverterForMap(
 	return nil
 }
 
-func (c *TypeConverter) genStructConverter(
-	keyPrefix string,
+// genConverterForConstant assigns a json constant from the field map to
+// the target field.
+func (c *TypeConverter) genConverterForConstant(
+	toField *compile.FieldSpec,
+	toIdentifier string,
+	value interface{},
 	indent string,
-	fromFields []*compile.FieldSpec,
-	toFields []*compile.FieldSpec,
 ) error {
-	for i := 0; i < len(toFields); i++ {
-		toField := toFields[i]
+	typeName, err := c.getGoTypeName(toField.Type)
+	if err != nil {
+		return err
+	}
 
-		var fromField *compile.FieldSpec
-		for j := 0; j < len(fromFields); j++ {
-			if fromFields[j].Name == toField.Name {
-				fromField = fromFields[j]
-				break
+	literal, err := constantLiteral(toField, value)
+	if err != nil {
+		return err
+	}
+
+	// thriftrw does not use pointers for optional binary fields.
+	_, isBinary := compile.RootTypeSpec(toField.Type).(*compile.BinarySpec)
+	if toField.Required || isBinary {
+		c.append(indent, toIdentifier, " = ", typeName, "(", literal, ")")
+	} else {
+		c.append(indent, toIdentifier, " = new(", typeName, ")")
+		c.append(indent, "*", toIdentifier, " = ", typeName, "(", literal, ")")
+	}
+	return nil
+}
+
+// genConverterForHeader reads the target field from a header.
+func (c *TypeConverter) genConverterForHeader(
+	toField *compile.FieldSpec,
+	toIdentifier string,
+	headerName string,
+	indent string,
+) error {
+	if !isStringType(toField.Type) {
+		return errors.Errorf(
+			"could not map header %q to field %s, field is not a string",
+			headerName, toField.Name,
+		)
+	}
+
+	typeName, err := c.getGoTypeName(toField.Type)
+	if err != nil {
+		return err
+	}
+
+	c.usesHeaders = true
+	c.append(indent, "if h, ok := inHeaders.Get(", strconv.Quote(headerName), "); ok {")
+	if toField.Required {
+		c.append(indent, "	", toIdentifier, " = ", typeName, "(h)")
+	} else {
+		c.append(indent, "	", toIdentifier, " = (*", typeName, ")(&h)")
+	}
+	c.append(indent, "}")
+	return nil
+}
+
+// genHeaderConverters writes headers from source fields, for field map
+// entries keyed by "headers.<name>".
+func (c *TypeConverter) genHeaderConverters() error {
+	keys := make([]string, 0, len(c.FieldMap))
+	for key := range c.FieldMap {
+		if strings.HasPrefix(key, fieldMapHeaderPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		headerName := strings.TrimPrefix(key, fieldMapHeaderPrefix)
+		entry := c.FieldMap[key]
+		c.usedKeys[key] = true
+		c.usesHeaders = true
+
+		if entry.Constant != nil {
+			value, ok := entry.Constant.(string)
+			if !ok {
+				return errors.Errorf(
+					"could not set header %q, constant is not a string",
+					headerName,
+				)
 			}
+			c.append("outHeaders.Set(", strconv.Quote(headerName), ", ", strconv.Quote(value), ")")
+			continue
 		}
 
-		if fromField == nil {
+		if entry.Drop || entry.QualifiedName == "" ||
+			strings.HasPrefix(entry.QualifiedName, fieldMapHeaderPrefix) {
 			return errors.Errorf(
-				"cannot map by name for the field %s",
-				toField.Name,
+				"could not set header %q, it must be mapped from a field or constant",
+				headerName,
 			)
 		}
 
-		toIdentifier := indent + "out." + keyPrefix + strings.Title(toField.Name)
-		fromIdentifier := "in." + keyPrefix + strings.Title(fromField.Name)
-
-		// Override thrift type names to avoid naming collisions between endpoint
-		// and client types.
-		switch toFieldType := toField.Type.(type) {
-		case
-			*compile.BoolSpec,
-			*compile.I8Spec,
-			*compile.I16Spec,
-			*compile.I32Spec,
-			*compile.EnumSpec,
-			*compile.I64Spec,
-			*compile.DoubleSpec,
-			*compile.StringSpec:
-
-			err := c.genConverterForPrimitive(
-				toField, toIdentifier, fromIdentifier,
+		fromField, fromKey, guards, err := c.resolveSource(entry.QualifiedName)
+		if err != nil {
+			return err
+		}
+		if !isStringType(fromField.Type) {
+			return errors.Errorf(
+				"could not map field %s to header %q, field is not a string",
+				entry.QualifiedName, headerName,
 			)
-			if err != nil {
-				return err
-			}
-		case *compile.BinarySpec:
-			c.append(toIdentifier, " = []byte(", fromIdentifier, ")")
-		case *compile.TypedefSpec:
-			typeName, err := c.getIdentifierName(toField.Type)
-			if err != nil {
-				return err
+		}
+
+		fromIdentifier := "in." + fromKey
+		if !fromField.Required {
+			guards = append(guards, fromIdentifier+" != nil")
+			fromIdentifier = "*" + fromIdentifier
+		}
+
+		indent := ""
+		if len(guards) > 0 {
+			c.append("if ", strings.Join(guards, " && "), " {")
+			indent = "	"
+		}
+		c.append(indent, "outHeaders.Set(", strconv.Quote(headerName), ", string(", fromIdentifier, "))")
+		if len(guards) > 0 {
+			c.append("}")
+		}
+	}
+	return nil
+}
+
+// resolveSource looks up a dotted thrift path in the source fields. It
+// returns the field, its go accessor relative to "in" and the nil checks
+// needed for its parent structs.
+func (c *TypeConverter) resolveSource(
+	path string,
+) (*compile.FieldSpec, string, []string, error) {
+	fields := c.fromFields
+	parts := strings.Split(path, ".")
+	key := ""
+	guards := []string{}
+
+	for i, part := range parts {
+		var field *compile.FieldSpec
+		for _, f := range fields {
+			if f.Name == part {
+				field = f
+				break
 			}
+		}
+		if field == nil {
+			return nil, "", nil, errors.Errorf(
+				"could not find source field %s for field map", path,
+			)
+		}
+
+		if key != "" {
+			key += "."
+		}
+		key += strings.Title(field.Name)
+
+		if i == len(parts)-1 {
+			return field, key, guards, nil
+		}
 
-			// TODO: typedef for struct is invalid here ...
-			if toField.Required {
-				c.append(
-					toIdentifier, " = ", typeName, "(", fromIdentifier, ")",
+		structSpec, ok := field.Type.(*compile.StructSpec)
+		if !ok {
+			return nil, "", nil, errors.Errorf(
+				"could not find source field %s for field map, %s is not a struct",
+				path, part,
+			)
+		}
+		guards = append(guards, "in."+key+" != nil")
+		fields = structSpec.Fields
+	}
+
+	/* coverage ignore next line */
+	return nil, "", nil, errors.Errorf("empty source path for field map")
+}
+
+func (c *TypeConverter) genStructConverter(
+	keyPrefix string,
+	fromPrefix string,
+	thriftPrefix string,
+	indent string,
+	fromFields []*compile.FieldSpec,
+	toFields []*compile.FieldSpec,
+) error {
+	for i := 0; i < len(toFields); i++ {
+		toField := toFields[i]
+		thriftPath := thriftPrefix + toField.Name
+		toIdentifier := "out." + keyPrefix + strings.Title(toField.Name)
+
+		var fromField *compile.FieldSpec
+		var fromKey string
+		var guards []string
+
+		if entry, ok := c.FieldMap[thriftPath]; ok {
+			c.usedKeys[thriftPath] = true
+
+			switch {
+			case entry.Drop:
+				continue
+			case entry.Constant != nil:
+				err := c.genConverterForConstant(
+					toField, toIdentifier, entry.Constant, indent,
 				)
-			} else {
-				c.append(
-					toIdentifier, " = (*", typeName, ")(", fromIdentifier, ")",
+				if err != nil {
+					return err
+				}
+				continue
+			case strings.HasPrefix(entry.QualifiedName, fieldMapHeaderPrefix):
+				err := c.genConverterForHeader(
+					toField, toIdentifier,
+					strings.TrimPrefix(entry.QualifiedName, fieldMapHeaderPrefix),
+					indent,
 				)
+				if err != nil {
+					return err
+				}
+				continue
 			}
 
-		case *compile.StructSpec:
-			err := c.genConverterForStruct(
-				toField.Name,
-				toFieldType,
-				fromField.Type,
-				fromIdentifier,
-				keyPrefix+strings.Title(toField.Name),
-				indent,
-			)
+			var err error
+			fromField, fromKey, guards, err = c.resolveSource(entry.QualifiedName)
 			if err != nil {
 				return err
 			}
-		case *compile.ListSpec:
-			err := c.genConverterForList(
-				toFieldType,
-				toField,
-				fromField,
-				toIdentifier,
-				fromIdentifier,
-				keyPrefix,
-				indent,
-			)
-			if err != nil {
-				return err
+		} else {
+			for j := 0; j < len(fromFields); j++ {
+				if fromFields[j].Name == toField.Name {
+					fromField = fromFields[j]
+					break
+				}
 			}
-		case *compile.MapSpec:
-			err := c.genConverterForMap(
-				toFieldType,
-				toField,
-				fromField,
-				toIdentifier,
-				fromIdentifier,
-				keyPrefix,
-				indent,
-			)
-			if err != nil {
-				return err
+
+			if fromField == nil {
+				return errors.Errorf(
+					"cannot map by name for the field %s",
+					toField.Name,
+				)
 			}
-		default:
-			// fmt.Printf("Unknown type %s for field %s \n",
-			// 	toField.Type.TypeCode().String(), toField.Name,
-			// )
-
-			// pkgName, err := h.TypePackageName(toField.Type.ThriftFile())
-			// if err != nil {
-			// 	return nil, err
-			// }
-			// typeName := pkgName + "." + toField.Type.ThriftName()
-			// line := toIdentifier + "(*" + typeName + ")" + postfix
-			// c.Lines = append(c.Lines, line)
+			fromKey = fromPrefix + strings.Title(fromField.Name)
 		}
+
+		fromIdentifier := "in." + fromKey
+
+		guards = c.unguarded(guards)
+		if len(guards) > 0 {
+			c.append(indent, "if ", strings.Join(guards, " && "), " {")
+			indent += "	"
+		}
+
+		err := c.genFieldConverter(
+			toField, fromField,
+			toIdentifier, fromIdentifier,
+			keyPrefix, fromKey, thriftPath, indent,
+		)
+		if err != nil {
+			return err
+		}
+
+		if len(guards) > 0 {
+			indent = indent[:len(indent)-1]
+			c.append(indent, "}")
+		}
+	}
+
+	return nil
+}
+
+// unguarded returns the nil checks that are not already made by the
+// enclosing struct converters.
+func (c *TypeConverter) unguarded(guards []string) []string {
+	filtered := make([]string, 0, len(guards))
+	for _, guard := range guards {
+		if !c.guarded[guard] {
+			filtered = append(filtered, guard)
+		}
+	}
+	return filtered
+}
+
+func (c *TypeConverter) genFieldConverter(
+	toField *compile.FieldSpec,
+	fromField *compile.FieldSpec,
+	toIdentifier string,
+	fromIdentifier string,
+	keyPrefix string,
+	fromKey string,
+	thriftPath string,
+	indent string,
+) error {
+	// Override thrift type names to avoid naming collisions between endpoint
+	// and client types.
+	switch toFieldType := toField.Type.(type) {
+	case
+		*compile.BoolSpec,
+		*compile.I8Spec,
+		*compile.I16Spec,
+		*compile.I32Spec,
+		*compile.EnumSpec,
+		*compile.I64Spec,
+		*compile.DoubleSpec,
+		*compile.StringSpec,
+		// TODO: typedef for struct is invalid here ...
+		*compile.TypedefSpec:
+
+		return c.genConverterForPrimitive(
+			toField, fromField, toIdentifier, fromIdentifier, indent,
+		)
+	case *compile.BinarySpec:
+		c.append(indent, toIdentifier, " = []byte(", fromIdentifier, ")")
+	case *compile.StructSpec:
+		return c.genConverterForStruct(
+			toField.Name,
+			toFieldType,
+			fromField.Type,
+			fromIdentifier,
+			keyPrefix+strings.Title(toField.Name),
+			fromKey,
+			thriftPath,
+			indent,
+		)
+	case *compile.ListSpec:
+		return c.genConverterForList(
+			toFieldType,
+			toField,
+			fromField,
+			indent+toIdentifier,
+			fromIdentifier,
+			keyPrefix,
+			fromKey,
+			thriftPath,
+			indent,
+		)
+	case *compile.MapSpec:
+		return c.genConverterForMap(
+			toFieldType,
+			toField,
+			fromField,
+			indent+toIdentifier,
+			fromIdentifier,
+			keyPrefix,
+			fromKey,
+			thriftPath,
+			indent,
+		)
+	default:
+		// fmt.Printf("Unknown type %s for field %s \n",
+		// 	toField.Type.TypeCode().String(), toField.Name,
+		// )
+
+		// pkgName, err := h.TypePackageName(toField.Type.ThriftFile())
+		// if err != nil {
+		// 	return nil, err
+		// }
+		// typeName := pkgName + "." + toField.Type.ThriftName()
+		// line := toIdentifier + "(*" + typeName + ")" + postfix
+		// c.Lines = append(c.Lines, line)
 	}
 
 	return nil
@@ -423,10 +723,80 @@ func (c *TypeConverter) GenStructConverter(
 	fromFields []*compile.FieldSpec,
 	toFields []*compile.FieldSpec,
 ) error {
-	err := c.genStructConverter("", "", fromFields, toFields)
+	c.fromFields = fromFields
+	c.usedKeys = map[string]bool{}
+	c.guarded = map[string]bool{}
+
+	err := c.genStructConverter("", "", "", "", fromFields, toFields)
 	if err != nil {
 		return err
 	}
 
+	err = c.genHeaderConverters()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(c.FieldMap))
+	for key := range c.FieldMap {
+		if !c.usedKeys[key] {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		return errors.Errorf(
+			"could not find target fields %s for field map",
+			strings.Join(keys, ", "),
+		)
+	}
+
 	return nil
 }
+
+func isStringType(spec compile.TypeSpec) bool {
+	_, ok := compile.RootTypeSpec(spec).(*compile.StringSpec)
+	return ok
+}
+
+// constantLiteral renders a json constant as a go literal for the field.
+func constantLiteral(
+	toField *compile.FieldSpec,
+	value interface{},
+) (string, error) {
+	switch spec := compile.RootTypeSpec(toField.Type).(type) {
+	case *compile.BoolSpec:
+		if v, ok := value.(bool); ok {
+			return strconv.FormatBool(v), nil
+		}
+	case *compile.I8Spec, *compile.I16Spec, *compile.I32Spec, *compile.I64Spec:
+		if v, ok := value.(float64); ok && v == float64(int64(v)) {
+			return strconv.FormatInt(int64(v), 10), nil
+		}
+	case *compile.DoubleSpec:
+		if v, ok := value.(float64); ok {
+			return strconv.FormatFloat(v, 'g', -1, 64), nil
+		}
+	case *compile.StringSpec, *compile.BinarySpec:
+		if v, ok := value.(string); ok {
+			return strconv.Quote(v), nil
+		}
+	case *compile.EnumSpec:
+		switch v := value.(type) {
+		case string:
+			for _, item := range spec.Items {
+				if item.Name == v {
+					return strconv.FormatInt(int64(item.Value), 10), nil
+				}
+			}
+		case float64:
+			if v == float64(int32(v)) {
+				return strconv.FormatInt(int64(v), 10), nil
+			}
+		}
+	}
+
+	return "", errors.Errorf(
+		"could not use constant %v for field %s", value, toField.Name,
+	)
+}
@@ -96,8 +96,19 @@ func convertTypes(
 	toStruct string,
 	content string,
 	otherFiles map[string][]byte,
+) (string, error) {
+	return convertTypesWithFieldMap(fromStruct, toStruct, content, otherFiles, nil)
+}
+
+func convertTypesWithFieldMap(
+	fromStruct string,
+	toStruct string,
+	content string,
+	otherFiles map[string][]byte,
+	fieldMap map[string]codegen.FieldMapperEntry,
 ) (string, error) {
 	converter := newTypeConverter()
+	converter.FieldMap = fieldMap
 	program, err := compileProgram(content, otherFiles)
 	if err != nil {
 		return "", err
@@ -966,3 +977,164 @@ func TestConvertWithBadKeyMapOfString(t *testing.T) {
 		err.Error(),
 	)
 }
+
+func TestConvertWithFieldMapRename(t *testing.T) {
+	lines, err := convertTypesWithFieldMap(
+		"Foo", "Bar",
+		`struct NestedFoo {
+			1: required string one
+			2: optional string two
+		}
+
+		struct NestedBar {
+			1: required string one
+			2: optional string three
+		}
+
+		struct Foo {
+			1: optional NestedFoo one
+			2: required string two
+		}
+
+		struct Bar {
+			1: optional NestedBar one
+			2: optional string four
+		}`,
+		nil,
+		map[string]codegen.FieldMapperEntry{
+			"one.three": {QualifiedName: "one.two"},
+			"four":      {QualifiedName: "two"},
+		},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, trim(`
+		if in.One != nil {
+			out.One = &structs.NestedBar{}
+			out.One.One = string(in.One.One)
+			out.One.Three = (*string)(in.One.Two)
+		} else {
+			out.One = nil
+		}
+		out.Four = (*string)(&in.Two)
+	`), lines)
+}
+
+func TestConvertWithFieldMapConstantAndDrop(t *testing.T) {
+	lines, err := convertTypesWithFieldMap(
+		"Foo", "Bar",
+		`enum ItemState {
+			REQUIRED,
+			OPTIONAL
+		}
+
+		struct Foo {
+			1: required string one
+		}
+
+		struct Bar {
+			1: required string one
+			2: optional ItemState two
+			3: required i32 three
+			4: optional string four
+		}`,
+		nil,
+		map[string]codegen.FieldMapperEntry{
+			"two":   {Constant: "OPTIONAL"},
+			"three": {Constant: float64(42)},
+			"four":  {Drop: true},
+		},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, trim(`
+		out.One = string(in.One)
+		out.Two = new(structs.ItemState)
+		*out.Two = structs.ItemState(1)
+		out.Three = int32(42)
+	`), lines)
+}
+
+func TestConvertWithFieldMapOptionalBinaryConstant(t *testing.T) {
+	lines, err := convertTypesWithFieldMap(
+		"Foo", "Bar",
+		`struct Foo {
+			1: required string one
+		}
+
+		struct Bar {
+			1: required string one
+			2: optional binary two
+		}`,
+		nil,
+		map[string]codegen.FieldMapperEntry{
+			"two": {Constant: "bytes"},
+		},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, trim(`
+		out.One = string(in.One)
+		out.Two = []byte("bytes")
+	`), lines)
+}
+
+func TestConvertWithFieldMapHeaders(t *testing.T) {
+	converter := newTypeConverter()
+	converter.FieldMap = map[string]codegen.FieldMapperEntry{
+		"one":             {QualifiedName: "headers.x-one"},
+		"headers.x-token": {QualifiedName: "nested.token"},
+	}
+	program, err := compileProgram(
+		`typedef string UUID
+
+		struct Nested {
+			1: optional UUID token
+		}
+
+		struct Foo {
+			1: optional Nested nested
+		}
+
+		struct Bar {
+			1: optional string one
+		}`,
+		nil,
+	)
+	assert.NoError(t, err)
+
+	err = converter.GenStructConverter(
+		program.Types["Foo"].(*compile.StructSpec).Fields,
+		program.Types["Bar"].(*compile.StructSpec).Fields,
+	)
+	assert.NoError(t, err)
+	assert.True(t, converter.UsesHeaders())
+	assert.Equal(t, trim(`
+		if h, ok := inHeaders.Get("x-one"); ok {
+			out.One = (*string)(&h)
+		}
+		if in.Nested != nil && in.Nested.Token != nil {
+			outHeaders.Set("x-token", string(*in.Nested.Token))
+		}
+	`), trim(strings.Join(converter.Lines, "\n")))
+}
+
+func TestConvertWithFieldMapUnknownTarget(t *testing.T) {
+	lines, err := convertTypesWithFieldMap(
+		"Foo", "Bar",
+		`struct Foo {
+			1: required string one
+		}
+
+		struct Bar {
+			1: required string one
+		}`,
+		nil,
+		map[string]codegen.FieldMapperEntry{
+			"two": {Drop: true},
+		},
+	)
+
+	assert.Equal(t, "", lines)
+	assert.Equal(t, "could not find target fields two for field map", err.Error())
+}
@@ -32,23 +32,56 @@ import (
 	"github.com/uber/zanzibar/runtime"
 )
 
-// BarClient is the http client for service Bar.
+// Client defines bar client interface.
+type Client interface {
+	ArgNotStruct(
+		ctx context.Context,
+		reqHeaders map[string]string,
+		args *clientsBarBar.Bar_ArgNotStruct_Args,
+	) (map[string]string, error)
+	ArgWithHeaders(
+		ctx context.Context,
+		reqHeaders map[string]string,
+		args *clientsBarBar.Bar_ArgWithHeaders_Args,
+	) (*clientsBarBar.BarResponse, map[string]string, error)
+	MissingArg(
+		ctx context.Context,
+		reqHeaders map[string]string,
+	) (*clientsBarBar.BarResponse, map[string]string, error)
+	NoRequest(
+		ctx context.Context,
+		reqHeaders map[string]string,
+	) (*clientsBarBar.BarResponse, map[string]string, error)
+	Normal(
+		ctx context.Context,
+		reqHeaders map[string]string,
+		args *clientsBarBar.Bar_Normal_Args,
+	) (*clientsBarBar.BarResponse, map[string]string, error)
+	TooManyArgs(
+		ctx context.Context,
+		reqHeaders map[string]string,
+		args *clientsBarBar.Bar_TooManyArgs_Args,
+	) (*clientsBarBar.BarResponse, map[string]string, error)
+}
+
+// BarClient is the http client.
 type BarClient struct {
 	ClientID   string
 	HTTPClient *zanzibar.HTTPClient
 }
 
-// NewClient returns a new http client for service Bar.
+// NewClient returns a new http client.
 func NewClient(
 	gateway *zanzibar.Gateway,
 ) *BarClient {
 	ip := gateway.Config.MustGetString("clients.bar.ip")
 	port := gateway.Config.MustGetInt("clients.bar.port")
 
-	baseURL := "http://" + ip + ":" + strconv.Itoa(int(port))
+	opts := zanzibar.NewHTTPClientOptions(gateway.Config, "bar")
+	baseURL := opts.Scheme() + "://" + ip + ":" + strconv.Itoa(int(port))
 	return &BarClient{
 		ClientID:   "bar",
-		HTTPClient: zanzibar.NewHTTPClient(gateway, baseURL),
+		HTTPClient: zanzibar.NewHTTPClientWithOptions(gateway, baseURL, opts),
 	}
 }
 
@@ -0,0 +1,149 @@
+// Code generated by zanzibar
+// @generated
+
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package clientmock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	barClientGenerated "github.com/uber/zanzibar/examples/example-gateway/build/clients/bar"
+	clientsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/bar/bar"
+)
+
+// MockClient is a mock of the bar client. Expected calls and their
+// canned responses are recorded with On and verified with
+// AssertExpectations.
+type MockClient struct {
+	mock.Mock
+}
+
+var _ barClientGenerated.Client = (*MockClient)(nil)
+
+// NewMockClient returns a mock of the bar client.
+func NewMockClient() *MockClient {
+	return &MockClient{}
+}
+
+// ArgNotStruct returns the canned response of the expected call.
+func (m *MockClient) ArgNotStruct(
+	ctx context.Context,
+	reqHeaders map[string]string,
+	args *clientsBarBar.Bar_ArgNotStruct_Args,
+) (map[string]string, error) {
+	ret := m.Called(ctx, reqHeaders, args)
+	var resHeaders map[string]string
+	if v := ret.Get(0); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return resHeaders, ret.Error(1)
+}
+
+// ArgWithHeaders returns the canned response of the expected call.
+func (m *MockClient) ArgWithHeaders(
+	ctx context.Context,
+	reqHeaders map[string]string,
+	args *clientsBarBar.Bar_ArgWithHeaders_Args,
+) (*clientsBarBar.BarResponse, map[string]string, error) {
+	ret := m.Called(ctx, reqHeaders, args)
+	var response *clientsBarBar.BarResponse
+	if v := ret.Get(0); v != nil {
+		response = v.(*clientsBarBar.BarResponse)
+	}
+	var resHeaders map[string]string
+	if v := ret.Get(1); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return response, resHeaders, ret.Error(2)
+}
+
+// MissingArg returns the canned response of the expected call.
+func (m *MockClient) MissingArg(
+	ctx context.Context,
+	reqHeaders map[string]string,
+) (*clientsBarBar.BarResponse, map[string]string, error) {
+	ret := m.Called(ctx, reqHeaders)
+	var response *clientsBarBar.BarResponse
+	if v := ret.Get(0); v != nil {
+		response = v.(*clientsBarBar.BarResponse)
+	}
+	var resHeaders map[string]string
+	if v := ret.Get(1); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return response, resHeaders, ret.Error(2)
+}
+
+// NoRequest returns the canned response of the expected call.
+func (m *MockClient) NoRequest(
+	ctx context.Context,
+	reqHeaders map[string]string,
+) (*clientsBarBar.BarResponse, map[string]string, error) {
+	ret := m.Called(ctx, reqHeaders)
+	var response *clientsBarBar.BarResponse
+	if v := ret.Get(0); v != nil {
+		response = v.(*clientsBarBar.BarResponse)
+	}
+	var resHeaders map[string]string
+	if v := ret.Get(1); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return response, resHeaders, ret.Error(2)
+}
+
+// Normal returns the canned response of the expected call.
+func (m *MockClient) Normal(
+	ctx context.Context,
+	reqHeaders map[string]string,
+	args *clientsBarBar.Bar_Normal_Args,
+) (*clientsBarBar.BarResponse, map[string]string, error) {
+	ret := m.Called(ctx, reqHeaders, args)
+	var response *clientsBarBar.BarResponse
+	if v := ret.Get(0); v != nil {
+		response = v.(*clientsBarBar.BarResponse)
+	}
+	var resHeaders map[string]string
+	if v := ret.Get(1); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return response, resHeaders, ret.Error(2)
+}
+
+// TooManyArgs returns the canned response of the expected call.
+func (m *MockClient) TooManyArgs(
+	ctx context.Context,
+	reqHeaders map[string]string,
+	args *clientsBarBar.Bar_TooManyArgs_Args,
+) (*clientsBarBar.BarResponse, map[string]string, error) {
+	ret := m.Called(ctx, reqHeaders, args)
+	var response *clientsBarBar.BarResponse
+	if v := ret.Get(0); v != nil {
+		response = v.(*clientsBarBar.BarResponse)
+	}
+	var resHeaders map[string]string
+	if v := ret.Get(1); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return response, resHeaders, ret.Error(2)
+}
@@ -36,6 +36,28 @@ import (
 	clientsBazBaz "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/baz/baz"
 )
 
+// Client defines baz client interface.
+type Client interface {
+	Call(
+		ctx context.Context,
+		reqHeaders map[string]string,
+		args *clientsBazBaz.SimpleService_Call_Args,
+	) (map[string]string, error)
+	Compare(
+		ctx context.Context,
+		reqHeaders map[string]string,
+		args *clientsBazBaz.SimpleService_Compare_Args,
+	) (*clientsBazBase.BazResponse, map[string]string, error)
+	Ping(
+		ctx context.Context,
+		reqHeaders map[string]string,
+	) (*clientsBazBase.BazResponse, map[string]string, error)
+	DeliberateDiffNoop(
+		ctx context.Context,
+		reqHeaders map[string]string,
+	) (map[string]string, error)
+}
+
 // NewClient returns a new TChannel client for service baz.
 func NewClient(gateway *zanzibar.Gateway) *BazClient {
 	serviceName := gateway.Config.MustGetString("clients.baz.serviceName")
@@ -57,6 +79,10 @@ func NewClient(gateway *zanzibar.Gateway) *BazClient {
 			ServiceName:       serviceName,
 			Timeout:           timeout,
 			TimeoutPerAttempt: timeoutPerAttempt,
+			ClientID:          "baz",
+			RecordDir: zanzibar.TChannelClientRecordDir(
+				gateway.Config, "baz",
+			),
 		},
 	)
 
@@ -0,0 +1,110 @@
+// Code generated by zanzibar
+// @generated
+
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package clientmock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	bazClientGenerated "github.com/uber/zanzibar/examples/example-gateway/build/clients/baz"
+	clientsBazBase "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/baz/base"
+	clientsBazBaz "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/baz/baz"
+)
+
+// MockClient is a mock of the baz client. Expected calls and their
+// canned responses are recorded with On and verified with
+// AssertExpectations.
+type MockClient struct {
+	mock.Mock
+}
+
+var _ bazClientGenerated.Client = (*MockClient)(nil)
+
+// NewMockClient returns a mock of the baz client.
+func NewMockClient() *MockClient {
+	return &MockClient{}
+}
+
+// Call returns the canned response of the expected call.
+func (m *MockClient) Call(
+	ctx context.Context,
+	reqHeaders map[string]string,
+	args *clientsBazBaz.SimpleService_Call_Args,
+) (map[string]string, error) {
+	ret := m.Called(ctx, reqHeaders, args)
+	var resHeaders map[string]string
+	if v := ret.Get(0); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return resHeaders, ret.Error(1)
+}
+
+// Compare returns the canned response of the expected call.
+func (m *MockClient) Compare(
+	ctx context.Context,
+	reqHeaders map[string]string,
+	args *clientsBazBaz.SimpleService_Compare_Args,
+) (*clientsBazBase.BazResponse, map[string]string, error) {
+	ret := m.Called(ctx, reqHeaders, args)
+	var response *clientsBazBase.BazResponse
+	if v := ret.Get(0); v != nil {
+		response = v.(*clientsBazBase.BazResponse)
+	}
+	var resHeaders map[string]string
+	if v := ret.Get(1); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return response, resHeaders, ret.Error(2)
+}
+
+// Ping returns the canned response of the expected call.
+func (m *MockClient) Ping(
+	ctx context.Context,
+	reqHeaders map[string]string,
+) (*clientsBazBase.BazResponse, map[string]string, error) {
+	ret := m.Called(ctx, reqHeaders)
+	var response *clientsBazBase.BazResponse
+	if v := ret.Get(0); v != nil {
+		response = v.(*clientsBazBase.BazResponse)
+	}
+	var resHeaders map[string]string
+	if v := ret.Get(1); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return response, resHeaders, ret.Error(2)
+}
+
+// DeliberateDiffNoop returns the canned response of the expected call.
+func (m *MockClient) DeliberateDiffNoop(
+	ctx context.Context,
+	reqHeaders map[string]string,
+) (map[string]string, error) {
+	ret := m.Called(ctx, reqHeaders)
+	var resHeaders map[string]string
+	if v := ret.Get(0); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return resHeaders, ret.Error(1)
+}
@@ -34,12 +34,13 @@ import (
 )
 
 // Clients datastructure that holds all the generated clients
-// This should only hold clients generate from specs
+// This should only hold clients generate from specs, generated clients
+// are held by their interface so they can be replaced by mocks
 type Clients struct {
-	Bar       *barClientGenerated.BarClient
-	Baz       *bazClientGenerated.BazClient
-	Contacts  *contactsClientGenerated.ContactsClient
-	GoogleNow *googlenowClientGenerated.GoogleNowClient
+	Bar       barClientGenerated.Client
+	Baz       bazClientGenerated.Client
+	Contacts  contactsClientGenerated.Client
+	GoogleNow googlenowClientGenerated.Client
 	Quux      *quuxClientStatic.Quux
 }
 
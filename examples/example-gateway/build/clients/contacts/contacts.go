@@ -25,6 +25,7 @@ package contactsClient
 
 import (
 	"context"
+	"net/url"
 	"strconv"
 
 	"github.com/pkg/errors"
@@ -32,23 +33,33 @@ import (
 	"github.com/uber/zanzibar/runtime"
 )
 
-// ContactsClient is the http client for service Contacts.
+// Client defines contacts client interface.
+type Client interface {
+	SaveContacts(
+		ctx context.Context,
+		reqHeaders map[string]string,
+		args *clientsContactsContacts.SaveContactsRequest,
+	) (*clientsContactsContacts.SaveContactsResponse, map[string]string, error)
+}
+
+// ContactsClient is the http client.
 type ContactsClient struct {
 	ClientID   string
 	HTTPClient *zanzibar.HTTPClient
 }
 
-// NewClient returns a new http client for service Contacts.
+// NewClient returns a new http client.
 func NewClient(
 	gateway *zanzibar.Gateway,
 ) *ContactsClient {
 	ip := gateway.Config.MustGetString("clients.contacts.ip")
 	port := gateway.Config.MustGetInt("clients.contacts.port")
 
-	baseURL := "http://" + ip + ":" + strconv.Itoa(int(port))
+	opts := zanzibar.NewHTTPClientOptions(gateway.Config, "contacts")
+	baseURL := opts.Scheme() + "://" + ip + ":" + strconv.Itoa(int(port))
 	return &ContactsClient{
 		ClientID:   "contacts",
-		HTTPClient: zanzibar.NewHTTPClient(gateway, baseURL),
+		HTTPClient: zanzibar.NewHTTPClientWithOptions(gateway, baseURL, opts),
 	}
 }
 
@@ -64,7 +75,7 @@ func (c *ContactsClient) SaveContacts(
 	)
 
 	// Generate full URL.
-	fullURL := c.HTTPClient.BaseURL + "/" + string(r.UserUUID) + "/contacts"
+	fullURL := c.HTTPClient.BaseURL + "/" + url.PathEscape(string(r.UserUUID)) + "/contacts"
 
 	err := req.WriteJSON("POST", fullURL, headers, r)
 	if err != nil {
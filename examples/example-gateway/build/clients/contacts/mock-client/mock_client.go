@@ -0,0 +1,65 @@
+// Code generated by zanzibar
+// @generated
+
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package clientmock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	contactsClientGenerated "github.com/uber/zanzibar/examples/example-gateway/build/clients/contacts"
+	clientsContactsContacts "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/contacts/contacts"
+)
+
+// MockClient is a mock of the contacts client. Expected calls and their
+// canned responses are recorded with On and verified with
+// AssertExpectations.
+type MockClient struct {
+	mock.Mock
+}
+
+var _ contactsClientGenerated.Client = (*MockClient)(nil)
+
+// NewMockClient returns a mock of the contacts client.
+func NewMockClient() *MockClient {
+	return &MockClient{}
+}
+
+// SaveContacts returns the canned response of the expected call.
+func (m *MockClient) SaveContacts(
+	ctx context.Context,
+	reqHeaders map[string]string,
+	args *clientsContactsContacts.SaveContactsRequest,
+) (*clientsContactsContacts.SaveContactsResponse, map[string]string, error) {
+	ret := m.Called(ctx, reqHeaders, args)
+	var response *clientsContactsContacts.SaveContactsResponse
+	if v := ret.Get(0); v != nil {
+		response = v.(*clientsContactsContacts.SaveContactsResponse)
+	}
+	var resHeaders map[string]string
+	if v := ret.Get(1); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return response, resHeaders, ret.Error(2)
+}
@@ -32,23 +32,37 @@ import (
 	"github.com/uber/zanzibar/runtime"
 )
 
-// GoogleNowClient is the http client for service GoogleNowService.
+// Client defines google-now client interface.
+type Client interface {
+	AddCredentials(
+		ctx context.Context,
+		reqHeaders map[string]string,
+		args *clientsGooglenowGooglenow.GoogleNowService_AddCredentials_Args,
+	) (map[string]string, error)
+	CheckCredentials(
+		ctx context.Context,
+		reqHeaders map[string]string,
+	) (map[string]string, error)
+}
+
+// GoogleNowClient is the http client.
 type GoogleNowClient struct {
 	ClientID   string
 	HTTPClient *zanzibar.HTTPClient
 }
 
-// NewClient returns a new http client for service GoogleNowService.
+// NewClient returns a new http client.
 func NewClient(
 	gateway *zanzibar.Gateway,
 ) *GoogleNowClient {
 	ip := gateway.Config.MustGetString("clients.google-now.ip")
 	port := gateway.Config.MustGetInt("clients.google-now.port")
 
-	baseURL := "http://" + ip + ":" + strconv.Itoa(int(port))
+	opts := zanzibar.NewHTTPClientOptions(gateway.Config, "google-now")
+	baseURL := opts.Scheme() + "://" + ip + ":" + strconv.Itoa(int(port))
 	return &GoogleNowClient{
 		ClientID:   "google-now",
-		HTTPClient: zanzibar.NewHTTPClient(gateway, baseURL),
+		HTTPClient: zanzibar.NewHTTPClientWithOptions(gateway, baseURL, opts),
 	}
 }
 
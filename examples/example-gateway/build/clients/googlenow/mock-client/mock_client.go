@@ -0,0 +1,74 @@
+// Code generated by zanzibar
+// @generated
+
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package clientmock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	googlenowClientGenerated "github.com/uber/zanzibar/examples/example-gateway/build/clients/googlenow"
+	clientsGooglenowGooglenow "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/googlenow/googlenow"
+)
+
+// MockClient is a mock of the google-now client. Expected calls and their
+// canned responses are recorded with On and verified with
+// AssertExpectations.
+type MockClient struct {
+	mock.Mock
+}
+
+var _ googlenowClientGenerated.Client = (*MockClient)(nil)
+
+// NewMockClient returns a mock of the google-now client.
+func NewMockClient() *MockClient {
+	return &MockClient{}
+}
+
+// AddCredentials returns the canned response of the expected call.
+func (m *MockClient) AddCredentials(
+	ctx context.Context,
+	reqHeaders map[string]string,
+	args *clientsGooglenowGooglenow.GoogleNowService_AddCredentials_Args,
+) (map[string]string, error) {
+	ret := m.Called(ctx, reqHeaders, args)
+	var resHeaders map[string]string
+	if v := ret.Get(0); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return resHeaders, ret.Error(1)
+}
+
+// CheckCredentials returns the canned response of the expected call.
+func (m *MockClient) CheckCredentials(
+	ctx context.Context,
+	reqHeaders map[string]string,
+) (map[string]string, error) {
+	ret := m.Called(ctx, reqHeaders)
+	var resHeaders map[string]string
+	if v := ret.Get(0); v != nil {
+		resHeaders = v.(map[string]string)
+	}
+	return resHeaders, ret.Error(1)
+}
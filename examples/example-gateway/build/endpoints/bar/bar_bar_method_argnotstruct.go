@@ -30,13 +30,14 @@ import (
 	zanzibar "github.com/uber/zanzibar/runtime"
 	"go.uber.org/zap"
 
+	module "github.com/uber/zanzibar/examples/example-gateway/build/endpoints/bar/module"
 	clientsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/bar/bar"
 	endpointsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/bar/bar"
 )
 
 // ArgNotStructHandler is the handler for "/bar/arg-not-struct-path"
 type ArgNotStructHandler struct {
-	Clients *clients.Clients
+	Dependencies *module.Dependencies
 }
 
 // NewArgNotStructEndpoint creates a handler
@@ -44,7 +45,11 @@ func NewArgNotStructEndpoint(
 	gateway *zanzibar.Gateway,
 ) *ArgNotStructHandler {
 	return &ArgNotStructHandler{
-		Clients: gateway.Clients.(*clients.Clients),
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				Bar: gateway.Clients.(*clients.Clients).Bar,
+			},
+		},
 	}
 }
 
@@ -60,7 +65,7 @@ func (handler *ArgNotStructHandler) HandleRequest(
 	}
 
 	workflow := ArgNotStructEndpoint{
-		Clients: handler.Clients,
+		Clients: &handler.Dependencies.Client,
 		Logger:  req.Logger,
 		Request: req,
 	}
@@ -89,7 +94,7 @@ func (handler *ArgNotStructHandler) HandleRequest(
 
 // ArgNotStructEndpoint calls thrift client Bar.ArgNotStruct
 type ArgNotStructEndpoint struct {
-	Clients *clients.Clients
+	Clients *module.ClientDependencies
 	Logger  *zap.Logger
 	Request *zanzibar.ServerHTTPRequest
 }
@@ -25,6 +25,7 @@ package bar
 
 import (
 	"bytes"
+	"io/ioutil"
 	"net/http"
 	"path/filepath"
 	"testing"
@@ -48,14 +49,18 @@ func TestArgNotStructSuccessfulRequestOKResponse(t *testing.T) {
 	defer gateway.Close()
 
 	fakeArgNotStruct := func(w http.ResponseWriter, r *http.Request) {
+		counter++
+
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err, "failed to read client request body") {
+			assert.JSONEq(t, `{"request":"foo"}`, string(body))
+		}
 
 		w.WriteHeader(200)
 
-		// TODO(zw): generate client response.
 		if _, err := w.Write([]byte(`{}`)); err != nil {
 			t.Fatal("can't write fake response")
 		}
-		counter++
 	}
 
 	gateway.HTTPBackends()["bar"].HandleFunc(
@@ -31,13 +31,14 @@ import (
 	"go.uber.org/thriftrw/ptr"
 	"go.uber.org/zap"
 
+	module "github.com/uber/zanzibar/examples/example-gateway/build/endpoints/bar/module"
 	clientsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/bar/bar"
 	endpointsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/bar/bar"
 )
 
 // ArgWithHeadersHandler is the handler for "/bar/argWithHeaders"
 type ArgWithHeadersHandler struct {
-	Clients *clients.Clients
+	Dependencies *module.Dependencies
 }
 
 // NewArgWithHeadersEndpoint creates a handler
@@ -45,7 +46,11 @@ func NewArgWithHeadersEndpoint(
 	gateway *zanzibar.Gateway,
 ) *ArgWithHeadersHandler {
 	return &ArgWithHeadersHandler{
-		Clients: gateway.Clients.(*clients.Clients),
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				Bar: gateway.Clients.(*clients.Clients).Bar,
+			},
+		},
 	}
 }
 
@@ -68,7 +73,7 @@ func (handler *ArgWithHeadersHandler) HandleRequest(
 	requestBody.UserUUID = ptr.String(xUUIDValue)
 
 	workflow := ArgWithHeadersEndpoint{
-		Clients: handler.Clients,
+		Clients: &handler.Dependencies.Client,
 		Logger:  req.Logger,
 		Request: req,
 	}
@@ -92,7 +97,7 @@ func (handler *ArgWithHeadersHandler) HandleRequest(
 
 // ArgWithHeadersEndpoint calls thrift client Bar.ArgWithHeaders
 type ArgWithHeadersEndpoint struct {
-	Clients *clients.Clients
+	Clients *module.ClientDependencies
 	Logger  *zap.Logger
 	Request *zanzibar.ServerHTTPRequest
 }
@@ -103,11 +108,13 @@ func (w ArgWithHeadersEndpoint) Handle(
 	reqHeaders zanzibar.Header,
 	r *endpointsBarBar.Bar_ArgWithHeaders_Args,
 ) (*endpointsBarBar.BarResponse, zanzibar.Header, error) {
-	clientRequest := convertToArgWithHeadersClientRequest(r)
-
 	clientHeaders := map[string]string{}
 
-	clientRespBody, _, err := w.Clients.Bar.ArgWithHeaders(
+	clientRequest := convertToArgWithHeadersClientRequest(
+		r, reqHeaders, zanzibar.ServerTChannelHeader(clientHeaders),
+	)
+
+	clientRespBody, cliRespHeaders, err := w.Clients.Bar.ArgWithHeaders(
 		ctx, clientHeaders, clientRequest,
 	)
 
@@ -130,23 +137,42 @@ func (w ArgWithHeadersEndpoint) Handle(
 	// TODO: Add support for TChannel Headers with a switch here
 	resHeaders := zanzibar.ServerHTTPHeader{}
 
-	response := convertArgWithHeadersClientResponse(clientRespBody)
+	// Client response headers are read case insensitively
+	clientResHeaders := zanzibar.ServerHTTPHeader{}
+	for k, v := range cliRespHeaders {
+		clientResHeaders.Set(k, v)
+	}
+	response := convertArgWithHeadersClientResponse(
+		clientRespBody, clientResHeaders, resHeaders,
+	)
 	return response, resHeaders, nil
 }
 
-func convertToArgWithHeadersClientRequest(in *endpointsBarBar.Bar_ArgWithHeaders_Args) *clientsBarBar.Bar_ArgWithHeaders_Args {
+func convertToArgWithHeadersClientRequest(
+	in *endpointsBarBar.Bar_ArgWithHeaders_Args,
+	inHeaders zanzibar.Header,
+	outHeaders zanzibar.Header,
+) *clientsBarBar.Bar_ArgWithHeaders_Args {
 	out := &clientsBarBar.Bar_ArgWithHeaders_Args{}
 
 	out.Name = string(in.Name)
-	out.UserUUID = (*string)(in.UserUUID)
+	if h, ok := inHeaders.Get("x-uuid"); ok {
+		out.UserUUID = (*string)(&h)
+	}
 
 	return out
 }
 
-func convertArgWithHeadersClientResponse(in *clientsBarBar.BarResponse) *endpointsBarBar.BarResponse {
+func convertArgWithHeadersClientResponse(
+	in *clientsBarBar.BarResponse,
+	inHeaders zanzibar.Header,
+	outHeaders zanzibar.Header,
+) *endpointsBarBar.BarResponse {
 	out := &endpointsBarBar.BarResponse{}
 
-	out.StringField = string(in.StringField)
+	if h, ok := inHeaders.Get("x-string-field"); ok {
+		out.StringField = string(h)
+	}
 	out.IntWithRange = int32(in.IntWithRange)
 	out.IntWithoutRange = int32(in.IntWithoutRange)
 	out.MapIntWithRange = make(map[string]int32, len(in.MapIntWithRange))
@@ -157,6 +183,7 @@ func convertArgWithHeadersClientResponse(in *clientsBarBar.BarResponse) *endpoin
 	for key, value := range in.MapIntWithoutRange {
 		out.MapIntWithoutRange[key] = int32(value)
 	}
+	outHeaders.Set("some-header-field", string(in.StringField))
 
 	return out
 }
@@ -30,13 +30,14 @@ import (
 	zanzibar "github.com/uber/zanzibar/runtime"
 	"go.uber.org/zap"
 
+	module "github.com/uber/zanzibar/examples/example-gateway/build/endpoints/bar/module"
 	clientsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/bar/bar"
 	endpointsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/bar/bar"
 )
 
 // MissingArgHandler is the handler for "/bar/missing-arg-path"
 type MissingArgHandler struct {
-	Clients *clients.Clients
+	Dependencies *module.Dependencies
 }
 
 // NewMissingArgEndpoint creates a handler
@@ -44,7 +45,11 @@ func NewMissingArgEndpoint(
 	gateway *zanzibar.Gateway,
 ) *MissingArgHandler {
 	return &MissingArgHandler{
-		Clients: gateway.Clients.(*clients.Clients),
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				Bar: gateway.Clients.(*clients.Clients).Bar,
+			},
+		},
 	}
 }
 
@@ -56,7 +61,7 @@ func (handler *MissingArgHandler) HandleRequest(
 ) {
 
 	workflow := MissingArgEndpoint{
-		Clients: handler.Clients,
+		Clients: &handler.Dependencies.Client,
 		Logger:  req.Logger,
 		Request: req,
 	}
@@ -86,7 +91,7 @@ func (handler *MissingArgHandler) HandleRequest(
 
 // MissingArgEndpoint calls thrift client Bar.MissingArg
 type MissingArgEndpoint struct {
-	Clients *clients.Clients
+	Clients *module.ClientDependencies
 	Logger  *zap.Logger
 	Request *zanzibar.ServerHTTPRequest
 }
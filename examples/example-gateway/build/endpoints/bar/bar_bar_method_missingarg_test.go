@@ -48,14 +48,13 @@ func TestMissingArgSuccessfulRequestOKResponse(t *testing.T) {
 	defer gateway.Close()
 
 	fakeMissingArg := func(w http.ResponseWriter, r *http.Request) {
+		counter++
 
 		w.WriteHeader(200)
 
-		// TODO(zw): generate client response.
 		if _, err := w.Write([]byte(`{"intWithRange":0,"intWithoutRange":1,"mapIntWithRange":{},"mapIntWithoutRange":{},"stringField":"foo"}`)); err != nil {
 			t.Fatal("can't write fake response")
 		}
-		counter++
 	}
 
 	gateway.HTTPBackends()["bar"].HandleFunc(
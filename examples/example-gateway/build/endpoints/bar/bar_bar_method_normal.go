@@ -30,13 +30,14 @@ import (
 	zanzibar "github.com/uber/zanzibar/runtime"
 	"go.uber.org/zap"
 
+	module "github.com/uber/zanzibar/examples/example-gateway/build/endpoints/bar/module"
 	clientsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/bar/bar"
 	endpointsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/bar/bar"
 )
 
 // NormalHandler is the handler for "/bar/bar-path"
 type NormalHandler struct {
-	Clients *clients.Clients
+	Dependencies *module.Dependencies
 }
 
 // NewNormalEndpoint creates a handler
@@ -44,7 +45,11 @@ func NewNormalEndpoint(
 	gateway *zanzibar.Gateway,
 ) *NormalHandler {
 	return &NormalHandler{
-		Clients: gateway.Clients.(*clients.Clients),
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				Bar: gateway.Clients.(*clients.Clients).Bar,
+			},
+		},
 	}
 }
 
@@ -60,7 +65,7 @@ func (handler *NormalHandler) HandleRequest(
 	}
 
 	workflow := NormalEndpoint{
-		Clients: handler.Clients,
+		Clients: &handler.Dependencies.Client,
 		Logger:  req.Logger,
 		Request: req,
 	}
@@ -90,7 +95,7 @@ func (handler *NormalHandler) HandleRequest(
 
 // NormalEndpoint calls thrift client Bar.Normal
 type NormalEndpoint struct {
-	Clients *clients.Clients
+	Clients *module.ClientDependencies
 	Logger  *zap.Logger
 	Request *zanzibar.ServerHTTPRequest
 }
@@ -25,9 +25,11 @@ package bar
 
 import (
 	"bytes"
+	"io/ioutil"
 	"net/http"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/uber/zanzibar/test/lib/test_gateway"
@@ -48,14 +50,18 @@ func TestNormalSuccessfulRequestOKResponse(t *testing.T) {
 	defer gateway.Close()
 
 	fakeNormal := func(w http.ResponseWriter, r *http.Request) {
+		counter++
+
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err, "failed to read client request body") {
+			assert.JSONEq(t, `{"request":{"boolField":true,"stringField":"foo"}}`, string(body))
+		}
 
 		w.WriteHeader(200)
 
-		// TODO(zw): generate client response.
 		if _, err := w.Write([]byte(`{"intWithRange":0,"intWithoutRange":1,"mapIntWithRange":{},"mapIntWithoutRange":{},"stringField":"foo"}`)); err != nil {
 			t.Fatal("can't write fake response")
 		}
-		counter++
 	}
 
 	gateway.HTTPBackends()["bar"].HandleFunc(
@@ -78,3 +84,157 @@ func TestNormalSuccessfulRequestOKResponse(t *testing.T) {
 
 	assert.Equal(t, 1, counter)
 }
+
+func TestNormalClientExceptionErrorResponse(t *testing.T) {
+	var counter int
+
+	gateway, err := testGateway.CreateGateway(t, nil, &testGateway.Options{
+		KnownHTTPBackends: []string{"bar"},
+		TestBinary: filepath.Join(
+			getDirName(), "..", "..", "services", "example-gateway", "main.go",
+		),
+	})
+	if !assert.NoError(t, err, "got bootstrap err") {
+		return
+	}
+	defer gateway.Close()
+
+	fakeNormal := func(w http.ResponseWriter, r *http.Request) {
+		counter++
+
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err, "failed to read client request body") {
+			assert.JSONEq(t, `{"request":{"boolField":true,"stringField":"foo"}}`, string(body))
+		}
+
+		w.WriteHeader(403)
+
+		if _, err := w.Write([]byte(`{"stringField":"foo"}`)); err != nil {
+			t.Fatal("can't write fake response")
+		}
+	}
+
+	gateway.HTTPBackends()["bar"].HandleFunc(
+		"POST", "/bar-path", fakeNormal,
+	)
+
+	headers := map[string]string{}
+
+	res, err := gateway.MakeRequest(
+		"POST",
+		"/bar/bar-path",
+		headers,
+		bytes.NewReader([]byte(`{"request":{"boolField":true,"stringField":"foo"}}`)),
+	)
+	if !assert.NoError(t, err, "got http error") {
+		return
+	}
+
+	assert.Equal(t, 403, res.StatusCode)
+
+	assert.Equal(t, 1, counter)
+}
+
+func TestNormalClientTimeoutErrorResponse(t *testing.T) {
+	var counter int
+
+	gateway, err := testGateway.CreateGateway(t, map[string]interface{}{
+		"clients.bar.responseHeaderTimeout": 50,
+	}, &testGateway.Options{
+		KnownHTTPBackends: []string{"bar"},
+		TestBinary: filepath.Join(
+			getDirName(), "..", "..", "services", "example-gateway", "main.go",
+		),
+	})
+	if !assert.NoError(t, err, "got bootstrap err") {
+		return
+	}
+	defer gateway.Close()
+
+	fakeNormal := func(w http.ResponseWriter, r *http.Request) {
+		counter++
+
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err, "failed to read client request body") {
+			assert.JSONEq(t, `{"request":{"boolField":true,"stringField":"foo"}}`, string(body))
+		}
+
+		time.Sleep(200 * time.Millisecond)
+
+		w.WriteHeader(200)
+
+		if _, err := w.Write([]byte(`{}`)); err != nil {
+			t.Fatal("can't write fake response")
+		}
+	}
+
+	gateway.HTTPBackends()["bar"].HandleFunc(
+		"POST", "/bar-path", fakeNormal,
+	)
+
+	headers := map[string]string{}
+
+	res, err := gateway.MakeRequest(
+		"POST",
+		"/bar/bar-path",
+		headers,
+		bytes.NewReader([]byte(`{"request":{"boolField":true,"stringField":"foo"}}`)),
+	)
+	if !assert.NoError(t, err, "got http error") {
+		return
+	}
+
+	assert.Equal(t, 500, res.StatusCode)
+
+	assert.Equal(t, 1, counter)
+}
+
+func TestNormalRecordedClientExceptionErrorResponse(t *testing.T) {
+	var counter int
+
+	gateway, err := testGateway.CreateGateway(t, nil, &testGateway.Options{
+		KnownHTTPBackends: []string{"bar"},
+		TestBinary: filepath.Join(
+			getDirName(), "..", "..", "services", "example-gateway", "main.go",
+		),
+	})
+	if !assert.NoError(t, err, "got bootstrap err") {
+		return
+	}
+	defer gateway.Close()
+
+	fakeNormal := func(w http.ResponseWriter, r *http.Request) {
+		counter++
+
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err, "failed to read client request body") {
+			assert.JSONEq(t, `{"request":{"boolField":true,"stringField":"foo"}}`, string(body))
+		}
+
+		w.WriteHeader(403)
+
+		if _, err := w.Write([]byte(`{"stringField":"foo"}`)); err != nil {
+			t.Fatal("can't write fake response")
+		}
+	}
+
+	gateway.HTTPBackends()["bar"].HandleFunc(
+		"POST", "/bar-path", fakeNormal,
+	)
+
+	headers := map[string]string{}
+
+	res, err := gateway.MakeRequest(
+		"POST",
+		"/bar/bar-path",
+		headers,
+		bytes.NewReader([]byte(`{"request":{"boolField":true,"stringField":"foo"}}`)),
+	)
+	if !assert.NoError(t, err, "got http error") {
+		return
+	}
+
+	assert.Equal(t, 403, res.StatusCode)
+
+	assert.Equal(t, 1, counter)
+}
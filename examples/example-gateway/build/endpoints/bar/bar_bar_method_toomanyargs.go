@@ -30,6 +30,7 @@ import (
 	zanzibar "github.com/uber/zanzibar/runtime"
 	"go.uber.org/zap"
 
+	module "github.com/uber/zanzibar/examples/example-gateway/build/endpoints/bar/module"
 	clientsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/bar/bar"
 	clientsFooFoo "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/foo/foo"
 	endpointsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/bar/bar"
@@ -37,7 +38,7 @@ import (
 
 // TooManyArgsHandler is the handler for "/bar/too-many-args-path"
 type TooManyArgsHandler struct {
-	Clients *clients.Clients
+	Dependencies *module.Dependencies
 }
 
 // NewTooManyArgsEndpoint creates a handler
@@ -45,7 +46,11 @@ func NewTooManyArgsEndpoint(
 	gateway *zanzibar.Gateway,
 ) *TooManyArgsHandler {
 	return &TooManyArgsHandler{
-		Clients: gateway.Clients.(*clients.Clients),
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				Bar: gateway.Clients.(*clients.Clients).Bar,
+			},
+		},
 	}
 }
 
@@ -64,7 +69,7 @@ func (handler *TooManyArgsHandler) HandleRequest(
 	}
 
 	workflow := TooManyArgsEndpoint{
-		Clients: handler.Clients,
+		Clients: &handler.Dependencies.Client,
 		Logger:  req.Logger,
 		Request: req,
 	}
@@ -95,7 +100,7 @@ func (handler *TooManyArgsHandler) HandleRequest(
 
 // TooManyArgsEndpoint calls thrift client Bar.TooManyArgs
 type TooManyArgsEndpoint struct {
-	Clients *clients.Clients
+	Clients *module.ClientDependencies
 	Logger  *zap.Logger
 	Request *zanzibar.ServerHTTPRequest
 }
@@ -25,6 +25,7 @@ package bar
 
 import (
 	"bytes"
+	"io/ioutil"
 	"net/http"
 	"path/filepath"
 	"testing"
@@ -48,6 +49,7 @@ func TestTooManyArgsSuccessfulRequestOKResponse(t *testing.T) {
 	defer gateway.Close()
 
 	fakeTooManyArgs := func(w http.ResponseWriter, r *http.Request) {
+		counter++
 
 		assert.Equal(
 			t,
@@ -58,16 +60,19 @@ func TestTooManyArgsSuccessfulRequestOKResponse(t *testing.T) {
 			"test-uuid",
 			r.Header.Get("X-Uuid"))
 
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err, "failed to read client request body") {
+			assert.JSONEq(t, `{"request":{"boolField":true,"stringField":"foo"}}`, string(body))
+		}
+
 		w.Header().Set("X-Token", "test-token")
 		w.Header().Set("X-Uuid", "test-uuid")
 
 		w.WriteHeader(200)
 
-		// TODO(zw): generate client response.
 		if _, err := w.Write([]byte(`{"intWithRange":0,"intWithoutRange":1,"mapIntWithRange":{},"mapIntWithoutRange":{},"stringField":"foo"}`)); err != nil {
 			t.Fatal("can't write fake response")
 		}
-		counter++
 	}
 
 	gateway.HTTPBackends()["bar"].HandleFunc(
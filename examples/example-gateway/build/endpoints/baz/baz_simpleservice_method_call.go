@@ -30,13 +30,14 @@ import (
 	zanzibar "github.com/uber/zanzibar/runtime"
 	"go.uber.org/zap"
 
+	module "github.com/uber/zanzibar/examples/example-gateway/build/endpoints/baz/module"
 	clientsBazBaz "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/baz/baz"
 	endpointsBazBaz "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/baz/baz"
 )
 
 // CallHandler is the handler for "/baz/call"
 type CallHandler struct {
-	Clients *clients.Clients
+	Dependencies *module.Dependencies
 }
 
 // NewCallEndpoint creates a handler
@@ -44,7 +45,11 @@ func NewCallEndpoint(
 	gateway *zanzibar.Gateway,
 ) *CallHandler {
 	return &CallHandler{
-		Clients: gateway.Clients.(*clients.Clients),
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				Baz: gateway.Clients.(*clients.Clients).Baz,
+			},
+		},
 	}
 }
 
@@ -63,7 +68,7 @@ func (handler *CallHandler) HandleRequest(
 	}
 
 	workflow := CallEndpoint{
-		Clients: handler.Clients,
+		Clients: &handler.Dependencies.Client,
 		Logger:  req.Logger,
 		Request: req,
 	}
@@ -93,7 +98,7 @@ func (handler *CallHandler) HandleRequest(
 
 // CallEndpoint calls thrift client Baz.Call
 type CallEndpoint struct {
-	Clients *clients.Clients
+	Clients *module.ClientDependencies
 	Logger  *zap.Logger
 	Request *zanzibar.ServerHTTPRequest
 }
@@ -30,6 +30,7 @@ import (
 	zanzibar "github.com/uber/zanzibar/runtime"
 	"go.uber.org/zap"
 
+	module "github.com/uber/zanzibar/examples/example-gateway/build/endpoints/baz/module"
 	clientsBazBase "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/baz/base"
 	clientsBazBaz "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/baz/baz"
 	endpointsBazBaz "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/baz/baz"
@@ -37,7 +38,7 @@ import (
 
 // CompareHandler is the handler for "/baz/compare"
 type CompareHandler struct {
-	Clients *clients.Clients
+	Dependencies *module.Dependencies
 }
 
 // NewCompareEndpoint creates a handler
@@ -45,7 +46,11 @@ func NewCompareEndpoint(
 	gateway *zanzibar.Gateway,
 ) *CompareHandler {
 	return &CompareHandler{
-		Clients: gateway.Clients.(*clients.Clients),
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				Baz: gateway.Clients.(*clients.Clients).Baz,
+			},
+		},
 	}
 }
 
@@ -61,7 +66,7 @@ func (handler *CompareHandler) HandleRequest(
 	}
 
 	workflow := CompareEndpoint{
-		Clients: handler.Clients,
+		Clients: &handler.Dependencies.Client,
 		Logger:  req.Logger,
 		Request: req,
 	}
@@ -90,7 +95,7 @@ func (handler *CompareHandler) HandleRequest(
 
 // CompareEndpoint calls thrift client Baz.Compare
 type CompareEndpoint struct {
-	Clients *clients.Clients
+	Clients *module.ClientDependencies
 	Logger  *zap.Logger
 	Request *zanzibar.ServerHTTPRequest
 }
@@ -30,13 +30,14 @@ import (
 	zanzibar "github.com/uber/zanzibar/runtime"
 	"go.uber.org/zap"
 
+	module "github.com/uber/zanzibar/examples/example-gateway/build/endpoints/baz/module"
 	clientsBazBase "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/baz/base"
 	endpointsBazBaz "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/baz/baz"
 )
 
 // PingHandler is the handler for "/baz/ping"
 type PingHandler struct {
-	Clients *clients.Clients
+	Dependencies *module.Dependencies
 }
 
 // NewPingEndpoint creates a handler
@@ -44,7 +45,11 @@ func NewPingEndpoint(
 	gateway *zanzibar.Gateway,
 ) *PingHandler {
 	return &PingHandler{
-		Clients: gateway.Clients.(*clients.Clients),
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				Baz: gateway.Clients.(*clients.Clients).Baz,
+			},
+		},
 	}
 }
 
@@ -56,7 +61,7 @@ func (handler *PingHandler) HandleRequest(
 ) {
 
 	workflow := PingEndpoint{
-		Clients: handler.Clients,
+		Clients: &handler.Dependencies.Client,
 		Logger:  req.Logger,
 		Request: req,
 	}
@@ -79,7 +84,7 @@ func (handler *PingHandler) HandleRequest(
 
 // PingEndpoint calls thrift client Baz.Ping
 type PingEndpoint struct {
-	Clients *clients.Clients
+	Clients *module.ClientDependencies
 	Logger  *zap.Logger
 	Request *zanzibar.ServerHTTPRequest
 }
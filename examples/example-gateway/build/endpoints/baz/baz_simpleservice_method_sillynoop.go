@@ -30,6 +30,7 @@ import (
 	zanzibar "github.com/uber/zanzibar/runtime"
 	"go.uber.org/zap"
 
+	module "github.com/uber/zanzibar/examples/example-gateway/build/endpoints/baz/module"
 	clientsBazBase "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/baz/base"
 	clientsBazBaz "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/baz/baz"
 	endpointsBazBaz "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/baz/baz"
@@ -37,7 +38,7 @@ import (
 
 // SillyNoopHandler is the handler for "/baz/silly-noop"
 type SillyNoopHandler struct {
-	Clients *clients.Clients
+	Dependencies *module.Dependencies
 }
 
 // NewSillyNoopEndpoint creates a handler
@@ -45,7 +46,11 @@ func NewSillyNoopEndpoint(
 	gateway *zanzibar.Gateway,
 ) *SillyNoopHandler {
 	return &SillyNoopHandler{
-		Clients: gateway.Clients.(*clients.Clients),
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				Baz: gateway.Clients.(*clients.Clients).Baz,
+			},
+		},
 	}
 }
 
@@ -57,7 +62,7 @@ func (handler *SillyNoopHandler) HandleRequest(
 ) {
 
 	workflow := SillyNoopEndpoint{
-		Clients: handler.Clients,
+		Clients: &handler.Dependencies.Client,
 		Logger:  req.Logger,
 		Request: req,
 	}
@@ -92,7 +97,7 @@ func (handler *SillyNoopHandler) HandleRequest(
 
 // SillyNoopEndpoint calls thrift client Baz.DeliberateDiffNoop
 type SillyNoopEndpoint struct {
-	Clients *clients.Clients
+	Clients *module.ClientDependencies
 	Logger  *zap.Logger
 	Request *zanzibar.ServerHTTPRequest
 }
@@ -26,6 +26,7 @@ package baz
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"path/filepath"
 	"testing"
 
@@ -33,6 +34,7 @@ import (
 	"github.com/uber/zanzibar/test/lib/test_gateway"
 
 	bazClient "github.com/uber/zanzibar/examples/example-gateway/build/clients/baz"
+	clientsBazBaz "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/baz/baz"
 )
 
 func TestSillyNoopSuccessfulRequestOKResponse(t *testing.T) {
@@ -83,3 +85,57 @@ func TestSillyNoopSuccessfulRequestOKResponse(t *testing.T) {
 	assert.Equal(t, 1, testSillyNoopCounter)
 	assert.Equal(t, 204, res.StatusCode)
 }
+
+func TestSillyNoopAuthErrErrorResponse(t *testing.T) {
+	testSillyNoopCounter := 0
+
+	gateway, err := testGateway.CreateGateway(t, map[string]interface{}{
+		"clients.baz.serviceName": "bazService",
+	}, &testGateway.Options{
+		KnownTChannelBackends: []string{"baz"},
+		TestBinary: filepath.Join(
+			getDirName(), "..", "..", "services", "example-gateway", "main.go",
+		),
+	})
+	if !assert.NoError(t, err, "got bootstrap err") {
+		return
+	}
+	defer gateway.Close()
+
+	fakeDeliberateDiffNoop := func(
+		ctx context.Context,
+		reqHeaders map[string]string,
+	) (map[string]string, error) {
+		testSillyNoopCounter++
+
+		var resHeaders map[string]string
+
+		var exception clientsBazBaz.AuthErr
+		err := json.Unmarshal([]byte(`{"message":"unauthorized"}`), &exception)
+		if err != nil {
+			t.Fatal("can't unmarshal client exception json to client exception struct")
+		}
+		return resHeaders, &exception
+	}
+
+	gateway.TChannelBackends()["baz"].Register(
+		"SimpleService",
+		"SillyNoop",
+		bazClient.NewSimpleServiceSillyNoopHandler(fakeDeliberateDiffNoop),
+	)
+
+	headers := map[string]string{}
+
+	res, err := gateway.MakeRequest(
+		"GET",
+		"/baz/silly-noop",
+		headers,
+		bytes.NewReader([]byte(`{}`)),
+	)
+	if !assert.NoError(t, err, "got http error") {
+		return
+	}
+
+	assert.Equal(t, 1, testSillyNoopCounter)
+	assert.Equal(t, 403, res.StatusCode)
+}
@@ -36,6 +36,7 @@ import (
 	"go.uber.org/thriftrw/wire"
 	"go.uber.org/zap"
 
+	module "github.com/uber/zanzibar/examples/example-gateway/build/endpoints/baz_tchannel/module"
 	endpointsBazTchannelBazTchannel "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/baz_tchannel/baz_tchannel"
 	customBazTchannel "github.com/uber/zanzibar/examples/example-gateway/endpoints/baz_tchannel"
 )
@@ -45,15 +46,19 @@ func NewSimpleServiceCallHandler(
 	gateway *zanzibar.Gateway,
 ) zanzibar.TChannelHandler {
 	return &SimpleServiceCallHandler{
-		Clients: gateway.Clients.(*clients.Clients),
-		Logger:  gateway.Logger,
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				Baz: gateway.Clients.(*clients.Clients).Baz,
+			},
+		},
+		Logger: gateway.Logger,
 	}
 }
 
 // SimpleServiceCallHandler is the handler for "SimpleService::Call".
 type SimpleServiceCallHandler struct {
-	Clients *clients.Clients
-	Logger  *zap.Logger
+	Dependencies *module.Dependencies
+	Logger       *zap.Logger
 }
 
 // Handle handles RPC call of "SimpleService::Call".
@@ -74,7 +79,7 @@ func (h *SimpleServiceCallHandler) Handle(
 		return false, nil, nil, err
 	}
 	workflow := customBazTchannel.CallEndpoint{
-		Clients: h.Clients,
+		Clients: &h.Dependencies.Client,
 		Logger:  h.Logger,
 	}
 
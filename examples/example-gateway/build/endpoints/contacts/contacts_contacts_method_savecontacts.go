@@ -30,13 +30,14 @@ import (
 	zanzibar "github.com/uber/zanzibar/runtime"
 	"go.uber.org/zap"
 
+	module "github.com/uber/zanzibar/examples/example-gateway/build/endpoints/contacts/module"
 	endpointsContactsContacts "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/contacts/contacts"
 	customContacts "github.com/uber/zanzibar/examples/example-gateway/endpoints/contacts"
 )
 
 // SaveContactsHandler is the handler for "/contacts/:userUUID/contacts"
 type SaveContactsHandler struct {
-	Clients *clients.Clients
+	Dependencies *module.Dependencies
 }
 
 // NewSaveContactsEndpoint creates a handler
@@ -44,7 +45,11 @@ func NewSaveContactsEndpoint(
 	gateway *zanzibar.Gateway,
 ) *SaveContactsHandler {
 	return &SaveContactsHandler{
-		Clients: gateway.Clients.(*clients.Clients),
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				Contacts: gateway.Clients.(*clients.Clients).Contacts,
+			},
+		},
 	}
 }
 
@@ -59,8 +64,10 @@ func (handler *SaveContactsHandler) HandleRequest(
 		return
 	}
 
+	requestBody.UserUUID = endpointsContactsContacts.UUID(req.Params.ByName("userUUID"))
+
 	workflow := customContacts.SaveContactsEndpoint{
-		Clients: handler.Clients,
+		Clients: &handler.Dependencies.Client,
 		Logger:  req.Logger,
 		Request: req,
 	}
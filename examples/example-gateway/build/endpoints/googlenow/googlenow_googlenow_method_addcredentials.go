@@ -30,13 +30,14 @@ import (
 	zanzibar "github.com/uber/zanzibar/runtime"
 	"go.uber.org/zap"
 
+	module "github.com/uber/zanzibar/examples/example-gateway/build/endpoints/googlenow/module"
 	clientsGooglenowGooglenow "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/googlenow/googlenow"
 	endpointsGooglenowGooglenow "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/googlenow/googlenow"
 )
 
 // AddCredentialsHandler is the handler for "/googlenow/add-credentials"
 type AddCredentialsHandler struct {
-	Clients *clients.Clients
+	Dependencies *module.Dependencies
 }
 
 // NewAddCredentialsEndpoint creates a handler
@@ -44,7 +45,11 @@ func NewAddCredentialsEndpoint(
 	gateway *zanzibar.Gateway,
 ) *AddCredentialsHandler {
 	return &AddCredentialsHandler{
-		Clients: gateway.Clients.(*clients.Clients),
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				GoogleNow: gateway.Clients.(*clients.Clients).GoogleNow,
+			},
+		},
 	}
 }
 
@@ -63,7 +68,7 @@ func (handler *AddCredentialsHandler) HandleRequest(
 	}
 
 	workflow := AddCredentialsEndpoint{
-		Clients: handler.Clients,
+		Clients: &handler.Dependencies.Client,
 		Logger:  req.Logger,
 		Request: req,
 	}
@@ -87,7 +92,7 @@ func (handler *AddCredentialsHandler) HandleRequest(
 
 // AddCredentialsEndpoint calls thrift client GoogleNow.AddCredentials
 type AddCredentialsEndpoint struct {
-	Clients *clients.Clients
+	Clients *module.ClientDependencies
 	Logger  *zap.Logger
 	Request *zanzibar.ServerHTTPRequest
 }
@@ -25,6 +25,7 @@ package googlenow
 
 import (
 	"bytes"
+	"io/ioutil"
 	"net/http"
 	"path/filepath"
 	"testing"
@@ -48,21 +49,25 @@ func TestAddCredentialsSuccessfulRequestOKResponse(t *testing.T) {
 	defer gateway.Close()
 
 	fakeAddCredentials := func(w http.ResponseWriter, r *http.Request) {
+		counter++
 
 		assert.Equal(
 			t,
 			"test-uuid",
 			r.Header.Get("X-Uuid"))
 
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err, "failed to read client request body") {
+			assert.JSONEq(t, `{"authCode":"test"}`, string(body))
+		}
+
 		w.Header().Set("X-Uuid", "test-uuid")
 
 		w.WriteHeader(202)
 
-		// TODO(zw): generate client response.
 		if _, err := w.Write([]byte(`{"status":"200 OK"}`)); err != nil {
 			t.Fatal("can't write fake response")
 		}
-		counter++
 	}
 
 	gateway.HTTPBackends()["google-now"].HandleFunc(
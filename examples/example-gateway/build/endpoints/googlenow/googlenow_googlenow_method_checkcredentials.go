@@ -29,11 +29,13 @@ import (
 	"github.com/uber/zanzibar/examples/example-gateway/build/clients"
 	zanzibar "github.com/uber/zanzibar/runtime"
 	"go.uber.org/zap"
+
+	module "github.com/uber/zanzibar/examples/example-gateway/build/endpoints/googlenow/module"
 )
 
 // CheckCredentialsHandler is the handler for "/googlenow/check-credentials"
 type CheckCredentialsHandler struct {
-	Clients *clients.Clients
+	Dependencies *module.Dependencies
 }
 
 // NewCheckCredentialsEndpoint creates a handler
@@ -41,7 +43,11 @@ func NewCheckCredentialsEndpoint(
 	gateway *zanzibar.Gateway,
 ) *CheckCredentialsHandler {
 	return &CheckCredentialsHandler{
-		Clients: gateway.Clients.(*clients.Clients),
+		Dependencies: &module.Dependencies{
+			Client: module.ClientDependencies{
+				GoogleNow: gateway.Clients.(*clients.Clients).GoogleNow,
+			},
+		},
 	}
 }
 
@@ -56,7 +62,7 @@ func (handler *CheckCredentialsHandler) HandleRequest(
 	}
 
 	workflow := CheckCredentialsEndpoint{
-		Clients: handler.Clients,
+		Clients: &handler.Dependencies.Client,
 		Logger:  req.Logger,
 		Request: req,
 	}
@@ -80,7 +86,7 @@ func (handler *CheckCredentialsHandler) HandleRequest(
 
 // CheckCredentialsEndpoint calls thrift client GoogleNow.CheckCredentials
 type CheckCredentialsEndpoint struct {
-	Clients *clients.Clients
+	Clients *module.ClientDependencies
 	Logger  *zap.Logger
 	Request *zanzibar.ServerHTTPRequest
 }
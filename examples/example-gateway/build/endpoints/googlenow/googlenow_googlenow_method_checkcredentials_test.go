@@ -48,6 +48,7 @@ func TestCheckCredentialsSuccessfulRequestOKResponse(t *testing.T) {
 	defer gateway.Close()
 
 	fakeCheckCredentials := func(w http.ResponseWriter, r *http.Request) {
+		counter++
 
 		assert.Equal(
 			t,
@@ -58,11 +59,9 @@ func TestCheckCredentialsSuccessfulRequestOKResponse(t *testing.T) {
 
 		w.WriteHeader(202)
 
-		// TODO(zw): generate client response.
 		if _, err := w.Write([]byte(`{"status":"200 OK"}`)); err != nil {
 			t.Fatal("can't write fake response")
 		}
-		counter++
 	}
 
 	gateway.HTTPBackends()["google-now"].HandleFunc(
@@ -0,0 +1,1274 @@
+// Code generated by zanzibar
+// @generated
+
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package endpoints
+
+// openAPIDocument is the OpenAPI document of the http endpoints.
+var openAPIDocument = []byte(`{
+	"openapi": "3.0.0",
+	"info": {
+		"title": "example-gateway",
+		"version": "1.0.0"
+	},
+	"paths": {
+		"/bar/arg-not-struct-path": {
+			"post": {
+				"operationId": "bar.argNotStruct",
+				"tags": [
+					"bar"
+				],
+				"requestBody": {
+					"required": true,
+					"content": {
+						"application/json": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"request": {
+										"type": "string"
+									}
+								},
+								"required": [
+									"request"
+								]
+							}
+						},
+						"application/vnd.apache.thrift.compact": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"request": {
+										"type": "string"
+									}
+								},
+								"required": [
+									"request"
+								]
+							}
+						},
+						"application/x-thrift": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"request": {
+										"type": "string"
+									}
+								},
+								"required": [
+									"request"
+								]
+							}
+						}
+					}
+				},
+				"responses": {
+					"200": {
+						"description": "OK"
+					},
+					"403": {
+						"description": "barException",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"/bar/argWithHeaders": {
+			"post": {
+				"operationId": "bar.argWithHeaders",
+				"tags": [
+					"bar"
+				],
+				"parameters": [
+					{
+						"name": "x-uuid",
+						"in": "header",
+						"required": false,
+						"schema": {
+							"type": "string"
+						}
+					}
+				],
+				"requestBody": {
+					"required": true,
+					"content": {
+						"application/json": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"name": {
+										"type": "string"
+									}
+								},
+								"required": [
+									"name"
+								]
+							}
+						},
+						"application/vnd.apache.thrift.compact": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"name": {
+										"type": "string"
+									}
+								},
+								"required": [
+									"name"
+								]
+							}
+						},
+						"application/x-thrift": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"name": {
+										"type": "string"
+									}
+								},
+								"required": [
+									"name"
+								]
+							}
+						}
+					}
+				},
+				"responses": {
+					"200": {
+						"description": "OK",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"/bar/bar-path": {
+			"post": {
+				"operationId": "bar.normal",
+				"tags": [
+					"bar"
+				],
+				"parameters": [
+					{
+						"name": "some-query-field",
+						"in": "query",
+						"required": true,
+						"schema": {
+							"type": "boolean"
+						}
+					}
+				],
+				"requestBody": {
+					"required": true,
+					"content": {
+						"application/json": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"request": {
+										"$ref": "#/components/schemas/bar.BarRequest"
+									}
+								},
+								"required": [
+									"request"
+								]
+							}
+						},
+						"application/vnd.apache.thrift.compact": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"request": {
+										"$ref": "#/components/schemas/bar.BarRequest"
+									}
+								},
+								"required": [
+									"request"
+								]
+							}
+						},
+						"application/x-thrift": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"request": {
+										"$ref": "#/components/schemas/bar.BarRequest"
+									}
+								},
+								"required": [
+									"request"
+								]
+							}
+						}
+					}
+				},
+				"responses": {
+					"200": {
+						"description": "OK",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							}
+						}
+					},
+					"403": {
+						"description": "barException",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"/bar/missing-arg-path": {
+			"get": {
+				"operationId": "bar.missingArg",
+				"tags": [
+					"bar"
+				],
+				"responses": {
+					"200": {
+						"description": "OK",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							}
+						}
+					},
+					"403": {
+						"description": "barException",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"/bar/no-request-path": {
+			"get": {
+				"operationId": "bar.noRequest",
+				"tags": [
+					"bar"
+				],
+				"responses": {
+					"200": {
+						"description": "OK",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							}
+						}
+					},
+					"403": {
+						"description": "barException",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"/bar/too-many-args-path": {
+			"post": {
+				"operationId": "bar.tooManyArgs",
+				"tags": [
+					"bar"
+				],
+				"parameters": [
+					{
+						"name": "x-uuid",
+						"in": "header",
+						"required": true,
+						"schema": {
+							"type": "string"
+						}
+					},
+					{
+						"name": "x-token",
+						"in": "header",
+						"required": true,
+						"schema": {
+							"type": "string"
+						}
+					},
+					{
+						"name": "some-query-field",
+						"in": "query",
+						"required": true,
+						"schema": {
+							"type": "boolean"
+						}
+					}
+				],
+				"requestBody": {
+					"required": true,
+					"content": {
+						"application/json": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"foo": {
+										"$ref": "#/components/schemas/foo.FooStruct"
+									},
+									"request": {
+										"$ref": "#/components/schemas/bar.BarRequest"
+									}
+								},
+								"required": [
+									"request"
+								]
+							}
+						},
+						"application/vnd.apache.thrift.compact": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"foo": {
+										"$ref": "#/components/schemas/foo.FooStruct"
+									},
+									"request": {
+										"$ref": "#/components/schemas/bar.BarRequest"
+									}
+								},
+								"required": [
+									"request"
+								]
+							}
+						},
+						"application/x-thrift": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"foo": {
+										"$ref": "#/components/schemas/foo.FooStruct"
+									},
+									"request": {
+										"$ref": "#/components/schemas/bar.BarRequest"
+									}
+								},
+								"required": [
+									"request"
+								]
+							}
+						}
+					}
+				},
+				"responses": {
+					"200": {
+						"description": "OK",
+						"headers": {
+							"x-token": {
+								"required": true,
+								"schema": {
+									"type": "string"
+								}
+							},
+							"x-uuid": {
+								"required": true,
+								"schema": {
+									"type": "string"
+								}
+							}
+						},
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarResponse"
+								}
+							}
+						}
+					},
+					"403": {
+						"description": "barException",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/bar.BarException"
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"/baz/call": {
+			"post": {
+				"operationId": "baz.call",
+				"tags": [
+					"baz"
+				],
+				"parameters": [
+					{
+						"name": "x-uuid",
+						"in": "header",
+						"required": true,
+						"schema": {
+							"type": "string"
+						}
+					},
+					{
+						"name": "x-token",
+						"in": "header",
+						"required": true,
+						"schema": {
+							"type": "string"
+						}
+					}
+				],
+				"requestBody": {
+					"required": true,
+					"content": {
+						"application/json": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"arg": {
+										"$ref": "#/components/schemas/baz.BazRequest"
+									}
+								},
+								"required": [
+									"arg"
+								]
+							}
+						},
+						"application/vnd.apache.thrift.compact": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"arg": {
+										"$ref": "#/components/schemas/baz.BazRequest"
+									}
+								},
+								"required": [
+									"arg"
+								]
+							}
+						},
+						"application/x-thrift": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"arg": {
+										"$ref": "#/components/schemas/baz.BazRequest"
+									}
+								},
+								"required": [
+									"arg"
+								]
+							}
+						}
+					}
+				},
+				"responses": {
+					"204": {
+						"description": "No Content",
+						"headers": {
+							"some-res-header": {
+								"required": true,
+								"schema": {
+									"type": "string"
+								}
+							}
+						}
+					},
+					"403": {
+						"description": "authErr",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.AuthErr"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.AuthErr"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.AuthErr"
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"/baz/compare": {
+			"post": {
+				"operationId": "baz.compare",
+				"tags": [
+					"baz"
+				],
+				"requestBody": {
+					"required": true,
+					"content": {
+						"application/json": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"arg1": {
+										"$ref": "#/components/schemas/baz.BazRequest"
+									},
+									"arg2": {
+										"$ref": "#/components/schemas/baz.BazRequest"
+									}
+								},
+								"required": [
+									"arg1",
+									"arg2"
+								]
+							}
+						},
+						"application/vnd.apache.thrift.compact": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"arg1": {
+										"$ref": "#/components/schemas/baz.BazRequest"
+									},
+									"arg2": {
+										"$ref": "#/components/schemas/baz.BazRequest"
+									}
+								},
+								"required": [
+									"arg1",
+									"arg2"
+								]
+							}
+						},
+						"application/x-thrift": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"arg1": {
+										"$ref": "#/components/schemas/baz.BazRequest"
+									},
+									"arg2": {
+										"$ref": "#/components/schemas/baz.BazRequest"
+									}
+								},
+								"required": [
+									"arg1",
+									"arg2"
+								]
+							}
+						}
+					}
+				},
+				"responses": {
+					"200": {
+						"description": "OK",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.BazResponse"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.BazResponse"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.BazResponse"
+								}
+							}
+						}
+					},
+					"403": {
+						"description": "authErr",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.AuthErr"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.AuthErr"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.AuthErr"
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"/baz/ping": {
+			"get": {
+				"operationId": "baz.ping",
+				"tags": [
+					"baz"
+				],
+				"responses": {
+					"200": {
+						"description": "OK",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.BazResponse"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.BazResponse"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.BazResponse"
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"/baz/silly-noop": {
+			"get": {
+				"operationId": "baz.sillyNoop",
+				"tags": [
+					"baz"
+				],
+				"responses": {
+					"204": {
+						"description": "No Content"
+					},
+					"403": {
+						"description": "authErr",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.AuthErr"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.AuthErr"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.AuthErr"
+								}
+							}
+						}
+					},
+					"500": {
+						"description": "serverErr",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.ServerErr"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.ServerErr"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/baz.ServerErr"
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"/contacts/{userUUID}/contacts": {
+			"post": {
+				"operationId": "contacts.saveContacts",
+				"tags": [
+					"contacts"
+				],
+				"parameters": [
+					{
+						"name": "userUUID",
+						"in": "path",
+						"required": true,
+						"schema": {
+							"type": "string"
+						}
+					}
+				],
+				"requestBody": {
+					"required": true,
+					"content": {
+						"application/json": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"contacts": {
+										"type": "array",
+										"items": {
+											"$ref": "#/components/schemas/contacts.Contact"
+										}
+									}
+								},
+								"required": [
+									"contacts"
+								]
+							}
+						},
+						"application/vnd.apache.thrift.compact": {
+							"schema": {
+								"$ref": "#/components/schemas/contacts.SaveContactsRequest"
+							}
+						},
+						"application/x-thrift": {
+							"schema": {
+								"$ref": "#/components/schemas/contacts.SaveContactsRequest"
+							}
+						}
+					}
+				},
+				"responses": {
+					"202": {
+						"description": "Accepted",
+						"content": {
+							"application/json": {
+								"schema": {
+									"$ref": "#/components/schemas/contacts.SaveContactsResponse"
+								}
+							},
+							"application/vnd.apache.thrift.compact": {
+								"schema": {
+									"$ref": "#/components/schemas/contacts.SaveContactsResponse"
+								}
+							},
+							"application/x-thrift": {
+								"schema": {
+									"$ref": "#/components/schemas/contacts.SaveContactsResponse"
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"/googlenow/add-credentials": {
+			"post": {
+				"operationId": "googlenow.addCredentials",
+				"tags": [
+					"googlenow"
+				],
+				"parameters": [
+					{
+						"name": "x-uuid",
+						"in": "header",
+						"required": true,
+						"schema": {
+							"type": "string"
+						}
+					},
+					{
+						"name": "x-token",
+						"in": "header",
+						"required": true,
+						"schema": {
+							"type": "string"
+						}
+					}
+				],
+				"requestBody": {
+					"required": true,
+					"content": {
+						"application/json": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"authCode": {
+										"type": "string"
+									}
+								},
+								"required": [
+									"authCode"
+								]
+							}
+						},
+						"application/vnd.apache.thrift.compact": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"authCode": {
+										"type": "string"
+									}
+								},
+								"required": [
+									"authCode"
+								]
+							}
+						},
+						"application/x-thrift": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"authCode": {
+										"type": "string"
+									}
+								},
+								"required": [
+									"authCode"
+								]
+							}
+						}
+					}
+				},
+				"responses": {
+					"202": {
+						"description": "Accepted",
+						"headers": {
+							"x-uuid": {
+								"required": true,
+								"schema": {
+									"type": "string"
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"/googlenow/check-credentials": {
+			"post": {
+				"operationId": "googlenow.checkCredentials",
+				"tags": [
+					"googlenow"
+				],
+				"parameters": [
+					{
+						"name": "x-uuid",
+						"in": "header",
+						"required": true,
+						"schema": {
+							"type": "string"
+						}
+					},
+					{
+						"name": "x-token",
+						"in": "header",
+						"required": true,
+						"schema": {
+							"type": "string"
+						}
+					}
+				],
+				"responses": {
+					"202": {
+						"description": "Accepted",
+						"headers": {
+							"x-uuid": {
+								"required": true,
+								"schema": {
+									"type": "string"
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	},
+	"components": {
+		"schemas": {
+			"bar.BarException": {
+				"type": "object",
+				"properties": {
+					"stringField": {
+						"type": "string"
+					}
+				},
+				"required": [
+					"stringField"
+				]
+			},
+			"bar.BarRequest": {
+				"type": "object",
+				"properties": {
+					"boolField": {
+						"type": "boolean"
+					},
+					"stringField": {
+						"type": "string"
+					}
+				},
+				"required": [
+					"stringField",
+					"boolField"
+				]
+			},
+			"bar.BarResponse": {
+				"type": "object",
+				"properties": {
+					"intWithRange": {
+						"type": "integer",
+						"format": "int32"
+					},
+					"intWithoutRange": {
+						"type": "integer",
+						"format": "int32"
+					},
+					"mapIntWithRange": {
+						"type": "object",
+						"additionalProperties": {
+							"type": "integer",
+							"format": "int32"
+						}
+					},
+					"mapIntWithoutRange": {
+						"type": "object",
+						"additionalProperties": {
+							"type": "integer",
+							"format": "int32"
+						}
+					},
+					"stringField": {
+						"type": "string"
+					}
+				},
+				"required": [
+					"stringField",
+					"intWithRange",
+					"intWithoutRange",
+					"mapIntWithRange",
+					"mapIntWithoutRange"
+				]
+			},
+			"baz.AuthErr": {
+				"type": "object",
+				"properties": {
+					"message": {
+						"type": "string"
+					}
+				},
+				"required": [
+					"message"
+				]
+			},
+			"baz.BazRequest": {
+				"type": "object",
+				"properties": {
+					"b1": {
+						"type": "boolean"
+					},
+					"i3": {
+						"type": "integer",
+						"format": "int32"
+					},
+					"s2": {
+						"type": "string"
+					}
+				},
+				"required": [
+					"b1",
+					"s2",
+					"i3"
+				]
+			},
+			"baz.BazResponse": {
+				"type": "object",
+				"properties": {
+					"message": {
+						"type": "string"
+					}
+				},
+				"required": [
+					"message"
+				]
+			},
+			"baz.ServerErr": {
+				"type": "object",
+				"properties": {
+					"message": {
+						"type": "string"
+					}
+				},
+				"required": [
+					"message"
+				]
+			},
+			"contacts.Contact": {
+				"type": "object",
+				"properties": {
+					"attributes": {
+						"$ref": "#/components/schemas/contacts.ContactAttributes"
+					},
+					"fragments": {
+						"type": "array",
+						"items": {
+							"$ref": "#/components/schemas/contacts.ContactFragment"
+						}
+					}
+				}
+			},
+			"contacts.ContactAttributes": {
+				"type": "object",
+				"properties": {
+					"firstName": {
+						"type": "string"
+					},
+					"hasCustomRingtone": {
+						"type": "boolean"
+					},
+					"hasPhoto": {
+						"type": "boolean"
+					},
+					"hasThumbnail": {
+						"type": "boolean"
+					},
+					"isSendToVoicemail": {
+						"type": "boolean"
+					},
+					"isStarred": {
+						"type": "boolean"
+					},
+					"lastName": {
+						"type": "string"
+					},
+					"lastTimeContacted": {
+						"type": "integer",
+						"format": "int32"
+					},
+					"namePrefix": {
+						"type": "string"
+					},
+					"nameSuffix": {
+						"type": "string"
+					},
+					"nickname": {
+						"type": "string"
+					},
+					"numFields": {
+						"type": "integer",
+						"format": "int32"
+					},
+					"timesContacted": {
+						"type": "integer",
+						"format": "int32"
+					}
+				}
+			},
+			"contacts.ContactFragment": {
+				"type": "object",
+				"properties": {
+					"text": {
+						"type": "string"
+					},
+					"type": {
+						"type": "string"
+					}
+				}
+			},
+			"contacts.SaveContactsRequest": {
+				"type": "object",
+				"properties": {
+					"contacts": {
+						"type": "array",
+						"items": {
+							"$ref": "#/components/schemas/contacts.Contact"
+						}
+					},
+					"userUUID": {
+						"type": "string"
+					}
+				},
+				"required": [
+					"userUUID",
+					"contacts"
+				]
+			},
+			"contacts.SaveContactsResponse": {
+				"type": "object"
+			},
+			"foo.FooStruct": {
+				"type": "object",
+				"properties": {
+					"fooBool": {
+						"type": "boolean"
+					},
+					"fooDouble": {
+						"type": "number",
+						"format": "double"
+					},
+					"fooI16": {
+						"type": "integer",
+						"format": "int32"
+					},
+					"fooI32": {
+						"type": "integer",
+						"format": "int32"
+					},
+					"fooMap": {
+						"type": "object",
+						"additionalProperties": {
+							"type": "string"
+						}
+					},
+					"fooString": {
+						"type": "string"
+					}
+				},
+				"required": [
+					"fooString"
+				]
+			}
+		}
+	}
+}
+`)
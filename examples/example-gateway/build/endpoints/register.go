@@ -24,11 +24,13 @@
 package endpoints
 
 import (
+	"github.com/uber/zanzibar/examples/example-gateway/build/clients"
 	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints/bar"
 	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints/baz"
 	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints/baz_tchannel"
 	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints/contacts"
 	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints/googlenow"
+	exampleMiddlewareGenerated "github.com/uber/zanzibar/examples/example-gateway/build/middlewares/example"
 	"github.com/uber/zanzibar/examples/example-gateway/middlewares/example"
 	"github.com/uber/zanzibar/runtime/middlewares/logger"
 
@@ -124,6 +126,11 @@ func Register(g *zanzibar.Gateway) {
 			zanzibar.NewStack([]zanzibar.MiddlewareHandle{
 				example.NewMiddleWare(
 					g,
+					&exampleMiddlewareGenerated.Dependencies{
+						Client: exampleMiddlewareGenerated.ClientDependencies{
+							Baz: g.Clients.(*clients.Clients).Baz,
+						},
+					},
 					example.Options{
 						Foo: "test",
 					},
@@ -208,4 +215,21 @@ func Register(g *zanzibar.Gateway) {
 		),
 	)
 	g.TChannelRouter.Register("SimpleService", "Call", endpoints.BazTChannelCallTChannelHandler)
+	g.RegisterOpenAPI(openAPIDocument)
+}
+
+// ConfigSchema declares the config keys required by the modules of the
+// gateway, it is validated when the gateway is created.
+var ConfigSchema = zanzibar.ConfigSchema{
+	"clients.bar.ip":                "string",
+	"clients.bar.port":              "integer",
+	"clients.baz.ip":                "string",
+	"clients.baz.port":              "integer",
+	"clients.baz.serviceName":       "string",
+	"clients.baz.timeout":           "integer",
+	"clients.baz.timeoutPerAttempt": "integer",
+	"clients.contacts.ip":           "string",
+	"clients.contacts.port":         "integer",
+	"clients.google-now.ip":         "string",
+	"clients.google-now.port":       "integer",
 }
@@ -0,0 +1,287 @@
+// Code generated by zanzibar
+// @generated
+
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package bazSDK
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	endpointsBazBaz "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/baz/baz"
+	"github.com/uber/zanzibar/runtime"
+)
+
+// BazClient is the http client of the baz endpoints.
+type BazClient struct {
+	ClientID   string
+	HTTPClient *zanzibar.HTTPClient
+}
+
+// NewClient returns a client of the gateway at baseURL, requests are
+// sent with http.DefaultClient if client is nil.
+func NewClient(
+	baseURL string, client *http.Client,
+) *BazClient {
+	return &BazClient{
+		ClientID:   "baz",
+		HTTPClient: zanzibar.NewStandaloneHTTPClient(baseURL, client, nil),
+	}
+}
+
+// Call calls "/baz/call" endpoint.
+func (c *BazClient) Call(
+	ctx context.Context,
+	headers map[string]string,
+	r *endpointsBazBaz.SimpleService_Call_Args,
+) (map[string]string, error) {
+
+	req := zanzibar.NewClientHTTPRequest(
+		c.ClientID, "Call", c.HTTPClient,
+	)
+	// TODO(jakev): Ensure we validate mandatory headers
+
+	// Generate full URL.
+	fullURL := c.HTTPClient.BaseURL + "/baz" + "/call"
+
+	err := req.WriteJSON("POST", fullURL, headers, r)
+	if err != nil {
+		return nil, err
+	}
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	respHeaders := map[string]string{}
+	for k := range res.Header {
+		respHeaders[k] = res.Header.Get(k)
+	}
+	// TODO(jakev): verify mandatory response headers
+
+	res.CheckOKResponse([]int{204, 403})
+
+	switch res.StatusCode {
+	case 204:
+		// TODO: log about unexpected body bytes?
+		_, err = res.ReadAll()
+		if err != nil {
+			return respHeaders, err
+		}
+
+		return respHeaders, nil
+
+	case 403:
+		var exception endpointsBazBaz.AuthErr
+		err = res.ReadAndUnmarshalBody(&exception)
+		if err != nil {
+			return respHeaders, err
+		}
+		return respHeaders, &exception
+
+	default:
+		// TODO: log about unexpected body bytes?
+		_, err = res.ReadAll()
+		if err != nil {
+			return respHeaders, err
+		}
+	}
+
+	return respHeaders, errors.Errorf(
+		"Unexpected http client response (%d)", res.StatusCode,
+	)
+}
+
+// Compare calls "/baz/compare" endpoint.
+func (c *BazClient) Compare(
+	ctx context.Context,
+	headers map[string]string,
+	r *endpointsBazBaz.SimpleService_Compare_Args,
+) (*endpointsBazBaz.BazResponse, map[string]string, error) {
+
+	req := zanzibar.NewClientHTTPRequest(
+		c.ClientID, "Compare", c.HTTPClient,
+	)
+
+	// Generate full URL.
+	fullURL := c.HTTPClient.BaseURL + "/baz" + "/compare"
+
+	err := req.WriteJSON("POST", fullURL, headers, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	respHeaders := map[string]string{}
+	for k := range res.Header {
+		respHeaders[k] = res.Header.Get(k)
+	}
+
+	res.CheckOKResponse([]int{200, 403})
+
+	switch res.StatusCode {
+	case 200:
+		var responseBody endpointsBazBaz.BazResponse
+		err = res.ReadAndUnmarshalBody(&responseBody)
+		if err != nil {
+			return nil, respHeaders, err
+		}
+
+		return &responseBody, respHeaders, nil
+
+	case 403:
+		var exception endpointsBazBaz.AuthErr
+		err = res.ReadAndUnmarshalBody(&exception)
+		if err != nil {
+			return nil, respHeaders, err
+		}
+		return nil, respHeaders, &exception
+
+	default:
+		// TODO: log about unexpected body bytes?
+		_, err = res.ReadAll()
+		if err != nil {
+			return nil, respHeaders, err
+		}
+	}
+
+	return nil, respHeaders, errors.Errorf(
+		"Unexpected http client response (%d)", res.StatusCode,
+	)
+}
+
+// Ping calls "/baz/ping" endpoint.
+func (c *BazClient) Ping(
+	ctx context.Context,
+	headers map[string]string,
+) (*endpointsBazBaz.BazResponse, map[string]string, error) {
+
+	req := zanzibar.NewClientHTTPRequest(
+		c.ClientID, "Ping", c.HTTPClient,
+	)
+
+	// Generate full URL.
+	fullURL := c.HTTPClient.BaseURL + "/baz" + "/ping"
+
+	err := req.WriteJSON("GET", fullURL, headers, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	respHeaders := map[string]string{}
+	for k := range res.Header {
+		respHeaders[k] = res.Header.Get(k)
+	}
+
+	res.CheckOKResponse([]int{200})
+
+	switch res.StatusCode {
+	case 200:
+		var responseBody endpointsBazBaz.BazResponse
+		err = res.ReadAndUnmarshalBody(&responseBody)
+		if err != nil {
+			return nil, respHeaders, err
+		}
+
+		return &responseBody, respHeaders, nil
+	}
+
+	return nil, respHeaders, errors.Errorf(
+		"Unexpected http client response (%d)", res.StatusCode,
+	)
+}
+
+// SillyNoop calls "/baz/silly-noop" endpoint.
+func (c *BazClient) SillyNoop(
+	ctx context.Context,
+	headers map[string]string,
+) (map[string]string, error) {
+
+	req := zanzibar.NewClientHTTPRequest(
+		c.ClientID, "SillyNoop", c.HTTPClient,
+	)
+
+	// Generate full URL.
+	fullURL := c.HTTPClient.BaseURL + "/baz" + "/silly-noop"
+
+	err := req.WriteJSON("GET", fullURL, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	respHeaders := map[string]string{}
+	for k := range res.Header {
+		respHeaders[k] = res.Header.Get(k)
+	}
+
+	res.CheckOKResponse([]int{204, 403, 500})
+
+	switch res.StatusCode {
+	case 204:
+		// TODO: log about unexpected body bytes?
+		_, err = res.ReadAll()
+		if err != nil {
+			return respHeaders, err
+		}
+
+		return respHeaders, nil
+
+	case 403:
+		var exception endpointsBazBaz.AuthErr
+		err = res.ReadAndUnmarshalBody(&exception)
+		if err != nil {
+			return respHeaders, err
+		}
+		return respHeaders, &exception
+
+	case 500:
+		var exception endpointsBazBaz.ServerErr
+		err = res.ReadAndUnmarshalBody(&exception)
+		if err != nil {
+			return respHeaders, err
+		}
+		return respHeaders, &exception
+
+	default:
+		// TODO: log about unexpected body bytes?
+		_, err = res.ReadAll()
+		if err != nil {
+			return respHeaders, err
+		}
+	}
+
+	return respHeaders, errors.Errorf(
+		"Unexpected http client response (%d)", res.StatusCode,
+	)
+}
@@ -0,0 +1,97 @@
+// Code generated by zanzibar
+// @generated
+
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package contactsSDK
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	endpointsContactsContacts "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/contacts/contacts"
+	"github.com/uber/zanzibar/runtime"
+)
+
+// ContactsClient is the http client of the contacts endpoints.
+type ContactsClient struct {
+	ClientID   string
+	HTTPClient *zanzibar.HTTPClient
+}
+
+// NewClient returns a client of the gateway at baseURL, requests are
+// sent with http.DefaultClient if client is nil.
+func NewClient(
+	baseURL string, client *http.Client,
+) *ContactsClient {
+	return &ContactsClient{
+		ClientID:   "contacts",
+		HTTPClient: zanzibar.NewStandaloneHTTPClient(baseURL, client, nil),
+	}
+}
+
+// SaveContacts calls "/contacts/:userUUID/contacts" endpoint.
+func (c *ContactsClient) SaveContacts(
+	ctx context.Context,
+	headers map[string]string,
+	r *endpointsContactsContacts.SaveContactsRequest,
+) (*endpointsContactsContacts.SaveContactsResponse, map[string]string, error) {
+
+	req := zanzibar.NewClientHTTPRequest(
+		c.ClientID, "saveContacts", c.HTTPClient,
+	)
+
+	// Generate full URL.
+	fullURL := c.HTTPClient.BaseURL + "/contacts" + "/" + url.PathEscape(string(r.UserUUID)) + "/contacts"
+
+	err := req.WriteJSON("POST", fullURL, headers, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	respHeaders := map[string]string{}
+	for k := range res.Header {
+		respHeaders[k] = res.Header.Get(k)
+	}
+
+	res.CheckOKResponse([]int{202})
+
+	switch res.StatusCode {
+	case 202:
+		var responseBody endpointsContactsContacts.SaveContactsResponse
+		err = res.ReadAndUnmarshalBody(&responseBody)
+		if err != nil {
+			return nil, respHeaders, err
+		}
+
+		return &responseBody, respHeaders, nil
+	}
+
+	return nil, respHeaders, errors.Errorf(
+		"Unexpected http client response (%d)", res.StatusCode,
+	)
+}
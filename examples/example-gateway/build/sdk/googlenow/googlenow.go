@@ -0,0 +1,143 @@
+// Code generated by zanzibar
+// @generated
+
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package googlenowSDK
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	endpointsGooglenowGooglenow "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/googlenow/googlenow"
+	"github.com/uber/zanzibar/runtime"
+)
+
+// GooglenowClient is the http client of the googlenow endpoints.
+type GooglenowClient struct {
+	ClientID   string
+	HTTPClient *zanzibar.HTTPClient
+}
+
+// NewClient returns a client of the gateway at baseURL, requests are
+// sent with http.DefaultClient if client is nil.
+func NewClient(
+	baseURL string, client *http.Client,
+) *GooglenowClient {
+	return &GooglenowClient{
+		ClientID:   "googlenow",
+		HTTPClient: zanzibar.NewStandaloneHTTPClient(baseURL, client, nil),
+	}
+}
+
+// AddCredentials calls "/googlenow/add-credentials" endpoint.
+func (c *GooglenowClient) AddCredentials(
+	ctx context.Context,
+	headers map[string]string,
+	r *endpointsGooglenowGooglenow.GoogleNow_AddCredentials_Args,
+) (map[string]string, error) {
+
+	req := zanzibar.NewClientHTTPRequest(
+		c.ClientID, "addCredentials", c.HTTPClient,
+	)
+	// TODO(jakev): Ensure we validate mandatory headers
+
+	// Generate full URL.
+	fullURL := c.HTTPClient.BaseURL + "/googlenow" + "/add-credentials"
+
+	err := req.WriteJSON("POST", fullURL, headers, r)
+	if err != nil {
+		return nil, err
+	}
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	respHeaders := map[string]string{}
+	for k := range res.Header {
+		respHeaders[k] = res.Header.Get(k)
+	}
+	// TODO(jakev): verify mandatory response headers
+
+	res.CheckOKResponse([]int{202})
+
+	switch res.StatusCode {
+	case 202:
+		// TODO: log about unexpected body bytes?
+		_, err = res.ReadAll()
+		if err != nil {
+			return respHeaders, err
+		}
+		return respHeaders, nil
+	}
+
+	return respHeaders, errors.Errorf(
+		"Unexpected http client response (%d)", res.StatusCode,
+	)
+}
+
+// CheckCredentials calls "/googlenow/check-credentials" endpoint.
+func (c *GooglenowClient) CheckCredentials(
+	ctx context.Context,
+	headers map[string]string,
+) (map[string]string, error) {
+
+	req := zanzibar.NewClientHTTPRequest(
+		c.ClientID, "checkCredentials", c.HTTPClient,
+	)
+	// TODO(jakev): Ensure we validate mandatory headers
+
+	// Generate full URL.
+	fullURL := c.HTTPClient.BaseURL + "/googlenow" + "/check-credentials"
+
+	err := req.WriteJSON("POST", fullURL, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	respHeaders := map[string]string{}
+	for k := range res.Header {
+		respHeaders[k] = res.Header.Get(k)
+	}
+	// TODO(jakev): verify mandatory response headers
+
+	res.CheckOKResponse([]int{202})
+
+	switch res.StatusCode {
+	case 202:
+		// TODO: log about unexpected body bytes?
+		_, err = res.ReadAll()
+		if err != nil {
+			return respHeaders, err
+		}
+		return respHeaders, nil
+	}
+
+	return respHeaders, errors.Errorf(
+		"Unexpected http client response (%d)", res.StatusCode,
+	)
+}
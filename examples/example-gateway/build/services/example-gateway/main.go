@@ -24,6 +24,7 @@
 package main
 
 import (
+	"flag"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -47,19 +48,22 @@ func getConfigDirName() string {
 	)
 }
 
+// configLayers selects the environment and datacenter config files
+var configLayers = zanzibar.NewConfigLayersFromEnv()
+
 func getConfig() *zanzibar.StaticConfig {
-	return zanzibar.NewStaticConfigOrDie([]string{
+	return configLayers.NewStaticConfigOrDie([]string{
 		// TODO: zanzibar-defaults should be bundled in the binary
 		filepath.Join(getDirName(), "zanzibar-defaults.json"),
-		filepath.Join(getConfigDirName(), "production.json"),
-		filepath.Join(os.Getenv("CONFIG_DIR"), "production.json"),
-	}, nil)
+	}, getConfigDirName(), os.Getenv("CONFIG_DIR"))
 }
 
 func createGateway() (*zanzibar.Gateway, error) {
 	config := getConfig()
 
-	gateway, err := zanzibar.CreateGateway(config, nil)
+	gateway, err := zanzibar.CreateGateway(config, &zanzibar.Options{
+		ConfigSchema: endpoints.ConfigSchema,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +79,7 @@ func logAndWait(server *zanzibar.Gateway) {
 		zap.String("realHTTPAddr", server.RealHTTPAddr),
 		zap.String("realTChannelAddr", server.RealTChannelAddr),
 		zap.Any("config", server.InspectOrDie()),
+		zap.Any("configSources", server.InspectConfigSourcesOrDie()),
 	)
 
 	// TODO: handle sigterm gracefully
@@ -84,6 +89,9 @@ func logAndWait(server *zanzibar.Gateway) {
 }
 
 func main() {
+	configLayers.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
 	server, err := createGateway()
 	if err != nil {
 		panic(err)
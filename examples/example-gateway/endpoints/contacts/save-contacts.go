@@ -3,7 +3,7 @@ package contacts
 import (
 	"context"
 
-	"github.com/uber/zanzibar/examples/example-gateway/build/clients"
+	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints/contacts/module"
 	contactsClientStructs "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/contacts/contacts"
 	endpointContacts "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/contacts/contacts"
 	zanzibar "github.com/uber/zanzibar/runtime"
@@ -12,7 +12,7 @@ import (
 
 // SaveContactsEndpoint ...
 type SaveContactsEndpoint struct {
-	Clients *clients.Clients
+	Clients *module.ClientDependencies
 	Logger  *zap.Logger
 	Request *zanzibar.ServerHTTPRequest
 }
@@ -24,9 +24,7 @@ func (w SaveContactsEndpoint) Handle(
 	r *endpointContacts.SaveContactsRequest,
 ) (*endpointContacts.SaveContactsResponse, zanzibar.Header, error) {
 	// TODO AuthenticatedRequest()
-	// TODO MatchedIdRequest({paramName: 'userUUID'})
-
-	r.UserUUID = endpointContacts.UUID(w.Request.Params[0].Value)
+	// r.UserUUID is populated from the path by the generated handler
 
 	clientBody := convertToClient(r)
 	cres, _, err := w.Clients.Contacts.SaveContacts(ctx, nil, clientBody)
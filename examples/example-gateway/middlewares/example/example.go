@@ -24,10 +24,12 @@ import (
 	"context"
 
 	"github.com/mcuadros/go-jsonschema-generator"
+	exampleMiddlewareGenerated "github.com/uber/zanzibar/examples/example-gateway/build/middlewares/example"
 	zanzibar "github.com/uber/zanzibar/runtime"
 )
 
 type exampleMiddleware struct {
+	deps    *exampleMiddlewareGenerated.Dependencies
 	options Options
 }
 
@@ -47,8 +49,10 @@ type MiddlewareState struct {
 // after performing it's operations.
 func NewMiddleWare(
 	gateway *zanzibar.Gateway,
+	deps *exampleMiddlewareGenerated.Dependencies,
 	options Options) zanzibar.MiddlewareHandle {
 	return &exampleMiddleware{
+		deps:    deps,
 		options: options,
 	}
 }
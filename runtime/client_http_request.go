@@ -24,7 +24,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"mime/multipart"
 	"net/http"
+	neturl "net/url"
 	"time"
 
 	"github.com/pkg/errors"
@@ -39,6 +41,7 @@ type ClientHTTPRequest struct {
 	client      *HTTPClient
 	httpRequest *http.Request
 	res         *ClientHTTPResponse
+	recorded    *RecordedClientCall
 
 	ClientID   string
 	MethodName string
@@ -83,14 +86,21 @@ func (req *ClientHTTPRequest) start(
 	req.startTime = time.Now()
 }
 
-// WriteJSON will send a json http request out.
+// WriteJSON will send a json http request out. Thrift structs are sent
+// with the thrift protocol instead if the client encoding is thrift.
 func (req *ClientHTTPRequest) WriteJSON(
 	method string, url string, headers map[string]string, body json.Marshaler,
 ) error {
-	var httpReq *http.Request
-	var httpErr error
+	if p := req.client.thrift; p != nil {
+		if s, ok := body.(RWTStruct); ok {
+			return req.writeThrift(method, url, headers, p, s)
+		}
+	}
+
+	var rawBody []byte
 	if body != nil {
-		rawBody, err := body.MarshalJSON()
+		var err error
+		rawBody, err = body.MarshalJSON()
 		if err != nil {
 			req.Logger.Error("Could not serialize client json request",
 				zap.String("error", err.Error()),
@@ -99,7 +109,117 @@ func (req *ClientHTTPRequest) WriteJSON(
 				"Could not serialize json for client: %s", req.ClientID,
 			)
 		}
+	}
+
+	return req.writeBody(method, url, headers, rawBody, "application/json")
+}
+
+func (req *ClientHTTPRequest) writeThrift(
+	method string, url string, headers map[string]string,
+	p *thriftHTTPProtocol, body RWTStruct,
+) error {
+	rawBody, err := p.marshal(body)
+	if err != nil {
+		req.Logger.Error("Could not serialize client thrift request",
+			zap.String("error", err.Error()),
+		)
+		return errors.Wrapf(err,
+			"Could not serialize thrift for client: %s", req.ClientID,
+		)
+	}
+
+	err = req.writeBody(method, url, headers, rawBody, p.contentType)
+	if err != nil {
+		return err
+	}
+	if m, ok := body.(json.Marshaler); ok && req.recorded != nil {
+		if jsonBody, err := m.MarshalJSON(); err == nil {
+			req.recorded.ClientRequest = recordedJSON(jsonBody)
+		}
+	}
+	req.httpRequest.Header.Set("Accept", p.contentType+", application/json")
+	return nil
+}
 
+// WriteForm will send an application/x-www-form-urlencoded http request
+// out. Binary fields are sent as plain form values.
+func (req *ClientHTTPRequest) WriteForm(
+	method string, url string, headers map[string]string, body interface{},
+) error {
+	values, files, err := MarshalForm(body)
+	if err != nil {
+		req.Logger.Error("Could not serialize client form request",
+			zap.String("error", err.Error()),
+		)
+		return errors.Wrapf(err,
+			"Could not serialize form for client: %s", req.ClientID,
+		)
+	}
+	for _, file := range files {
+		values.Set(file.Name, string(file.Content))
+	}
+
+	return req.writeBody(
+		method, url, headers, []byte(values.Encode()),
+		"application/x-www-form-urlencoded",
+	)
+}
+
+// WriteMultipart will send a multipart/form-data http request out.
+// Binary fields are sent as file parts named after the field.
+func (req *ClientHTTPRequest) WriteMultipart(
+	method string, url string, headers map[string]string, body interface{},
+) error {
+	values, files, err := MarshalForm(body)
+	if err == nil {
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		err = writeMultipart(writer, values, files)
+		if err == nil {
+			return req.writeBody(
+				method, url, headers, buf.Bytes(),
+				writer.FormDataContentType(),
+			)
+		}
+	}
+
+	req.Logger.Error("Could not serialize client multipart request",
+		zap.String("error", err.Error()),
+	)
+	return errors.Wrapf(err,
+		"Could not serialize multipart form for client: %s", req.ClientID,
+	)
+}
+
+func writeMultipart(
+	writer *multipart.Writer, values neturl.Values, files []FormFile,
+) error {
+	for name, strs := range values {
+		for _, str := range strs {
+			if err := writer.WriteField(name, str); err != nil {
+				return err
+			}
+		}
+	}
+	for _, file := range files {
+		part, err := writer.CreateFormFile(file.Name, file.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := part.Write(file.Content); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+func (req *ClientHTTPRequest) writeBody(
+	method string, url string, headers map[string]string,
+	rawBody []byte, contentType string,
+) error {
+	var httpReq *http.Request
+	var httpErr error
+	if rawBody != nil {
 		httpReq, httpErr = http.NewRequest(
 			method, url, bytes.NewReader(rawBody),
 		)
@@ -122,7 +242,23 @@ func (req *ClientHTTPRequest) WriteJSON(
 	}
 
 	req.httpRequest = httpReq
-	req.httpRequest.Header.Set("Content-Type", "application/json")
+	req.httpRequest.Header.Set("Content-Type", contentType)
+
+	if req.client.recorder != nil {
+		req.recorded = &RecordedClientCall{
+			ClientID:         req.ClientID,
+			ClientMethod:     req.MethodName,
+			ClientReqHeaders: map[string]string{},
+			HTTPMethod:       method,
+			HTTPPath:         httpReq.URL.Path,
+		}
+		for k, v := range headers {
+			req.recorded.ClientReqHeaders[k] = v
+		}
+		if contentType == "application/json" {
+			req.recorded.ClientRequest = recordedJSON(rawBody)
+		}
+	}
 	return nil
 }
 
@@ -139,5 +275,10 @@ func (req *ClientHTTPRequest) Do(
 	}
 
 	req.res.setRawHTTPResponse(res)
+	if req.recorded != nil {
+		if err := req.res.record(); err != nil {
+			return nil, err
+		}
+	}
 	return req.res, nil
 }
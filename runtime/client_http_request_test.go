@@ -27,6 +27,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/uber/zanzibar/examples/example-gateway/build/clients"
+	"github.com/uber/zanzibar/examples/example-gateway/build/clients/bar"
 	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints"
 	clientsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/bar/bar"
 	zanzibar "github.com/uber/zanzibar/runtime"
@@ -116,7 +117,7 @@ func TestMakingClientCalLWithHeaders(t *testing.T) {
 	)
 
 	clients := bgateway.ActualGateway.Clients.(*clients.Clients)
-	client := clients.Bar.HTTPClient
+	client := clients.Bar.(*barClient.BarClient).HTTPClient
 
 	req := zanzibar.NewClientHTTPRequest("bar", "bar-path", client)
 
@@ -21,6 +21,7 @@
 package zanzibar
 
 import (
+	"bytes"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -30,6 +31,14 @@ import (
 	"go.uber.org/zap"
 )
 
+// unrecordedHeaders are the response headers set by the transport, they
+// are not recorded in the client fixtures.
+var unrecordedHeaders = map[string]bool{
+	"Content-Length": true,
+	"Content-Type":   true,
+	"Date":           true,
+}
+
 // ClientHTTPResponse is the struct managing the client response
 // when making outbound http calls.
 type ClientHTTPResponse struct {
@@ -37,6 +46,9 @@ type ClientHTTPResponse struct {
 	finishTime  time.Time
 	finished    bool
 	rawResponse *http.Response
+	// recorded is the recorded call of a thrift response, its body is
+	// recorded as json once it is decoded.
+	recorded *RecordedClientCall
 
 	StatusCode int
 	Header     http.Header
@@ -86,10 +98,16 @@ func (res *ClientHTTPResponse) ReadAll() ([]byte, error) {
 	return rawBody, nil
 }
 
-// UnmarshalBody will parse body from the client response.
+// UnmarshalBody will parse body from the client response. Bodies with a
+// thrift content type are decoded with the thrift protocol.
 func (res *ClientHTTPResponse) UnmarshalBody(
 	body json.Unmarshaler, rawBody []byte,
 ) error {
+	p := thriftProtocolForContentType(res.Header.Get("Content-Type"))
+	if p != nil {
+		return res.unmarshalThriftBody(p, body, rawBody)
+	}
+
 	err := body.UnmarshalJSON(rawBody)
 	if err != nil {
 		res.req.Logger.Warn("Could not parse client json",
@@ -105,6 +123,80 @@ func (res *ClientHTTPResponse) UnmarshalBody(
 	return nil
 }
 
+func (res *ClientHTTPResponse) unmarshalThriftBody(
+	p *thriftHTTPProtocol, body json.Unmarshaler, rawBody []byte,
+) error {
+	err := p.unmarshal(rawBody, body)
+	if err != nil {
+		res.req.Logger.Warn("Could not parse client thrift",
+			zap.String("error", err.Error()),
+		)
+		return errors.Wrapf(
+			err,
+			"Could not parse client(%s) thrift",
+			res.req.ClientID,
+		)
+	}
+
+	if call := res.recorded; call != nil {
+		res.recorded = nil
+		jsonBody, err := json.Marshal(body)
+		if err == nil {
+			err = res.req.client.recorder.Update(call, func(
+				call *RecordedClientCall,
+			) {
+				call.ClientResponse = recordedJSON(jsonBody)
+			})
+		}
+		if err != nil {
+			res.req.Logger.Warn("Could not record client response",
+				zap.String("error", err.Error()),
+			)
+		}
+	}
+	return nil
+}
+
+// record adds the call to the fixtures of the client when the response
+// arrives, so responses that are never read are recorded too. The body is
+// buffered and replaced for the readers of the response.
+func (res *ClientHTTPResponse) record() error {
+	call := res.req.recorded
+	res.req.recorded = nil
+
+	rawBody, err := ioutil.ReadAll(res.rawResponse.Body)
+	_ = res.rawResponse.Body.Close()
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"Could not read client(%s) response body",
+			res.req.ClientID,
+		)
+	}
+	res.rawResponse.Body = ioutil.NopCloser(bytes.NewReader(rawBody))
+
+	call.ClientStatusCode = res.StatusCode
+	call.ClientResHeaders = map[string]string{}
+	for k := range res.Header {
+		if !unrecordedHeaders[k] {
+			call.ClientResHeaders[k] = res.Header.Get(k)
+		}
+	}
+	// Thrift bodies are recorded as json once they are decoded.
+	if thriftProtocolForContentType(res.Header.Get("Content-Type")) == nil {
+		call.ClientResponse = recordedJSON(rawBody)
+	} else {
+		res.recorded = call
+	}
+
+	if err := res.req.client.recorder.Record(call); err != nil {
+		res.req.Logger.Warn("Could not record client call",
+			zap.String("error", err.Error()),
+		)
+	}
+	return nil
+}
+
 // ReadAndUnmarshalBody will try to unmarshal into struct or fail
 func (res *ClientHTTPResponse) ReadAndUnmarshalBody(
 	body json.Unmarshaler,
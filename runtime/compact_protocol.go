@@ -0,0 +1,531 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+	"go.uber.org/thriftrw/wire"
+)
+
+// Type ids of the thrift compact protocol.
+const (
+	compactBoolTrue  byte = 1
+	compactBoolFalse byte = 2
+	compactI8        byte = 3
+	compactI16       byte = 4
+	compactI32       byte = 5
+	compactI64       byte = 6
+	compactDouble    byte = 7
+	compactBinary    byte = 8
+	compactList      byte = 9
+	compactSet       byte = 10
+	compactMap       byte = 11
+	compactStruct    byte = 12
+)
+
+// maxCompactDepth is the deepest nesting of structs, lists, sets and maps
+// the compact decoder accepts.
+const maxCompactDepth = 64
+
+// compactProtocol encodes thriftrw wire values with the thrift compact
+// protocol, thriftrw only ships the binary protocol.
+type compactProtocol struct{}
+
+// Encode writes the compact encoding of the value to the writer.
+func (compactProtocol) Encode(v wire.Value, w io.Writer) error {
+	e := compactEncoder{}
+	if err := e.writeValue(v); err != nil {
+		return err
+	}
+	_, err := w.Write(e.buf)
+	return err
+}
+
+// Decode reads a compact encoded value of the given type.
+func (compactProtocol) Decode(r io.ReaderAt, t wire.Type) (wire.Value, error) {
+	ctype, err := toCompactType(t)
+	if err != nil {
+		return wire.Value{}, err
+	}
+	d := compactDecoder{reader: r, size: -1}
+	if sized, ok := r.(interface {
+		Size() int64
+	}); ok {
+		d.size = sized.Size()
+	}
+	return d.readValue(ctype)
+}
+
+func toCompactType(t wire.Type) (byte, error) {
+	switch t {
+	case wire.TBool:
+		return compactBoolTrue, nil
+	case wire.TI8:
+		return compactI8, nil
+	case wire.TI16:
+		return compactI16, nil
+	case wire.TI32:
+		return compactI32, nil
+	case wire.TI64:
+		return compactI64, nil
+	case wire.TDouble:
+		return compactDouble, nil
+	case wire.TBinary:
+		return compactBinary, nil
+	case wire.TList:
+		return compactList, nil
+	case wire.TSet:
+		return compactSet, nil
+	case wire.TMap:
+		return compactMap, nil
+	case wire.TStruct:
+		return compactStruct, nil
+	}
+	return 0, errors.Errorf("unknown thrift type %v", t)
+}
+
+func fromCompactType(ctype byte) (wire.Type, error) {
+	switch ctype {
+	case compactBoolTrue, compactBoolFalse:
+		return wire.TBool, nil
+	case compactI8:
+		return wire.TI8, nil
+	case compactI16:
+		return wire.TI16, nil
+	case compactI32:
+		return wire.TI32, nil
+	case compactI64:
+		return wire.TI64, nil
+	case compactDouble:
+		return wire.TDouble, nil
+	case compactBinary:
+		return wire.TBinary, nil
+	case compactList:
+		return wire.TList, nil
+	case compactSet:
+		return wire.TSet, nil
+	case compactMap:
+		return wire.TMap, nil
+	case compactStruct:
+		return wire.TStruct, nil
+	}
+	return 0, errors.Errorf("unknown compact type %d", ctype)
+}
+
+type compactEncoder struct {
+	buf []byte
+}
+
+func (e *compactEncoder) writeUvarint(n uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	e.buf = append(e.buf, tmp[:binary.PutUvarint(tmp[:], n)]...)
+}
+
+func (e *compactEncoder) writeVarint(n int64) {
+	e.writeUvarint(uint64((n << 1) ^ (n >> 63)))
+}
+
+func (e *compactEncoder) writeBool(b bool) {
+	if b {
+		e.buf = append(e.buf, compactBoolTrue)
+	} else {
+		e.buf = append(e.buf, compactBoolFalse)
+	}
+}
+
+func (e *compactEncoder) writeValue(v wire.Value) error {
+	switch v.Type() {
+	case wire.TBool:
+		e.writeBool(v.GetBool())
+	case wire.TI8:
+		e.buf = append(e.buf, byte(v.GetI8()))
+	case wire.TI16:
+		e.writeVarint(int64(v.GetI16()))
+	case wire.TI32:
+		e.writeVarint(int64(v.GetI32()))
+	case wire.TI64:
+		e.writeVarint(v.GetI64())
+	case wire.TDouble:
+		var tmp [8]byte
+		binary.LittleEndian.PutUint64(tmp[:], math.Float64bits(v.GetDouble()))
+		e.buf = append(e.buf, tmp[:]...)
+	case wire.TBinary:
+		b := v.GetBinary()
+		e.writeUvarint(uint64(len(b)))
+		e.buf = append(e.buf, b...)
+	case wire.TStruct:
+		return e.writeStruct(v.GetStruct())
+	case wire.TList:
+		return e.writeList(v.GetList())
+	case wire.TSet:
+		return e.writeList(v.GetSet())
+	case wire.TMap:
+		return e.writeMap(v.GetMap())
+	default:
+		return errors.Errorf("unknown thrift type %v", v.Type())
+	}
+	return nil
+}
+
+func (e *compactEncoder) writeStruct(s wire.Struct) error {
+	var lastID int16
+	for _, field := range s.Fields {
+		var ctype byte
+		if field.Value.Type() == wire.TBool {
+			ctype = compactBoolFalse
+			if field.Value.GetBool() {
+				ctype = compactBoolTrue
+			}
+		} else {
+			var err error
+			ctype, err = toCompactType(field.Value.Type())
+			if err != nil {
+				return err
+			}
+		}
+
+		delta := int(field.ID) - int(lastID)
+		if delta > 0 && delta <= 15 {
+			e.buf = append(e.buf, byte(delta<<4)|ctype)
+		} else {
+			e.buf = append(e.buf, ctype)
+			e.writeVarint(int64(field.ID))
+		}
+		lastID = field.ID
+
+		if field.Value.Type() == wire.TBool {
+			continue
+		}
+		if err := e.writeValue(field.Value); err != nil {
+			return err
+		}
+	}
+	e.buf = append(e.buf, 0)
+	return nil
+}
+
+func (e *compactEncoder) writeList(l wire.ValueList) error {
+	ctype, err := toCompactType(l.ValueType())
+	if err != nil {
+		return err
+	}
+	if size := l.Size(); size < 15 {
+		e.buf = append(e.buf, byte(size<<4)|ctype)
+	} else {
+		e.buf = append(e.buf, 0xf0|ctype)
+		e.writeUvarint(uint64(size))
+	}
+	return l.ForEach(e.writeValue)
+}
+
+func (e *compactEncoder) writeMap(m wire.MapItemList) error {
+	if m.Size() == 0 {
+		e.buf = append(e.buf, 0)
+		return nil
+	}
+	ktype, err := toCompactType(m.KeyType())
+	if err != nil {
+		return err
+	}
+	vtype, err := toCompactType(m.ValueType())
+	if err != nil {
+		return err
+	}
+	e.writeUvarint(uint64(m.Size()))
+	e.buf = append(e.buf, ktype<<4|vtype)
+	return m.ForEach(func(item wire.MapItem) error {
+		if err := e.writeValue(item.Key); err != nil {
+			return err
+		}
+		return e.writeValue(item.Value)
+	})
+}
+
+type compactDecoder struct {
+	reader io.ReaderAt
+	offset int64
+	// size of the input, or -1 if the reader does not know it.
+	size  int64
+	depth int
+}
+
+// checkRemaining rejects n values of at least minBytes each if they can not
+// fit in the rest of the input.
+func (d *compactDecoder) checkRemaining(n int, minBytes int64) error {
+	need := int64(n) * minBytes
+	if d.size < 0 {
+		if need == 0 {
+			return nil
+		}
+		// Probe the last byte needed instead of trusting the size.
+		var probe [1]byte
+		if _, err := d.reader.ReadAt(probe[:], d.offset+need-1); err != nil {
+			return errors.Errorf("compact size %d exceeds the input", n)
+		}
+		return nil
+	}
+	if remaining := d.size - d.offset; need > remaining {
+		return errors.Errorf(
+			"compact size %d exceeds the %d remaining bytes", n, remaining,
+		)
+	}
+	return nil
+}
+
+func (d *compactDecoder) read(n int) ([]byte, error) {
+	if err := d.checkRemaining(n, 1); err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	read, err := d.reader.ReadAt(b, d.offset)
+	d.offset += int64(read)
+	if read == n {
+		return b, nil
+	}
+	if err == nil || err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return nil, err
+}
+
+func (d *compactDecoder) readByte() (byte, error) {
+	b, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (d *compactDecoder) readUvarint() (uint64, error) {
+	var n uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		b, err := d.readByte()
+		if err != nil {
+			return 0, err
+		}
+		n |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return n, nil
+		}
+	}
+	return 0, errors.New("compact varint overflows 64 bits")
+}
+
+func (d *compactDecoder) readVarint() (int64, error) {
+	n, err := d.readUvarint()
+	return int64(n>>1) ^ -int64(n&1), err
+}
+
+func (d *compactDecoder) readSize() (int, error) {
+	n, err := d.readUvarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxInt32 {
+		return 0, errors.Errorf("compact size %d is too large", n)
+	}
+	return int(n), nil
+}
+
+func (d *compactDecoder) readValue(ctype byte) (wire.Value, error) {
+	switch ctype {
+	case compactStruct, compactList, compactSet, compactMap:
+		if d.depth >= maxCompactDepth {
+			return wire.Value{}, errors.Errorf(
+				"compact value is nested deeper than %d", maxCompactDepth,
+			)
+		}
+		d.depth++
+		defer func() { d.depth-- }()
+	}
+
+	switch ctype {
+	case compactBoolTrue, compactBoolFalse:
+		b, err := d.readByte()
+		return wire.NewValueBool(b == compactBoolTrue), err
+	case compactI8:
+		b, err := d.readByte()
+		return wire.NewValueI8(int8(b)), err
+	case compactI16:
+		n, err := d.readVarint()
+		return wire.NewValueI16(int16(n)), err
+	case compactI32:
+		n, err := d.readVarint()
+		return wire.NewValueI32(int32(n)), err
+	case compactI64:
+		n, err := d.readVarint()
+		return wire.NewValueI64(n), err
+	case compactDouble:
+		b, err := d.read(8)
+		if err != nil {
+			return wire.Value{}, err
+		}
+		bits := binary.LittleEndian.Uint64(b)
+		return wire.NewValueDouble(math.Float64frombits(bits)), nil
+	case compactBinary:
+		size, err := d.readSize()
+		if err != nil {
+			return wire.Value{}, err
+		}
+		b, err := d.read(size)
+		return wire.NewValueBinary(b), err
+	case compactStruct:
+		s, err := d.readStruct()
+		return wire.NewValueStruct(s), err
+	case compactList:
+		l, err := d.readList()
+		return wire.NewValueList(l), err
+	case compactSet:
+		l, err := d.readList()
+		return wire.NewValueSet(l), err
+	case compactMap:
+		m, err := d.readMap()
+		return wire.NewValueMap(m), err
+	}
+	return wire.Value{}, errors.Errorf("unknown compact type %d", ctype)
+}
+
+func (d *compactDecoder) readStruct() (wire.Struct, error) {
+	var s wire.Struct
+	var lastID int16
+	for {
+		header, err := d.readByte()
+		if err != nil {
+			return s, err
+		}
+		if header == 0 {
+			return s, nil
+		}
+
+		ctype := header & 0x0f
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			n, err := d.readVarint()
+			if err != nil {
+				return s, err
+			}
+			id = int16(n)
+		}
+		lastID = id
+
+		var value wire.Value
+		switch ctype {
+		case compactBoolTrue, compactBoolFalse:
+			value = wire.NewValueBool(ctype == compactBoolTrue)
+		default:
+			value, err = d.readValue(ctype)
+			if err != nil {
+				return s, err
+			}
+		}
+		s.Fields = append(s.Fields, wire.Field{ID: id, Value: value})
+	}
+}
+
+func (d *compactDecoder) readList() (wire.ValueList, error) {
+	header, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	ctype := header & 0x0f
+	size := int(header >> 4)
+	if size == 15 {
+		size, err = d.readSize()
+		if err != nil {
+			return nil, err
+		}
+	}
+	t, err := fromCompactType(ctype)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.checkRemaining(size, compactMinSize(ctype)); err != nil {
+		return nil, err
+	}
+
+	var values []wire.Value
+	for i := 0; i < size; i++ {
+		value, err := d.readValue(ctype)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return wire.ValueListFromSlice(t, values), nil
+}
+
+func (d *compactDecoder) readMap() (wire.MapItemList, error) {
+	size, err := d.readSize()
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		return wire.MapItemListFromSlice(wire.TBinary, wire.TBinary, nil), nil
+	}
+	if err := d.checkRemaining(size, 2); err != nil {
+		return nil, err
+	}
+
+	types, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	kctype, vctype := types>>4, types&0x0f
+	ktype, err := fromCompactType(kctype)
+	if err != nil {
+		return nil, err
+	}
+	vtype, err := fromCompactType(vctype)
+	if err != nil {
+		return nil, err
+	}
+	minSize := compactMinSize(kctype) + compactMinSize(vctype)
+	if err := d.checkRemaining(size, minSize); err != nil {
+		return nil, err
+	}
+
+	var items []wire.MapItem
+	for i := 0; i < size; i++ {
+		key, err := d.readValue(kctype)
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.readValue(vctype)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, wire.MapItem{Key: key, Value: value})
+	}
+	return wire.MapItemListFromSlice(ktype, vtype, items), nil
+}
+
+// compactMinSize returns the fewest bytes a value of the compact type is
+// encoded in.
+func compactMinSize(ctype byte) int64 {
+	if ctype == compactDouble {
+		return 8
+	}
+	return 1
+}
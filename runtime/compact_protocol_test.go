@@ -0,0 +1,189 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/thriftrw/wire"
+)
+
+func TestCompactEncoding(t *testing.T) {
+	value := wire.NewValueStruct(wire.Struct{Fields: []wire.Field{
+		{ID: 1, Value: wire.NewValueI32(-1)},
+		{ID: 2, Value: wire.NewValueBool(true)},
+		{ID: 20, Value: wire.NewValueString("hi")},
+		{ID: 21, Value: wire.NewValueList(wire.ValueListFromSlice(
+			wire.TI64, []wire.Value{wire.NewValueI64(1), wire.NewValueI64(2)},
+		))},
+		{ID: 22, Value: wire.NewValueMap(wire.MapItemListFromSlice(
+			wire.TBinary, wire.TI32, []wire.MapItem{{
+				Key:   wire.NewValueString("a"),
+				Value: wire.NewValueI32(1),
+			}},
+		))},
+	}})
+
+	var buf bytes.Buffer
+	require.NoError(t, compactProtocol{}.Encode(value, &buf))
+	assert.Equal(t, []byte{
+		0x15, 0x01, // i32 field 1
+		0x11,                         // bool field 2
+		0x08, 0x28, 0x02, 0x68, 0x69, // binary field 20
+		0x19, 0x26, 0x02, 0x04, // list field 21
+		0x1b, 0x01, 0x85, 0x01, 0x61, 0x02, // map field 22
+		0x00,
+	}, buf.Bytes())
+
+	decoded, err := compactProtocol{}.Decode(
+		bytes.NewReader(buf.Bytes()), wire.TStruct,
+	)
+	require.NoError(t, err)
+	assert.True(t, wire.ValuesAreEqual(value, decoded), "got %v", decoded)
+}
+
+func TestCompactRoundTrip(t *testing.T) {
+	list := make([]wire.Value, 20)
+	for i := range list {
+		list[i] = wire.NewValueI32(int32(i))
+	}
+	value := wire.NewValueStruct(wire.Struct{Fields: []wire.Field{
+		{ID: -3, Value: wire.NewValueBool(false)},
+		{ID: 1, Value: wire.NewValueI8(-8)},
+		{ID: 2, Value: wire.NewValueI16(-300)},
+		{ID: 3, Value: wire.NewValueI64(-1 << 62)},
+		{ID: 4, Value: wire.NewValueDouble(3.25)},
+		{ID: 5, Value: wire.NewValueBinary([]byte{0, 255})},
+		{ID: 6, Value: wire.NewValueStruct(wire.Struct{})},
+		{ID: 7, Value: wire.NewValueSet(wire.ValueListFromSlice(
+			wire.TBool, []wire.Value{
+				wire.NewValueBool(true), wire.NewValueBool(false),
+			},
+		))},
+		{ID: 8, Value: wire.NewValueList(wire.ValueListFromSlice(
+			wire.TI32, list,
+		))},
+		{ID: 9, Value: wire.NewValueMap(wire.MapItemListFromSlice(
+			wire.TBinary, wire.TBinary, nil,
+		))},
+	}})
+	var buf bytes.Buffer
+	require.NoError(t, compactProtocol{}.Encode(value, &buf))
+	decoded, err := compactProtocol{}.Decode(
+		bytes.NewReader(buf.Bytes()), wire.TStruct,
+	)
+	require.NoError(t, err)
+	assert.True(t, wire.ValuesAreEqual(value, decoded), "got %v", decoded)
+}
+
+func TestCompactDecodeErrors(t *testing.T) {
+	for _, raw := range [][]byte{
+		{0x15},             // missing i32 value
+		{0x18, 0x05, 0x61}, // binary shorter than its length
+		{0x1d},             // unknown type
+		{0x15, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	} {
+		_, err := compactProtocol{}.Decode(bytes.NewReader(raw), wire.TStruct)
+		assert.Error(t, err, "decoding %x", raw)
+	}
+}
+
+// unsizedReader hides the Size method of a bytes.Reader.
+type unsizedReader struct {
+	r *bytes.Reader
+}
+
+func (u unsizedReader) ReadAt(b []byte, off int64) (int, error) {
+	return u.r.ReadAt(b, off)
+}
+
+func TestCompactDecodeHugeSizes(t *testing.T) {
+	huge := []byte{0xff, 0xff, 0xff, 0xff, 0x07} // math.MaxInt32
+	for _, raw := range [][]byte{
+		append([]byte{0x18}, huge...),       // binary
+		append([]byte{0x19, 0xf5}, huge...), // list of i32
+		append([]byte{0x1a, 0xf7}, huge...), // set of double
+		append([]byte{0x1b}, huge...),       // map
+	} {
+		raw = append(raw, 0x01, 0x02, 0x03)
+		_, err := compactProtocol{}.Decode(bytes.NewReader(raw), wire.TStruct)
+		if assert.Error(t, err, "decoding %x", raw) {
+			assert.Contains(t, err.Error(), "exceeds the")
+		}
+
+		_, err = compactProtocol{}.Decode(
+			unsizedReader{bytes.NewReader(raw)}, wire.TStruct,
+		)
+		if assert.Error(t, err, "decoding unsized %x", raw) {
+			assert.Contains(t, err.Error(), "exceeds the input")
+		}
+	}
+}
+
+// nestedStructs returns depth structs each nested in field 1 of the last.
+func nestedStructs(depth int) []byte {
+	raw := bytes.Repeat([]byte{0x1c}, depth-1)
+	return append(raw, bytes.Repeat([]byte{0x00}, depth)...)
+}
+
+func TestCompactDecodeDepth(t *testing.T) {
+	_, err := compactProtocol{}.Decode(
+		bytes.NewReader(nestedStructs(maxCompactDepth)), wire.TStruct,
+	)
+	assert.NoError(t, err)
+
+	_, err = compactProtocol{}.Decode(
+		bytes.NewReader(nestedStructs(maxCompactDepth+1)), wire.TStruct,
+	)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "nested deeper than 64")
+	}
+
+	// Lists of lists count towards the same depth.
+	raw := append([]byte{0x19}, bytes.Repeat([]byte{0x19}, 100)...)
+	_, err = compactProtocol{}.Decode(bytes.NewReader(raw), wire.TStruct)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "nested deeper than 64")
+	}
+}
+
+func TestThriftProtocolForAccept(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   *thriftHTTPProtocol
+		found  bool
+	}{
+		{"application/x-thrift", thriftBinaryHTTP, true},
+		{"application/vnd.apache.thrift.compact, application/json", thriftCompactHTTP, true},
+		{"application/json, application/x-thrift", nil, true},
+		{"application/json;q=0.5, application/x-thrift", thriftBinaryHTTP, true},
+		{"*/*", nil, true},
+		{"text/html", nil, false},
+	}
+	for _, test := range tests {
+		got, found := thriftProtocolForAccept(test.accept)
+		assert.Equal(t, test.want, got, test.accept)
+		assert.Equal(t, test.found, found, test.accept)
+	}
+}
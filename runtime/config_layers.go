@@ -0,0 +1,160 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The environment variables selecting the config layers of a gateway
+const (
+	EnvironmentEnvVar = "ZANZIBAR_ENVIRONMENT"
+	DatacenterEnvVar  = "ZANZIBAR_DATACENTER"
+)
+
+// defaultEnvironment is the environment of a gateway when none is selected.
+const defaultEnvironment = "production"
+
+// ConfigEnvironments are the environments a gateway can be configured for.
+var ConfigEnvironments = []string{"development", "test", "staging", "production"}
+
+// ConfigLayers selects the config files of the environment and datacenter a
+// gateway runs in. For every config dir, the environment file, for instance
+// production.json, overrides the earlier files and the datacenter file,
+// for instance production-sjc1.json, overrides the environment file.
+// The environment variables with the ConfigEnvVarPrefix and then the
+// Overrides override the files.
+type ConfigLayers struct {
+	// Environment is one of ConfigEnvironments.
+	Environment string
+	// Datacenter is optional, there is no datacenter file without it.
+	Datacenter string
+	// Overrides are key=value pairs overriding config keys.
+	Overrides []string
+}
+
+// NewConfigLayersFromEnv returns the config layers selected by the
+// ZANZIBAR_ENVIRONMENT and ZANZIBAR_DATACENTER environment variables, the
+// environment is production if it is not set.
+func NewConfigLayersFromEnv() *ConfigLayers {
+	layers := &ConfigLayers{
+		Environment: os.Getenv(EnvironmentEnvVar),
+		Datacenter:  os.Getenv(DatacenterEnvVar),
+	}
+	if layers.Environment == "" {
+		layers.Environment = defaultEnvironment
+	}
+	return layers
+}
+
+// RegisterFlags registers the -environment and -datacenter flags, which
+// override the layers selected by the environment variables, and the
+// repeatable -set key=value flag, which adds to the Overrides.
+func (layers *ConfigLayers) RegisterFlags(flags *flag.FlagSet) {
+	flags.StringVar(
+		&layers.Environment, "environment", layers.Environment,
+		"environment of the config files, one of development, test, "+
+			"staging or production",
+	)
+	flags.StringVar(
+		&layers.Datacenter, "datacenter", layers.Datacenter,
+		"datacenter of the config files",
+	)
+	flags.Var(
+		configOverridesFlag{&layers.Overrides}, "set",
+		"key=value overriding a config key, can be repeated",
+	)
+}
+
+// configOverridesFlag appends the value of every -set flag to the overrides.
+type configOverridesFlag struct {
+	overrides *[]string
+}
+
+func (f configOverridesFlag) String() string {
+	if f.overrides == nil {
+		return ""
+	}
+	return strings.Join(*f.overrides, ",")
+}
+
+func (f configOverridesFlag) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return errors.Errorf("expected key=value, got %q", value)
+	}
+	*f.overrides = append(*f.overrides, value)
+	return nil
+}
+
+// Validate returns an error if the environment is not one of
+// ConfigEnvironments.
+func (layers *ConfigLayers) Validate() error {
+	for _, env := range ConfigEnvironments {
+		if layers.Environment == env {
+			return nil
+		}
+	}
+	return errors.Errorf(
+		"Unknown config environment %q, expected one of %v",
+		layers.Environment, ConfigEnvironments,
+	)
+}
+
+// Files returns the base files followed by the environment and datacenter
+// files of each config dir, the later files overwrite keys from earlier files.
+func (layers *ConfigLayers) Files(baseFiles []string, dirs ...string) []string {
+	files := append([]string{}, baseFiles...)
+	for _, dir := range dirs {
+		files = append(files, filepath.Join(dir, layers.Environment+".json"))
+		if layers.Datacenter != "" {
+			files = append(files, filepath.Join(
+				dir, layers.Environment+"-"+layers.Datacenter+".json",
+			))
+		}
+	}
+	return files
+}
+
+// NewStaticConfigOrDie creates the static config of the layers from the base
+// files and the config dirs, with the overrides of the environment variables
+// and of the Overrides. The datacenter, if any, is set as the "datacenter"
+// key of the config.
+func (layers *ConfigLayers) NewStaticConfigOrDie(
+	baseFiles []string, dirs ...string,
+) *StaticConfig {
+	if err := layers.Validate(); err != nil {
+		panic(err)
+	}
+
+	seedConfig := map[string]interface{}{}
+	if layers.Datacenter != "" {
+		seedConfig["datacenter"] = layers.Datacenter
+	}
+	config := NewStaticConfigOrDie(layers.Files(baseFiles, dirs...), seedConfig)
+	config.OverrideFromEnvOrDie(os.Environ())
+	config.OverrideFromFlagsOrDie(layers.Overrides)
+	return config
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	zanzibar "github.com/uber/zanzibar/runtime"
+)
+
+func TestConfigLayersFiles(t *testing.T) {
+	layers := &zanzibar.ConfigLayers{Environment: "staging"}
+	assert.Equal(t, []string{
+		"defaults.json",
+		filepath.Join("app", "staging.json"),
+		filepath.Join("override", "staging.json"),
+	}, layers.Files([]string{"defaults.json"}, "app", "override"))
+
+	layers.Datacenter = "sjc1"
+	assert.Equal(t, []string{
+		filepath.Join("app", "staging.json"),
+		filepath.Join("app", "staging-sjc1.json"),
+	}, layers.Files(nil, "app"))
+}
+
+func TestConfigLayersFromEnvAndFlags(t *testing.T) {
+	defer func() {
+		assert.NoError(t, os.Unsetenv(zanzibar.EnvironmentEnvVar))
+		assert.NoError(t, os.Unsetenv(zanzibar.DatacenterEnvVar))
+	}()
+
+	layers := zanzibar.NewConfigLayersFromEnv()
+	assert.Equal(t, "production", layers.Environment)
+	assert.Equal(t, "", layers.Datacenter)
+
+	assert.NoError(t, os.Setenv(zanzibar.EnvironmentEnvVar, "development"))
+	assert.NoError(t, os.Setenv(zanzibar.DatacenterEnvVar, "sjc1"))
+	layers = zanzibar.NewConfigLayersFromEnv()
+	assert.Equal(t, "development", layers.Environment)
+	assert.Equal(t, "sjc1", layers.Datacenter)
+
+	flags := flag.NewFlagSet("gateway", flag.ContinueOnError)
+	layers.RegisterFlags(flags)
+	assert.NoError(t, flags.Parse([]string{"-environment", "test"}))
+	assert.Equal(t, "test", layers.Environment)
+	assert.Equal(t, "sjc1", layers.Datacenter)
+}
+
+func TestConfigLayersOverride(t *testing.T) {
+	closer := WriteFixture(testDir, map[string][]byte{
+		"config/defaults.json": mustMarshal(map[string]string{
+			"a": "defaults",
+			"b": "defaults",
+			"c": "defaults",
+		}),
+		"config/staging.json": mustMarshal(map[string]string{
+			"b": "staging",
+			"c": "staging",
+		}),
+		"config/staging-sjc1.json": mustMarshal(map[string]string{
+			"c": "staging-sjc1",
+		}),
+	})
+	defer closer.Close()
+
+	layers := &zanzibar.ConfigLayers{
+		Environment: "staging",
+		Datacenter:  "sjc1",
+	}
+	config := layers.NewStaticConfigOrDie([]string{
+		filepath.Join(testDir, "config", "defaults.json"),
+	}, filepath.Join(testDir, "config"))
+
+	assert.Equal(t, "defaults", config.MustGetString("a"))
+	assert.Equal(t, "staging", config.MustGetString("b"))
+	assert.Equal(t, "staging-sjc1", config.MustGetString("c"))
+	assert.Equal(t, "sjc1", config.MustGetString("datacenter"))
+
+	config.SetOrDie("d", "set")
+	assert.Equal(t, map[string]string{
+		"a":          filepath.Join(testDir, "config", "defaults.json"),
+		"b":          filepath.Join(testDir, "config", "staging.json"),
+		"c":          filepath.Join(testDir, "config", "staging-sjc1.json"),
+		"d":          "set",
+		"datacenter": "seed",
+	}, config.InspectSourcesOrDie())
+}
+
+func TestConfigLayersUnknownEnvironment(t *testing.T) {
+	layers := &zanzibar.ConfigLayers{Environment: "prod"}
+	assert.Error(t, layers.Validate())
+	assert.Panics(t, func() {
+		layers.NewStaticConfigOrDie(nil)
+	})
+}
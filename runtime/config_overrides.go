@@ -0,0 +1,242 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/buger/jsonparser"
+	"github.com/pkg/errors"
+)
+
+// ConfigEnvVarPrefix is the prefix of the environment variables overriding
+// config keys, ZANZIBAR_HTTP_PORT overrides http.port.
+const ConfigEnvVarPrefix = "ZANZIBAR_"
+
+// The sources of overridden values
+const (
+	envOverrideSource  = "env:"
+	flagOverrideSource = "flag:-set"
+)
+
+// ConfigEnvVarName returns the environment variable overriding a config key,
+// the key in upper case with every character other than a letter or a digit
+// replaced by an underscore.
+func ConfigEnvVarName(key string) string {
+	return ConfigEnvVarPrefix + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, key)
+}
+
+// OverrideFromEnvOrDie overrides the existing config keys whose
+// ConfigEnvVarName is set in environ, given as KEY=value pairs like
+// os.Environ(). The other variables, including the variables of the config
+// layers and any ZANZIBAR_ variable not naming a key, are ignored.
+// OverrideFromEnvOrDie() will panic if a set variable names several keys.
+func (conf *StaticConfig) OverrideFromEnvOrDie(environ []string) {
+	keys := map[string][]string{}
+	for _, key := range conf.keys() {
+		name := ConfigEnvVarName(key)
+		keys[name] = append(keys[name], key)
+	}
+
+	for _, pair := range environ {
+		parts := strings.SplitN(pair, "=", 2)
+		name := parts[0]
+		if len(parts) != 2 ||
+			name == EnvironmentEnvVar || name == DatacenterEnvVar {
+			continue
+		}
+
+		switch matches := keys[name]; len(matches) {
+		case 0:
+			continue
+		case 1:
+			conf.OverrideOrDie(matches[0], parts[1], envOverrideSource+name)
+		default:
+			panic(errors.Errorf(
+				"Keys (%s) are all overridden by %s",
+				strings.Join(matches, ", "), name,
+			))
+		}
+	}
+}
+
+// OverrideFromFlagsOrDie overrides config keys with the key=value pairs of
+// the -set flags.
+func (conf *StaticConfig) OverrideFromFlagsOrDie(overrides []string) {
+	for _, override := range overrides {
+		parts := strings.SplitN(override, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			panic(errors.Errorf(
+				"Config override (%s) is not key=value", override,
+			))
+		}
+		conf.OverrideOrDie(parts[0], parts[1], flagOverrideSource)
+	}
+}
+
+// OverrideOrDie overrides the value of a key with a value given as a string,
+// for instance by an environment variable. The value is converted to the
+// type of the value it overrides, so that it can be read with the same
+// getter, and an integer can only be overridden by an integer. If the key
+// does not exist yet, the value is read as json, or as a string if it is not
+// json. A seeded value can only be overridden if it is a string, a boolean
+// or a number. OverrideOrDie() will panic if the value cannot be converted
+// or if frozen.
+func (conf *StaticConfig) OverrideOrDie(key string, value string, source string) {
+	if conf.frozen {
+		panic(errors.Errorf("Cannot override(%s) because frozen", key))
+	}
+
+	dataType, integer := jsonparser.NotExist, false
+	if v, contains := conf.seedConfig[key]; contains {
+		var ok bool
+		if dataType, ok = seedDataType(v); !ok {
+			panic(errors.Errorf(
+				"Cannot override key (%s) from %s, seeded value of type %T "+
+					"cannot be converted", key, source, v,
+			))
+		}
+		switch v.(type) {
+		case int, int64:
+			integer = true
+		}
+	} else if v, contains := conf.configValues[key]; contains {
+		dataType = v.dataType
+		integer = dataType == jsonparser.Number &&
+			!strings.ContainsAny(string(v.bytes), ".eE")
+	}
+
+	configValue, err := parseOverride(value, dataType, integer)
+	if err != nil {
+		panic(errors.Wrapf(
+			err, "Cannot override key (%s) from %s", key, source,
+		))
+	}
+
+	delete(conf.seedConfig, key)
+	conf.configValues[key] = configValue
+	conf.sources[key] = source
+}
+
+func (conf *StaticConfig) keys() []string {
+	keys := make([]string, 0, len(conf.configValues)+len(conf.seedConfig))
+	for key := range conf.configValues {
+		keys = append(keys, key)
+	}
+	for key := range conf.seedConfig {
+		if _, contains := conf.configValues[key]; !contains {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// seedDataType returns the json type of a seeded value, or false if the
+// value is not a string, a boolean or a number.
+func seedDataType(value interface{}) (jsonparser.ValueType, bool) {
+	switch value.(type) {
+	case string:
+		return jsonparser.String, true
+	case bool:
+		return jsonparser.Boolean, true
+	case int, int64, float64:
+		return jsonparser.Number, true
+	default:
+		return jsonparser.NotExist, false
+	}
+}
+
+func parseOverride(
+	value string, dataType jsonparser.ValueType, integer bool,
+) (StaticConfigValue, error) {
+	if integer {
+		v, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return StaticConfigValue{}, errors.Errorf(
+				"value is not an integer: %s", value,
+			)
+		}
+		return StaticConfigValue{
+			bytes:    []byte(strconv.FormatInt(v, 10)),
+			dataType: jsonparser.Number,
+		}, nil
+	}
+
+	switch dataType {
+	case jsonparser.String:
+		return stringConfigValue(value), nil
+	case jsonparser.Boolean:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return StaticConfigValue{}, errors.Errorf(
+				"value is not a boolean: %s", value,
+			)
+		}
+		return StaticConfigValue{
+			bytes:    []byte(strconv.FormatBool(v)),
+			dataType: jsonparser.Boolean,
+		}, nil
+	case jsonparser.NotExist:
+		v, ok := parseJSONValue(value)
+		if !ok {
+			return stringConfigValue(value), nil
+		}
+		return v, nil
+	default:
+		v, ok := parseJSONValue(value)
+		if !ok || v.dataType != dataType {
+			return StaticConfigValue{}, errors.Errorf(
+				"value is not a %s: %s", dataType, value,
+			)
+		}
+		return v, nil
+	}
+}
+
+// parseJSONValue parses a json value, returning false if the value is not a
+// single json value.
+func parseJSONValue(value string) (StaticConfigValue, bool) {
+	trimmed := strings.TrimSpace(value)
+	v, dataType, _, err := jsonparser.Get([]byte(trimmed))
+	if err != nil || dataType == jsonparser.String ||
+		string(v) != trimmed {
+		return StaticConfigValue{}, false
+	}
+	return StaticConfigValue{bytes: v, dataType: dataType}, true
+}
+
+func stringConfigValue(value string) StaticConfigValue {
+	bytes, _ := json.Marshal(value)
+	return StaticConfigValue{
+		bytes:    bytes[1 : len(bytes)-1],
+		dataType: jsonparser.String,
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	zanzibar "github.com/uber/zanzibar/runtime"
+)
+
+func TestConfigEnvVarName(t *testing.T) {
+	assert.Equal(t, "ZANZIBAR_HTTP_PORT", zanzibar.ConfigEnvVarName("http.port"))
+	assert.Equal(t,
+		"ZANZIBAR_CLIENTS_GOOGLE_NOW_IP",
+		zanzibar.ConfigEnvVarName("clients.google-now.ip"),
+	)
+}
+
+func TestOverrideFromEnv(t *testing.T) {
+	closer := WriteFixture(testDir, map[string][]byte{
+		"config/production.json": mustMarshal(map[string]interface{}{
+			"http.port":             7783,
+			"clients.google-now.ip": "127.0.0.1",
+			"useDatacenter":         false,
+			"struct":                map[string]interface{}{"Field": "a"},
+		}),
+	})
+	defer closer.Close()
+	configFile := filepath.Join(testDir, "config", "production.json")
+
+	config := zanzibar.NewStaticConfigOrDie([]string{configFile}, map[string]interface{}{
+		"seed.int": int64(1),
+	})
+	config.OverrideFromEnvOrDie([]string{
+		"ZANZIBAR_HTTP_PORT=8000",
+		"ZANZIBAR_CLIENTS_GOOGLE_NOW_IP=10.0.0.1",
+		"ZANZIBAR_USEDATACENTER=true",
+		`ZANZIBAR_STRUCT={"Field": "b"}`,
+		"ZANZIBAR_SEED_INT=2",
+		"ZANZIBAR_NEW_KEY=new",
+		"ZANZIBAR_CACHE=1",
+		"ZANZIBAR_ENVIRONMENT=staging",
+		"HTTP_PORT=9000",
+	})
+
+	assert.Equal(t, int64(8000), config.MustGetInt("http.port"))
+	assert.Equal(t, "10.0.0.1", config.MustGetString("clients.google-now.ip"))
+	assert.Equal(t, true, config.MustGetBoolean("useDatacenter"))
+	assert.Equal(t, int64(2), config.MustGetInt("seed.int"))
+	assert.False(t, config.ContainsKey("new.key"))
+	assert.False(t, config.ContainsKey("cache"))
+	assert.False(t, config.ContainsKey("environment"))
+	assert.Panics(t, func() {
+		config.OverrideFromEnvOrDie([]string{"ZANZIBAR_HTTP_PORT=8000.5"})
+	})
+
+	var s struct{ Field string }
+	config.MustGetStruct("struct", &s)
+	assert.Equal(t, "b", s.Field)
+
+	assert.Equal(t, map[string]string{
+		"http.port":             "env:ZANZIBAR_HTTP_PORT",
+		"clients.google-now.ip": "env:ZANZIBAR_CLIENTS_GOOGLE_NOW_IP",
+		"useDatacenter":         "env:ZANZIBAR_USEDATACENTER",
+		"struct":                "env:ZANZIBAR_STRUCT",
+		"seed.int":              "env:ZANZIBAR_SEED_INT",
+	}, config.InspectSourcesOrDie())
+}
+
+func TestOverrideFromEnvCollisions(t *testing.T) {
+	config := zanzibar.NewStaticConfigOrDie(nil, map[string]interface{}{
+		"http.port": int64(7783),
+		"http_port": int64(7784),
+	})
+
+	config.OverrideFromEnvOrDie([]string{"ZANZIBAR_NAME=gateway"})
+	assert.Equal(t, int64(7783), config.MustGetInt("http.port"))
+
+	assert.Panics(t, func() {
+		config.OverrideFromEnvOrDie([]string{"ZANZIBAR_HTTP_PORT=8000"})
+	})
+}
+
+func TestOverrideWrongTypes(t *testing.T) {
+	config := zanzibar.NewStaticConfigOrDie(nil, map[string]interface{}{
+		"int":   int64(1),
+		"float": 1.5,
+		"bool":  true,
+	})
+
+	assert.Panics(t, func() {
+		config.OverrideFromEnvOrDie([]string{"ZANZIBAR_INT=one"})
+	})
+	assert.Panics(t, func() {
+		config.OverrideFromEnvOrDie([]string{"ZANZIBAR_INT=1.5"})
+	})
+	assert.Panics(t, func() {
+		config.OverrideFromFlagsOrDie([]string{"int=1e3"})
+	})
+	config.OverrideFromFlagsOrDie([]string{"float=2"})
+	assert.Equal(t, float64(2), config.MustGetFloat("float"))
+	assert.Panics(t, func() {
+		config.OverrideFromFlagsOrDie([]string{"bool=yes"})
+	})
+	assert.Panics(t, func() {
+		config.OverrideFromFlagsOrDie([]string{"int"})
+	})
+
+	config.Freeze()
+	assert.Panics(t, func() {
+		config.OverrideFromFlagsOrDie([]string{"int=2"})
+	})
+}
+
+func TestOverrideSeededValuesOfOtherTypes(t *testing.T) {
+	config := zanzibar.NewStaticConfigOrDie(nil, map[string]interface{}{
+		"timeout": time.Second,
+		"hosts":   []string{"a"},
+	})
+
+	assert.Panics(t, func() {
+		config.OverrideFromFlagsOrDie([]string{"timeout=2s"})
+	})
+	assert.Panics(t, func() {
+		config.OverrideFromEnvOrDie([]string{`ZANZIBAR_HOSTS=["b"]`})
+	})
+
+	timeout, ok := config.GetDuration("timeout")
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, timeout)
+	hosts, ok := config.GetStringList("hosts")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a"}, hosts)
+}
+
+func TestOverrideFromFlags(t *testing.T) {
+	config := zanzibar.NewStaticConfigOrDie(nil, map[string]interface{}{
+		"http.port": int64(7783),
+	})
+	config.OverrideFromFlagsOrDie([]string{
+		"http.port=8000", "float=1.5", "name=a=b",
+	})
+
+	assert.Equal(t, int64(8000), config.MustGetInt("http.port"))
+	assert.Equal(t, float64(1.5), config.MustGetFloat("float"))
+	assert.Equal(t, "a=b", config.MustGetString("name"))
+	assert.Equal(t, "flag:-set", config.InspectSourcesOrDie()["http.port"])
+}
+
+func TestConfigLayersOverrideFlags(t *testing.T) {
+	layers := &zanzibar.ConfigLayers{Environment: "production"}
+	flags := flag.NewFlagSet("gateway", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	layers.RegisterFlags(flags)
+
+	assert.NoError(t, flags.Parse([]string{
+		"--set", "http.port=8000", "-set", "name=gateway",
+	}))
+	assert.Error(t, flags.Parse([]string{"-set", "http.port"}))
+
+	config := layers.NewStaticConfigOrDie(nil)
+	assert.Equal(t, int64(8000), config.MustGetInt("http.port"))
+	assert.Equal(t, "gateway", config.MustGetString("name"))
+}
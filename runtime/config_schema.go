@@ -0,0 +1,146 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/pkg/errors"
+)
+
+// ConfigSchema maps required config keys to their json schema type, one of
+// "string", "integer", "number", "boolean", "object" or "array".
+type ConfigSchema map[string]string
+
+// ConfigSchemaTypes are the types a config key can be declared with.
+var ConfigSchemaTypes = []string{
+	"string", "integer", "number", "boolean", "object", "array",
+}
+
+// gatewayConfigSchema returns the config keys read when creating a gateway.
+func gatewayConfigSchema(config *StaticConfig) ConfigSchema {
+	schema := ConfigSchema{
+		"serviceName":                 "string",
+		"http.port":                   "integer",
+		"tchannel.port":               "integer",
+		"tchannel.serviceName":        "string",
+		"tchannel.processName":        "string",
+		"logger.fileName":             "string",
+		"logger.output":               "string",
+		"metrics.type":                "string",
+		"metrics.tally.service":       "string",
+		"metrics.tally.flushInterval": "integer",
+	}
+	// The datacenter selected by the config layers takes precedence
+	if !config.ContainsKey("datacenter") {
+		schema["useDatacenter"] = "boolean"
+		if config.checkType("useDatacenter", "boolean") == "" &&
+			config.MustGetBoolean("useDatacenter") {
+			schema["datacenterFile"] = "string"
+		}
+	}
+	if config.checkType("metrics.type", "string") == "" &&
+		config.MustGetString("metrics.type") == "m3" {
+		schema["env"] = "string"
+		schema["metrics.m3.hostPort"] = "string"
+	}
+	return schema
+}
+
+// ValidateSchema returns an error listing every key of the schema that is
+// missing or can not be read as its type.
+func (conf *StaticConfig) ValidateSchema(schema ConfigSchema) error {
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	for _, key := range keys {
+		if problem := conf.checkType(key, schema[key]); problem != "" {
+			problems = append(problems, fmt.Sprintf("Key (%s) %s", key, problem))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.Errorf(
+		"%d invalid config key(s):\n\t%s",
+		len(problems), strings.Join(problems, "\n\t"),
+	)
+}
+
+// checkType returns why the value of key can not be read as the schema
+// type, or an empty string if it can.
+func (conf *StaticConfig) checkType(key string, schemaType string) string {
+	if v, contains := conf.seedConfig[key]; contains {
+		var ok bool
+		switch schemaType {
+		case "string":
+			_, ok = v.(string)
+		case "integer":
+			_, ok = seedInt(v)
+		case "number":
+			_, ok = seedFloat(v)
+		case "boolean":
+			_, ok = v.(bool)
+		case "object", "array":
+			ok = v != nil
+		default:
+			return fmt.Sprintf("has unknown schema type %q", schemaType)
+		}
+		if !ok {
+			return fmt.Sprintf("is not of type %s: %v", schemaType, v)
+		}
+		return ""
+	}
+
+	v, contains := conf.configValues[key]
+	if !contains {
+		return "is missing"
+	}
+	var ok bool
+	switch schemaType {
+	case "string":
+		ok = v.dataType == jsonparser.String
+	case "integer":
+		_, err := jsonparser.ParseInt(v.bytes)
+		ok = v.dataType == jsonparser.Number && err == nil
+	case "number":
+		ok = v.dataType == jsonparser.Number
+	case "boolean":
+		ok = v.dataType == jsonparser.Boolean
+	case "object":
+		ok = v.dataType == jsonparser.Object
+	case "array":
+		ok = v.dataType == jsonparser.Array
+	default:
+		return fmt.Sprintf("has unknown schema type %q", schemaType)
+	}
+	if !ok {
+		return fmt.Sprintf("is not of type %s: %s", schemaType, string(v.bytes))
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	zanzibar "github.com/uber/zanzibar/runtime"
+)
+
+func TestValidateSchema(t *testing.T) {
+	closer := WriteFixture(testDir, map[string][]byte{
+		"config/production.json": mustMarshal(map[string]interface{}{
+			"http.port":        7783,
+			"clients.bar.ip":   "127.0.0.1",
+			"clients.bar.port": "4001",
+			"timeout":          1.5,
+			"struct":           map[string]interface{}{"Field": "a"},
+			"list":             []string{"a"},
+		}),
+	})
+	defer closer.Close()
+	configFile := filepath.Join(testDir, "config", "production.json")
+
+	config := zanzibar.NewStaticConfigOrDie([]string{configFile}, map[string]interface{}{
+		"useDatacenter": false,
+		"seed.int":      int64(1),
+		"seed.goInt":    2,
+	})
+
+	assert.NoError(t, config.ValidateSchema(zanzibar.ConfigSchema{
+		"http.port":      "integer",
+		"clients.bar.ip": "string",
+		"timeout":        "number",
+		"struct":         "object",
+		"list":           "array",
+		"useDatacenter":  "boolean",
+		"seed.int":       "integer",
+		"seed.goInt":     "integer",
+	}))
+	assert.NoError(t, config.ValidateSchema(zanzibar.ConfigSchema{
+		"seed.int":   "number",
+		"seed.goInt": "number",
+	}))
+	assert.Equal(t, int64(2), config.MustGetInt("seed.goInt"))
+	assert.Equal(t, float64(2), config.MustGetFloat("seed.goInt"))
+
+	err := config.ValidateSchema(zanzibar.ConfigSchema{
+		"http.port":        "integer",
+		"clients.bar.port": "integer",
+		"clients.baz.ip":   "string",
+		"timeout":          "integer",
+		"useDatacenter":    "string",
+	})
+	assert.EqualError(t, err, "4 invalid config key(s):\n"+
+		"\tKey (clients.bar.port) is not of type integer: 4001\n"+
+		"\tKey (clients.baz.ip) is missing\n"+
+		"\tKey (timeout) is not of type integer: 1.5\n"+
+		"\tKey (useDatacenter) is not of type string: false",
+	)
+}
+
+func TestCreateGatewayValidatesConfig(t *testing.T) {
+	config := zanzibar.NewStaticConfigOrDie(nil, map[string]interface{}{
+		"serviceName":   "test-gateway",
+		"http.port":     int64(0),
+		"useDatacenter": true,
+		"metrics.type":  "m3",
+	})
+
+	_, err := zanzibar.CreateGateway(config, &zanzibar.Options{
+		ConfigSchema: zanzibar.ConfigSchema{
+			"clients.bar.ip": "string",
+		},
+	})
+	if !assert.Error(t, err) {
+		return
+	}
+	assert.Contains(t, err.Error(), "11 invalid config key(s)")
+	for _, key := range []string{
+		"clients.bar.ip", "datacenterFile", "env", "metrics.m3.hostPort",
+		"tchannel.port", "logger.output",
+	} {
+		assert.Contains(t, err.Error(), "Key ("+key+") is missing")
+	}
+}
+
+func TestCreateGatewayWithLayerDatacenter(t *testing.T) {
+	config := zanzibar.NewStaticConfigOrDie(nil, map[string]interface{}{
+		"serviceName":  "test-gateway",
+		"http.port":    int64(0),
+		"datacenter":   "sjc1",
+		"metrics.type": "tally",
+	})
+
+	_, err := zanzibar.CreateGateway(config, nil)
+	if !assert.Error(t, err) {
+		return
+	}
+	assert.NotContains(t, err.Error(), "useDatacenter")
+	assert.NotContains(t, err.Error(), "datacenterFile")
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// RecordedClientCall is a downstream request/response pair recorded by a
+// client, in the client stub format of the endpoint test fixtures.
+type RecordedClientCall struct {
+	ClientID         string            `json:"clientId"`
+	ClientMethod     string            `json:"clientMethod"`
+	ClientRequest    interface{}       `json:"clientRequest"`
+	ClientReqHeaders map[string]string `json:"clientReqHeaders"`
+	ClientResponse   interface{}       `json:"clientResponse,omitempty"`
+	ClientResHeaders map[string]string `json:"clientResHeaders"`
+	// ClientException is the exception returned by a tchannel client.
+	ClientException *RecordedClientException `json:"clientException,omitempty"`
+	// ClientStatusCode is the status code of a http client response.
+	ClientStatusCode int `json:"clientStatusCode,omitempty"`
+	// HTTPMethod and HTTPPath are the route of a http client request.
+	HTTPMethod string `json:"httpMethod,omitempty"`
+	HTTPPath   string `json:"httpPath,omitempty"`
+}
+
+// RecordedClientException is an exception recorded by a tchannel client.
+type RecordedClientException struct {
+	Name string      `json:"name"`
+	Body interface{} `json:"body"`
+}
+
+// MaxRecordedClientCalls is the number of calls recorded per client method,
+// the later calls of the method are not recorded.
+const MaxRecordedClientCalls = 100
+
+// FixtureRecorder records client calls to a fixture file per client
+// method. The file of a method holds the first MaxRecordedClientCalls
+// calls recorded by the process in order, it is rewritten after every
+// recorded call.
+type FixtureRecorder struct {
+	dir string
+
+	mutex sync.Mutex
+	calls map[string][]*RecordedClientCall
+}
+
+// NewFixtureRecorder returns a recorder of client calls to the fixture
+// files in dir.
+func NewFixtureRecorder(dir string) *FixtureRecorder {
+	return &FixtureRecorder{
+		dir:   dir,
+		calls: map[string][]*RecordedClientCall{},
+	}
+}
+
+// RecordedFixturePath returns the fixture file of a client method.
+func RecordedFixturePath(dir string, clientID string, method string) string {
+	return filepath.Join(dir, clientID, method+".json")
+}
+
+// Record adds a call to the fixture file of its method, unless
+// MaxRecordedClientCalls calls of the method are already recorded.
+func (r *FixtureRecorder) Record(call *RecordedClientCall) error {
+	path := RecordedFixturePath(r.dir, call.ClientID, call.ClientMethod)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.calls[path]) >= MaxRecordedClientCalls {
+		return nil
+	}
+	r.calls[path] = append(r.calls[path], call)
+	return r.write(path, call)
+}
+
+// Update changes a recorded call and rewrites the fixture file of its
+// method, update is called with the calls locked. The file is not
+// rewritten if the call was not recorded.
+func (r *FixtureRecorder) Update(
+	call *RecordedClientCall, update func(call *RecordedClientCall),
+) error {
+	path := RecordedFixturePath(r.dir, call.ClientID, call.ClientMethod)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	update(call)
+	for _, recorded := range r.calls[path] {
+		if recorded == call {
+			return r.write(path, call)
+		}
+	}
+	return nil
+}
+
+// write rewrites the fixture file of the method of a call.
+func (r *FixtureRecorder) write(path string, call *RecordedClientCall) error {
+	bytes, err := json.MarshalIndent(r.calls[path], "", "\t")
+	if err != nil {
+		return errors.Wrapf(err,
+			"Could not serialize recorded calls for client: %s", call.ClientID,
+		)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return errors.Wrapf(err,
+			"Could not create fixture dir for client: %s", call.ClientID,
+		)
+	}
+	if err := ioutil.WriteFile(path, bytes, 0644); err != nil {
+		return errors.Wrapf(err,
+			"Could not write fixture file for client: %s", call.ClientID,
+		)
+	}
+	return nil
+}
+
+// ReadRecordedClientCalls reads the calls of a fixture file.
+func ReadRecordedClientCalls(path string) ([]*RecordedClientCall, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not read fixture file %q", path)
+	}
+	var calls []*RecordedClientCall
+	if err := json.Unmarshal(bytes, &calls); err != nil {
+		return nil, errors.Wrapf(err, "Could not parse fixture file %q", path)
+	}
+	return calls, nil
+}
+
+// recordedJSON parses a json body into a generic value, it returns nil if
+// the body is empty or not json.
+func recordedJSON(rawBody []byte) interface{} {
+	var value interface{}
+	if len(rawBody) == 0 || json.Unmarshal(rawBody, &value) != nil {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,340 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/zanzibar/examples/example-gateway/build/clients"
+	"github.com/uber/zanzibar/examples/example-gateway/build/clients/baz"
+	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints"
+	clientsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/bar/bar"
+	clientsBazBaz "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/baz/baz"
+	zanzibar "github.com/uber/zanzibar/runtime"
+	"github.com/uber/zanzibar/test/lib/bench_gateway"
+)
+
+func createRecordingGateway(
+	t *testing.T, recordDir string,
+) *benchGateway.BenchGateway {
+	config := map[string]interface{}{
+		"clients.baz.serviceName": "baz",
+	}
+	if recordDir != "" {
+		config["clients.bar.recordDir"] = recordDir
+		config["clients.baz.recordDir"] = recordDir
+	}
+	gateway, err := benchGateway.CreateGateway(
+		config,
+		defaultTestOptions,
+		clients.CreateClients,
+		endpoints.Register,
+	)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return gateway.(*benchGateway.BenchGateway)
+}
+
+func TestRecordCallsUpToTheLimit(t *testing.T) {
+	recordDir, err := ioutil.TempDir("", "zanzibar-recordings")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(recordDir) }()
+
+	recorder := zanzibar.NewFixtureRecorder(recordDir)
+	var last *zanzibar.RecordedClientCall
+	for i := 0; i <= zanzibar.MaxRecordedClientCalls; i++ {
+		last = &zanzibar.RecordedClientCall{
+			ClientID:         "bar",
+			ClientMethod:     "normal",
+			ClientStatusCode: 200,
+		}
+		assert.NoError(t, recorder.Record(last))
+	}
+	assert.NoError(t, recorder.Update(last, func(
+		call *zanzibar.RecordedClientCall,
+	) {
+		call.ClientStatusCode = 500
+	}))
+
+	calls, err := zanzibar.ReadRecordedClientCalls(
+		zanzibar.RecordedFixturePath(recordDir, "bar", "normal"),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, calls, zanzibar.MaxRecordedClientCalls)
+	for _, call := range calls {
+		assert.Equal(t, 200, call.ClientStatusCode)
+	}
+}
+
+func TestRecordAndReplayHTTPClientCalls(t *testing.T) {
+	recordDir, err := ioutil.TempDir("", "zanzibar-recordings")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(recordDir) }()
+
+	args := &clientsBarBar.Bar_Normal_Args{
+		Request: &clientsBarBar.BarRequest{
+			StringField: "foo",
+			BoolField:   true,
+		},
+	}
+	responses := []string{
+		`{"stringField":"foo","intWithRange":0,"intWithoutRange":1,` +
+			`"mapIntWithRange":{},"mapIntWithoutRange":{}}`,
+		`{"stringField":"bar","intWithRange":2,"intWithoutRange":3,` +
+			`"mapIntWithRange":{},"mapIntWithoutRange":{}}`,
+	}
+
+	gateway := createRecordingGateway(t, recordDir)
+	counter := 0
+	gateway.HTTPBackends()["bar"].HandleFunc(
+		"POST", "/bar-path",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Example-Header", "bar")
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(responses[counter]))
+			counter++
+		},
+	)
+
+	bar := gateway.ActualGateway.Clients.(*clients.Clients).Bar
+	var recorded []*clientsBarBar.BarResponse
+	for range responses {
+		res, _, err := bar.Normal(
+			context.Background(), map[string]string{"x-uuid": "a"}, args,
+		)
+		if !assert.NoError(t, err) {
+			return
+		}
+		recorded = append(recorded, res)
+	}
+	gateway.Close()
+
+	calls, err := zanzibar.ReadRecordedClientCalls(
+		zanzibar.RecordedFixturePath(recordDir, "bar", "normal"),
+	)
+	if !assert.NoError(t, err) || !assert.Len(t, calls, 2) {
+		return
+	}
+	assert.Equal(t, "bar", calls[0].ClientID)
+	assert.Equal(t, "normal", calls[0].ClientMethod)
+	assert.Equal(t, "POST", calls[0].HTTPMethod)
+	assert.Equal(t, "/bar-path", calls[0].HTTPPath)
+	assert.Equal(t, 200, calls[0].ClientStatusCode)
+	assert.Equal(t, map[string]string{"x-uuid": "a"}, calls[0].ClientReqHeaders)
+	assert.Equal(t,
+		map[string]string{"Example-Header": "bar"}, calls[0].ClientResHeaders,
+	)
+	assert.Equal(t, map[string]interface{}{
+		"request": map[string]interface{}{
+			"stringField": "foo",
+			"boolField":   true,
+		},
+	}, calls[0].ClientRequest)
+
+	gateway = createRecordingGateway(t, "")
+	defer gateway.Close()
+	gateway.HTTPBackends()["bar"].ReplayCalls(calls)
+
+	bar = gateway.ActualGateway.Clients.(*clients.Clients).Bar
+	for _, expected := range recorded {
+		res, resHeaders, err := bar.Normal(context.Background(), nil, args)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, expected, res)
+		assert.Equal(t, "bar", resHeaders["Example-Header"])
+	}
+
+	_, _, err = bar.Normal(context.Background(), nil, args)
+	assert.Error(t, err, "expected no recorded call left")
+}
+
+func TestRecordUnreadHTTPResponses(t *testing.T) {
+	recordDir, err := ioutil.TempDir("", "zanzibar-recordings")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(recordDir) }()
+
+	gateway := createRecordingGateway(t, "")
+	defer gateway.Close()
+
+	backend := gateway.HTTPBackends()["bar"]
+	backend.HandleFunc(
+		"POST", "/no-content",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(204)
+		},
+	)
+	backend.HandleFunc(
+		"POST", "/error",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(500)
+			_, _ = w.Write([]byte(`{"error":"boom"}`))
+		},
+	)
+	backend.HandleFunc(
+		"POST", "/thrift",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", zanzibar.ThriftBinaryContentType)
+			w.WriteHeader(200)
+			_ = zanzibar.WriteStruct(w, &clientsBarBar.BarResponse{
+				StringField:        "foo",
+				MapIntWithRange:    map[string]int32{},
+				MapIntWithoutRange: map[string]int32{},
+			})
+		},
+	)
+
+	opts := zanzibar.NewHTTPClientOptions(gateway.ActualGateway.Config, "bar")
+	opts.RecordDir = recordDir
+	baseURL := "http://" + backend.RealAddr
+	client := zanzibar.NewHTTPClientWithOptions(
+		gateway.ActualGateway, baseURL, opts,
+	)
+
+	for _, path := range []string{"/no-content", "/error"} {
+		req := zanzibar.NewClientHTTPRequest("bar", "unread", client)
+		if !assert.NoError(t, req.WriteJSON("POST", baseURL+path, nil, nil)) {
+			return
+		}
+		if _, err := req.Do(context.Background()); !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	// Thrift bodies are recorded as json once they are decoded.
+	req := zanzibar.NewClientHTTPRequest("bar", "unread", client)
+	if !assert.NoError(t, req.WriteJSON("POST", baseURL+"/thrift", nil, nil)) {
+		return
+	}
+	res, err := req.Do(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+	var response clientsBarBar.BarResponse
+	if !assert.NoError(t, res.ReadAndUnmarshalBody(&response)) {
+		return
+	}
+	assert.Equal(t, "foo", response.StringField)
+
+	calls, err := zanzibar.ReadRecordedClientCalls(
+		zanzibar.RecordedFixturePath(recordDir, "bar", "unread"),
+	)
+	if !assert.NoError(t, err) || !assert.Len(t, calls, 3) {
+		return
+	}
+	assert.Equal(t, 204, calls[0].ClientStatusCode)
+	assert.Nil(t, calls[0].ClientResponse)
+	assert.Equal(t, 500, calls[1].ClientStatusCode)
+	assert.Equal(t,
+		map[string]interface{}{"error": "boom"}, calls[1].ClientResponse,
+	)
+	assert.Equal(t, "foo",
+		calls[2].ClientResponse.(map[string]interface{})["stringField"],
+	)
+}
+
+func TestRecordAndReplayTChannelClientCalls(t *testing.T) {
+	recordDir, err := ioutil.TempDir("", "zanzibar-recordings")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(recordDir) }()
+
+	args := &clientsBazBaz.SimpleService_Call_Args{
+		Arg: &clientsBazBaz.BazRequest{B1: true, S2: "hello", I3: 42},
+	}
+
+	gateway := createRecordingGateway(t, recordDir)
+	counter := 0
+	gateway.TChannelBackends()["baz"].Register(
+		"SimpleService", "Call",
+		bazClient.NewSimpleServiceCallHandler(func(
+			ctx context.Context,
+			reqHeaders map[string]string,
+			args *clientsBazBaz.SimpleService_Call_Args,
+		) (map[string]string, error) {
+			counter++
+			if counter > 1 {
+				return nil, &clientsBazBaz.AuthErr{Message: "denied"}
+			}
+			return map[string]string{"x-baz": "ok"}, nil
+		}),
+	)
+
+	baz := gateway.ActualGateway.Clients.(*clients.Clients).Baz
+	_, err = baz.Call(context.Background(), map[string]string{"x-uuid": "a"}, args)
+	assert.NoError(t, err)
+	_, err = baz.Call(context.Background(), nil, args)
+	assert.Error(t, err)
+	gateway.Close()
+
+	calls, err := zanzibar.ReadRecordedClientCalls(
+		zanzibar.RecordedFixturePath(recordDir, "baz", "Call"),
+	)
+	if !assert.NoError(t, err) || !assert.Len(t, calls, 2) {
+		return
+	}
+	assert.Equal(t, "baz", calls[0].ClientID)
+	assert.Equal(t, map[string]string{"x-uuid": "a"}, calls[0].ClientReqHeaders)
+	assert.Equal(t, map[string]string{"x-baz": "ok"}, calls[0].ClientResHeaders)
+	assert.Equal(t, map[string]interface{}{
+		"arg": map[string]interface{}{
+			"b1": true,
+			"s2": "hello",
+			"i3": float64(42),
+		},
+	}, calls[0].ClientRequest)
+	assert.Nil(t, calls[0].ClientException)
+	assert.Equal(t, &zanzibar.RecordedClientException{
+		Name: "authErr",
+		Body: map[string]interface{}{"message": "denied"},
+	}, calls[1].ClientException)
+
+	gateway = createRecordingGateway(t, "")
+	defer gateway.Close()
+	gateway.TChannelBackends()["baz"].ReplayCalls(
+		"SimpleService", "Call", calls,
+		func() zanzibar.RWTStruct {
+			return &clientsBazBaz.SimpleService_Call_Result{}
+		},
+	)
+
+	baz = gateway.ActualGateway.Clients.(*clients.Clients).Baz
+	resHeaders, err := baz.Call(context.Background(), nil, args)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resHeaders["x-baz"])
+	_, err = baz.Call(context.Background(), nil, args)
+	assert.Equal(t, &clientsBazBaz.AuthErr{Message: "denied"}, err)
+}
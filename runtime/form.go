@@ -0,0 +1,297 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FormFile is a binary field sent as a multipart file part.
+type FormFile struct {
+	Name    string
+	Content []byte
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+type formField struct {
+	name  string
+	index int
+}
+
+// formFields returns the json-tagged fields of a thriftrw struct type.
+func formFields(t reflect.Type) []formField {
+	fields := []formField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, formField{name: name, index: i})
+	}
+	return fields
+}
+
+func structValue(v interface{}) (reflect.Value, error) {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return value, errors.New("form body must not be nil")
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return value, errors.Errorf(
+			"form body must be a struct, got %s", value.Type(),
+		)
+	}
+	return value, nil
+}
+
+func isBinary(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// isSet returns true for the map[T]struct{} types thriftrw generates for
+// sets of hashable values.
+func isSet(t reflect.Type) bool {
+	return t.Kind() == reflect.Map &&
+		t.Elem().Kind() == reflect.Struct && t.Elem().NumField() == 0
+}
+
+// MarshalForm encodes the fields of a thriftrw struct as form values keyed
+// by their json names. Binary fields are returned as file parts, lists and
+// sets are repeated values, nested structs and maps are encoded as json
+// strings.
+func MarshalForm(body interface{}) (url.Values, []FormFile, error) {
+	value, err := structValue(body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := url.Values{}
+	files := []FormFile{}
+	for _, field := range formFields(value.Type()) {
+		fieldValue := value.Field(field.index)
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+
+		if isBinary(fieldValue.Type()) {
+			if !fieldValue.IsNil() {
+				files = append(files, FormFile{
+					Name:    field.name,
+					Content: fieldValue.Bytes(),
+				})
+			}
+			continue
+		}
+
+		if fieldValue.Kind() == reflect.Slice {
+			if fieldValue.IsNil() {
+				continue
+			}
+			for i := 0; i < fieldValue.Len(); i++ {
+				str, err := formString(fieldValue.Index(i))
+				if err != nil {
+					return nil, nil, errors.Wrapf(
+						err, "could not encode form field %q", field.name,
+					)
+				}
+				values.Add(field.name, str)
+			}
+			continue
+		}
+
+		if fieldValue.Kind() == reflect.Map && fieldValue.IsNil() {
+			continue
+		}
+
+		if isSet(fieldValue.Type()) {
+			strs := make([]string, 0, fieldValue.Len())
+			for _, key := range fieldValue.MapKeys() {
+				str, err := formString(key)
+				if err != nil {
+					return nil, nil, errors.Wrapf(
+						err, "could not encode form field %q", field.name,
+					)
+				}
+				strs = append(strs, str)
+			}
+			sort.Strings(strs)
+			for _, str := range strs {
+				values.Add(field.name, str)
+			}
+			continue
+		}
+
+		str, err := formString(fieldValue)
+		if err != nil {
+			return nil, nil, errors.Wrapf(
+				err, "could not encode form field %q", field.name,
+			)
+		}
+		values.Set(field.name, str)
+	}
+	return values, files, nil
+}
+
+// formString encodes a single value, enums use their thrift names.
+func formString(value reflect.Value) (string, error) {
+	if stringer, ok := value.Interface().(fmt.Stringer); ok &&
+		value.Kind() != reflect.Struct {
+		return stringer.String(), nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64), nil
+	}
+
+	bytes, err := json.Marshal(value.Interface())
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// UnmarshalForm decodes form values and file parts into a thriftrw struct.
+// Values are coerced to the field types and passed through the struct's
+// json decoder, so required fields and enums are validated as they are
+// for json bodies.
+func UnmarshalForm(
+	values url.Values, files map[string][]byte, body json.Unmarshaler,
+) error {
+	value, err := structValue(body)
+	if err != nil {
+		return err
+	}
+
+	obj := make(map[string]interface{})
+	for _, field := range formFields(value.Type()) {
+		fieldType := value.Type().Field(field.index).Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if isBinary(fieldType) {
+			if content, ok := files[field.name]; ok {
+				obj[field.name] = content
+			} else if _, ok := values[field.name]; ok {
+				obj[field.name] = []byte(values.Get(field.name))
+			}
+			continue
+		}
+
+		strs, ok := values[field.name]
+		if !ok || len(strs) == 0 {
+			continue
+		}
+
+		if isSet(fieldType) {
+			set := make(map[string]struct{}, len(strs))
+			for _, str := range strs {
+				if _, err := formValue(fieldType.Key(), str); err != nil {
+					return errors.Wrapf(
+						err, "invalid form field %q", field.name,
+					)
+				}
+				set[str] = struct{}{}
+			}
+			obj[field.name] = set
+			continue
+		}
+
+		if fieldType.Kind() == reflect.Slice {
+			list := make([]interface{}, len(strs))
+			for i, str := range strs {
+				list[i], err = formValue(fieldType.Elem(), str)
+				if err != nil {
+					return errors.Wrapf(
+						err, "invalid form field %q", field.name,
+					)
+				}
+			}
+			obj[field.name] = list
+			continue
+		}
+
+		obj[field.name], err = formValue(fieldType, strs[0])
+		if err != nil {
+			return errors.Wrapf(err, "invalid form field %q", field.name)
+		}
+	}
+
+	bytes, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return body.UnmarshalJSON(bytes)
+}
+
+// formValue coerces a form string into a json value of the given type.
+func formValue(t reflect.Type, str string) (interface{}, error) {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) &&
+		t.Kind() != reflect.Struct {
+		return str, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return str, nil
+	case reflect.Bool:
+		return strconv.ParseBool(str)
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(str, 10, t.Bits())
+	case reflect.Float64:
+		return strconv.ParseFloat(str, 64)
+	}
+
+	if !json.Valid([]byte(str)) {
+		return nil, errors.Errorf("expected json encoded %s", t)
+	}
+	return json.RawMessage(str), nil
+}
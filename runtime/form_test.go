@@ -0,0 +1,286 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/zanzibar/examples/example-gateway/build/clients"
+	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints"
+	zanzibar "github.com/uber/zanzibar/runtime"
+	"github.com/uber/zanzibar/test/lib/bench_gateway"
+)
+
+type formColor int32
+
+const (
+	formColorRed  formColor = 0
+	formColorBlue formColor = 1
+)
+
+func (c formColor) String() string {
+	if c == formColorBlue {
+		return "BLUE"
+	}
+	return "RED"
+}
+
+func (c *formColor) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "RED":
+		*c = formColorRed
+	case "BLUE":
+		*c = formColorBlue
+	default:
+		return errors.Errorf("unknown color %q", text)
+	}
+	return nil
+}
+
+func (c *formColor) UnmarshalJSON(text []byte) error {
+	var name string
+	if err := json.Unmarshal(text, &name); err != nil {
+		return err
+	}
+	return c.UnmarshalText([]byte(name))
+}
+
+type formBody struct {
+	Name    string             `json:"name,required"`
+	Count   *int32             `json:"count,omitempty"`
+	Enabled bool               `json:"enabled,required"`
+	Ratio   *float64           `json:"ratio,omitempty"`
+	Tags    []string           `json:"tags,omitempty"`
+	Color   *formColor         `json:"color,omitempty"`
+	Photo   []byte             `json:"photo,omitempty"`
+	Extra   map[string]string  `json:"extra,omitempty"`
+	IDs     map[int32]struct{} `json:"ids,omitempty"`
+}
+
+func (b *formBody) UnmarshalJSON(text []byte) error {
+	type plain formBody
+	return json.Unmarshal(text, (*plain)(b))
+}
+
+func newFormBody() *formBody {
+	count := int32(3)
+	ratio := 0.5
+	color := formColorBlue
+	return &formBody{
+		Name:    "foo bar",
+		Count:   &count,
+		Enabled: true,
+		Ratio:   &ratio,
+		Tags:    []string{"a", "b"},
+		Color:   &color,
+		Photo:   []byte{0, 1, 2, 255},
+		Extra:   map[string]string{"k": "v"},
+		IDs:     map[int32]struct{}{10: {}, 2: {}},
+	}
+}
+
+func TestMarshalForm(t *testing.T) {
+	values, files, err := zanzibar.MarshalForm(newFormBody())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, url.Values{
+		"name":    {"foo bar"},
+		"count":   {"3"},
+		"enabled": {"true"},
+		"ratio":   {"0.5"},
+		"tags":    {"a", "b"},
+		"color":   {"BLUE"},
+		"extra":   {`{"k":"v"}`},
+		"ids":     {"10", "2"},
+	}, values)
+	assert.Equal(t, []zanzibar.FormFile{
+		{Name: "photo", Content: []byte{0, 1, 2, 255}},
+	}, files)
+
+	_, _, err = zanzibar.MarshalForm("foo")
+	assert.EqualError(t, err, "form body must be a struct, got string")
+}
+
+func TestUnmarshalForm(t *testing.T) {
+	values, files, err := zanzibar.MarshalForm(newFormBody())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var body formBody
+	err = zanzibar.UnmarshalForm(
+		values, map[string][]byte{files[0].Name: files[0].Content}, &body,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, newFormBody(), &body)
+
+	body = formBody{}
+	err = zanzibar.UnmarshalForm(url.Values{"count": {"abc"}}, nil, &body)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid form field "count"`)
+
+	body = formBody{}
+	err = zanzibar.UnmarshalForm(url.Values{"extra": {"{"}}, nil, &body)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid form field "extra"`)
+
+	body = formBody{}
+	err = zanzibar.UnmarshalForm(url.Values{"ids": {"1", "x"}}, nil, &body)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid form field "ids"`)
+}
+
+func TestReadAndUnmarshalForm(t *testing.T) {
+	gateway, err := benchGateway.CreateGateway(
+		defaultTestConfig,
+		defaultTestOptions,
+		clients.CreateClients,
+		endpoints.Register,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gateway.Close()
+
+	bgateway := gateway.(*benchGateway.BenchGateway)
+	var body formBody
+	endpoint := zanzibar.NewRouterEndpoint(
+		bgateway.ActualGateway,
+		"foo",
+		"foo",
+		func(
+			ctx context.Context,
+			req *zanzibar.ServerHTTPRequest,
+			res *zanzibar.ServerHTTPResponse,
+		) {
+		},
+	)
+
+	values, files, err := zanzibar.MarshalForm(newFormBody())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for name, strs := range values {
+		for _, str := range strs {
+			assert.NoError(t, writer.WriteField(name, str))
+		}
+	}
+	part, err := writer.CreateFormFile(files[0].Name, "photo.jpg")
+	assert.NoError(t, err)
+	_, err = part.Write(files[0].Content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	httpReq := httptest.NewRequest("POST", "/foo", &buf)
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+	req := zanzibar.NewServerHTTPRequest(
+		httptest.NewRecorder(), httpReq, nil, endpoint,
+	)
+	assert.True(t, req.ReadAndUnmarshalForm(&body))
+	assert.Equal(t, newFormBody(), &body)
+
+	body = formBody{}
+	httpReq = httptest.NewRequest(
+		"POST", "/foo", strings.NewReader("name=foo&color=GREEN"),
+	)
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = zanzibar.NewServerHTTPRequest(
+		httptest.NewRecorder(), httpReq, nil, endpoint,
+	)
+	assert.False(t, req.ReadAndUnmarshalForm(&body))
+}
+
+func TestClientWriteMultipart(t *testing.T) {
+	gateway, err := benchGateway.CreateGateway(
+		defaultTestConfig,
+		defaultTestOptions,
+		clients.CreateClients,
+		endpoints.Register,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gateway.Close()
+
+	var (
+		contentType string
+		photo       []byte
+		name        string
+	)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			contentType = r.Header.Get("Content-Type")
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				w.WriteHeader(400)
+				return
+			}
+			name = r.PostForm.Get("name")
+			file, _, err := r.FormFile("photo")
+			if err == nil {
+				photo, _ = ioutil.ReadAll(file)
+			}
+			w.WriteHeader(200)
+		},
+	))
+	defer server.Close()
+
+	bgateway := gateway.(*benchGateway.BenchGateway)
+	client := zanzibar.NewHTTPClient(bgateway.ActualGateway, server.URL)
+
+	req := zanzibar.NewClientHTTPRequest("clientID", "DoStuff", client)
+	err = req.WriteMultipart("POST", server.URL+"/foo", nil, newFormBody())
+	if !assert.NoError(t, err) {
+		return
+	}
+	res, err := req.Do(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 200, res.StatusCode)
+	assert.True(t, strings.HasPrefix(contentType, "multipart/form-data"))
+	assert.Equal(t, "foo bar", name)
+	assert.Equal(t, []byte{0, 1, 2, 255}, photo)
+
+	req = zanzibar.NewClientHTTPRequest("clientID", "DoStuff", client)
+	err = req.WriteForm("POST", server.URL+"/foo", nil, "foo")
+	assert.EqualError(t, err,
+		"Could not serialize form for client: clientID: "+
+			"form body must be a struct, got string",
+	)
+}
@@ -54,6 +54,9 @@ type Clients interface {
 type Options struct {
 	MetricsBackend tally.CachedStatsReporter
 	LogWriter      zapcore.WriteSyncer
+	// ConfigSchema declares the config keys required by the modules of the
+	// gateway, they are validated with the keys of the gateway itself.
+	ConfigSchema ConfigSchema
 }
 
 // Gateway type
@@ -99,6 +102,18 @@ func CreateGateway(
 		logWriter = opts.LogWriter
 	}
 
+	schema := gatewayConfigSchema(config)
+	if opts != nil {
+		for key, schemaType := range opts.ConfigSchema {
+			if _, ok := schema[key]; !ok {
+				schema[key] = schemaType
+			}
+		}
+	}
+	if err := config.ValidateSchema(schema); err != nil {
+		return nil, err
+	}
+
 	gateway := &Gateway{
 		HTTPPort:     int32(config.MustGetInt("http.port")),
 		TChannelPort: int32(config.MustGetInt("tchannel.port")),
@@ -242,6 +257,25 @@ func (gateway *Gateway) handleHealthRequest(
 	res.WriteJSONBytes(200, nil, bytes)
 }
 
+// RegisterOpenAPI serves the OpenAPI document of the gateway endpoints at
+// "/openapi.json" if "http.openapi.serve" is enabled.
+func (gateway *Gateway) RegisterOpenAPI(document []byte) {
+	const key = "http.openapi.serve"
+	if !gateway.Config.ContainsKey(key) || !gateway.Config.MustGetBoolean(key) {
+		return
+	}
+
+	gateway.HTTPRouter.Register("GET", "/openapi.json", NewRouterEndpoint(
+		gateway, "openapi", "openapi", func(
+			ctx context.Context,
+			req *ServerHTTPRequest,
+			res *ServerHTTPResponse,
+		) {
+			res.WriteJSONBytes(200, nil, document)
+		},
+	))
+}
+
 // Close the http server
 func (gateway *Gateway) Close() {
 	gateway.metricsBackend.Flush()
@@ -264,12 +298,23 @@ func (gateway *Gateway) InspectOrDie() map[string]interface{} {
 	return gateway.Config.InspectOrDie()
 }
 
+// InspectConfigSourcesOrDie inspects where the config values of this gateway
+// come from
+func (gateway *Gateway) InspectConfigSourcesOrDie() map[string]string {
+	return gateway.Config.InspectSourcesOrDie()
+}
+
 // Wait for gateway to close the server
 func (gateway *Gateway) Wait() {
 	gateway.WaitGroup.Wait()
 }
 
 func (gateway *Gateway) setupConfig(config *StaticConfig) {
+	// The datacenter selected by the config layers takes precedence
+	if config.ContainsKey("datacenter") {
+		return
+	}
+
 	useDC := config.MustGetBoolean("useDatacenter")
 
 	if useDC {
@@ -418,18 +463,26 @@ func (gateway *Gateway) setupHTTPServer() error {
 	if err != nil {
 		return errors.Wrap(err, "error finding the best IP")
 	}
+
+	tlsConfig, err := newServerTLSConfig(gateway.Config)
+	if err != nil {
+		return errors.Wrap(err, "error setting up TLS")
+	}
+
 	gateway.httpServer = &HTTPServer{
 		Server: &http.Server{
-			Addr:    listenIP.String() + ":" + strconv.FormatInt(int64(gateway.HTTPPort), 10),
-			Handler: gateway.HTTPRouter,
+			Addr:      listenIP.String() + ":" + strconv.FormatInt(int64(gateway.HTTPPort), 10),
+			Handler:   gateway.HTTPRouter,
+			TLSConfig: tlsConfig,
 		},
 		Logger: gateway.Logger,
 	}
 
 	gateway.localHTTPServer = &HTTPServer{
 		Server: &http.Server{
-			Addr:    "127.0.0.1:" + strconv.FormatInt(int64(gateway.HTTPPort), 10),
-			Handler: gateway.HTTPRouter,
+			Addr:      "127.0.0.1:" + strconv.FormatInt(int64(gateway.HTTPPort), 10),
+			Handler:   gateway.HTTPRouter,
+			TLSConfig: tlsConfig,
 		},
 		Logger: gateway.Logger,
 	}
@@ -0,0 +1,96 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package zanzibar_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/zanzibar/examples/example-gateway/build/clients"
+	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints"
+	"github.com/uber/zanzibar/examples/example-gateway/build/sdk/bar"
+	"github.com/uber/zanzibar/test/lib/bench_gateway"
+
+	endpointsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/bar/bar"
+)
+
+func TestGatewaySDK(t *testing.T) {
+	gateway, err := benchGateway.CreateGateway(
+		defaultTestConfig,
+		defaultTestOptions,
+		clients.CreateClients,
+		endpoints.Register,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gateway.Close()
+
+	bgateway := gateway.(*benchGateway.BenchGateway)
+	handleBarJSON(bgateway)
+
+	sdk := barSDK.NewClient(
+		"http://"+bgateway.ActualGateway.RealHTTPAddr, nil,
+	)
+	response, _, err := sdk.Normal(
+		context.Background(), nil, &endpointsBarBar.Bar_Normal_Args{
+			Request: &endpointsBarBar.BarRequest{StringField: "foo"},
+		},
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "foo", response.StringField)
+	assert.Equal(t, int32(1), response.IntWithRange)
+}
+
+func TestGatewaySDKException(t *testing.T) {
+	gateway, err := benchGateway.CreateGateway(
+		defaultTestConfig,
+		defaultTestOptions,
+		clients.CreateClients,
+		endpoints.Register,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gateway.Close()
+
+	bgateway := gateway.(*benchGateway.BenchGateway)
+	bgateway.HTTPBackends()["bar"].HandleFunc(
+		"POST", "/bar-path",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(403)
+			_, _ = w.Write([]byte(`{"stringField":"bar"}`))
+		},
+	)
+
+	sdk := barSDK.NewClient(
+		"http://"+bgateway.ActualGateway.RealHTTPAddr, nil,
+	)
+	_, _, err = sdk.Normal(
+		context.Background(), nil, &endpointsBarBar.Bar_Normal_Args{
+			Request: &endpointsBarBar.BarRequest{StringField: "foo"},
+		},
+	)
+	assert.IsType(t, &endpointsBarBar.BarException{}, err)
+}
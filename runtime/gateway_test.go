@@ -0,0 +1,89 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/zanzibar/examples/example-gateway/build/clients"
+	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints"
+	"github.com/uber/zanzibar/test/lib/bench_gateway"
+)
+
+func TestOpenAPIRoute(t *testing.T) {
+	config := map[string]interface{}{
+		"http.openapi.serve": true,
+	}
+	for k, v := range defaultTestConfig {
+		config[k] = v
+	}
+	gateway, err := benchGateway.CreateGateway(
+		config,
+		defaultTestOptions,
+		clients.CreateClients,
+		endpoints.Register,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gateway.Close()
+
+	res, err := gateway.MakeRequest("GET", "/openapi.json", nil, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = res.Body.Close() }()
+	assert.Equal(t, 200, res.StatusCode)
+
+	bytes, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+	var doc struct {
+		OpenAPI string                 `json:"openapi"`
+		Paths   map[string]interface{} `json:"paths"`
+	}
+	if assert.NoError(t, json.Unmarshal(bytes, &doc)) {
+		assert.Equal(t, "3.0.0", doc.OpenAPI)
+		assert.Contains(t, doc.Paths, "/bar/bar-path")
+	}
+}
+
+func TestOpenAPIRouteDisabled(t *testing.T) {
+	gateway, err := benchGateway.CreateGateway(
+		defaultTestConfig,
+		defaultTestOptions,
+		clients.CreateClients,
+		endpoints.Register,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gateway.Close()
+
+	res, err := gateway.MakeRequest("GET", "/openapi.json", nil, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = res.Body.Close() }()
+	assert.Equal(t, 404, res.StatusCode)
+}
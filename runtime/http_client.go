@@ -20,8 +20,28 @@
 
 package zanzibar
 
-import "net/http"
-import "go.uber.org/zap"
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+// defaultHTTPClientPrefix is the config prefix of the transport options
+// shared by every http client.
+const defaultHTTPClientPrefix = "http.clients."
+
+// proxyFromEnvironment is the proxy option value that reads the proxy from
+// the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+const proxyFromEnvironment = "environment"
 
 // HTTPClient defines a http client.
 type HTTPClient struct {
@@ -30,23 +50,346 @@ type HTTPClient struct {
 	Client  *http.Client
 	Logger  *zap.Logger
 	BaseURL string
+
+	// thrift is the request body protocol, nil for json.
+	thrift *thriftHTTPProtocol
+	// recorder records the calls of the client, nil if not recording.
+	recorder *FixtureRecorder
+}
+
+// HTTPClientOptions configures the transport of a http client.
+type HTTPClientOptions struct {
+	// ClientID tags the connection pool metrics of the client.
+	ClientID string
+	// MaxIdleConns limits the idle connections across all hosts.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost limits the idle connections per host.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the connections per host, zero means no limit.
+	MaxConnsPerHost int
+	// DialTimeout is the timeout to establish a connection.
+	DialTimeout time.Duration
+	// KeepAlive is the keep alive period of connections.
+	KeepAlive time.Duration
+	// IdleConnTimeout is how long an idle connection is kept in the pool.
+	IdleConnTimeout time.Duration
+	// TLSHandshakeTimeout is the timeout of the TLS handshake.
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout is the timeout to read the response headers
+	// after the request is written, zero means no timeout.
+	ResponseHeaderTimeout time.Duration
+	// ExpectContinueTimeout is the time to wait for a 100-continue
+	// response when the request has an "Expect: 100-continue" header.
+	ExpectContinueTimeout time.Duration
+	// MaxResponseHeaderBytes limits the size of the response headers.
+	MaxResponseHeaderBytes int64
+	// Proxy is the proxy url, "environment" to read it from the
+	// environment or empty to connect directly.
+	Proxy string
+	// EnableHTTP2 attempts to use HTTP/2 for TLS connections.
+	EnableHTTP2 bool
+	// TLS configures https connections, nil for plaintext http.
+	TLS *TLSClientOptions
+	// Encoding is the body encoding of thrift structs, "json", "thrift"
+	// for the binary protocol or "compact" for the compact protocol.
+	Encoding string
+	// RecordDir is the directory the calls of the client are recorded to
+	// as endpoint test fixtures, calls are not recorded if empty.
+	RecordDir string
+}
+
+// TLSClientOptions configures the TLS connections of a http client.
+type TLSClientOptions struct {
+	// CAFile is the CA bundle used to verify the server, the system pool
+	// is used if empty.
+	CAFile string
+	// CertFile and KeyFile are the client certificate for mutual TLS. The
+	// certificate is reloaded when the files change.
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the server name to verify.
+	ServerName string
+}
+
+// Scheme returns the url scheme of the client.
+func (opts *HTTPClientOptions) Scheme() string {
+	if opts.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// NewHTTPClientOptions reads the transport options of a http client from the
+// "clients.<clientID>.*" config keys, falling back to the "http.clients.*"
+// keys shared by all clients and then to built in defaults.
+func NewHTTPClientOptions(
+	config *StaticConfig, clientID string,
+) *HTTPClientOptions {
+	r := httpClientConfigReader{config: config, clientID: clientID}
+	var tlsOpts *TLSClientOptions
+	if r.getBoolean("tls.enabled", false) {
+		tlsOpts = &TLSClientOptions{
+			CAFile:     r.getString("tls.caFile", ""),
+			CertFile:   r.getString("tls.certFile", ""),
+			KeyFile:    r.getString("tls.keyFile", ""),
+			ServerName: r.getString("tls.serverName", ""),
+		}
+	}
+	return &HTTPClientOptions{
+		ClientID:               clientID,
+		MaxIdleConns:           int(r.getInt("maxIdleConns", 500)),
+		MaxIdleConnsPerHost:    int(r.getInt("maxIdleConnsPerHost", 500)),
+		MaxConnsPerHost:        int(r.getInt("maxConnsPerHost", 0)),
+		DialTimeout:            r.getDuration("dialTimeout", 5000),
+		KeepAlive:              r.getDuration("keepAlive", 30000),
+		IdleConnTimeout:        r.getDuration("idleConnTimeout", 90000),
+		TLSHandshakeTimeout:    r.getDuration("tlsHandshakeTimeout", 10000),
+		ResponseHeaderTimeout:  r.getDuration("responseHeaderTimeout", 0),
+		ExpectContinueTimeout:  r.getDuration("expectContinueTimeout", 1000),
+		MaxResponseHeaderBytes: r.getInt("maxResponseHeaderBytes", 1<<20),
+		Proxy:                  r.getString("proxy", ""),
+		EnableHTTP2:            r.getBoolean("enableHTTP2", false),
+		TLS:                    tlsOpts,
+		Encoding:               r.getString("encoding", "json"),
+		RecordDir:              r.getString("recordDir", ""),
+	}
+}
+
+// httpClientConfigReader reads a client option from the client config or the
+// shared http client config.
+type httpClientConfigReader struct {
+	config   *StaticConfig
+	clientID string
+}
+
+func (r httpClientConfigReader) key(name string) (string, bool) {
+	if r.clientID != "" {
+		key := "clients." + r.clientID + "." + name
+		if r.config.ContainsKey(key) {
+			return key, true
+		}
+	}
+	key := defaultHTTPClientPrefix + name
+	return key, r.config.ContainsKey(key)
+}
+
+func (r httpClientConfigReader) getInt(name string, def int64) int64 {
+	if key, ok := r.key(name); ok {
+		return r.config.MustGetInt(key)
+	}
+	return def
+}
+
+func (r httpClientConfigReader) getDuration(
+	name string, defMillis int64,
+) time.Duration {
+	return time.Duration(r.getInt(name, defMillis)) * time.Millisecond
 }
 
-// NewHTTPClient will allocate a http client.
+func (r httpClientConfigReader) getString(name string, def string) string {
+	if key, ok := r.key(name); ok {
+		return r.config.MustGetString(key)
+	}
+	return def
+}
+
+func (r httpClientConfigReader) getBoolean(name string, def bool) bool {
+	if key, ok := r.key(name); ok {
+		return r.config.MustGetBoolean(key)
+	}
+	return def
+}
+
+// NewHTTPClient will allocate a http client using the transport options
+// shared by all clients.
 func NewHTTPClient(
 	gateway *Gateway, baseURL string,
 ) *HTTPClient {
+	return NewHTTPClientWithOptions(
+		gateway, baseURL, NewHTTPClientOptions(gateway.Config, ""),
+	)
+}
+
+// NewHTTPClientWithOptions will allocate a http client with the given
+// transport options. It panics if the proxy or encoding option is not valid
+// or the TLS files cannot be loaded.
+func NewHTTPClientWithOptions(
+	gateway *Gateway, baseURL string, opts *HTTPClientOptions,
+) *HTTPClient {
+	proxy, err := proxyFunc(opts.Proxy)
+	if err != nil {
+		panic(errors.Wrapf(
+			err, "Invalid proxy for http client (%s)", opts.ClientID,
+		))
+	}
+
+	thrift, err := thriftProtocolForEncoding(opts.Encoding)
+	if err != nil {
+		panic(errors.Wrapf(
+			err, "Invalid encoding for http client (%s)", opts.ClientID,
+		))
+	}
+
+	var tlsConfig *tls.Config
+	if opts.TLS != nil {
+		tlsConfig, err = newClientTLSConfig(opts.TLS)
+		if err != nil {
+			panic(errors.Wrapf(
+				err, "Invalid TLS config for http client (%s)", opts.ClientID,
+			))
+		}
+	}
+
+	scope := gateway.MetricScope.Tagged(map[string]string{
+		"client": opts.ClientID,
+	})
+	metrics := &httpClientPoolMetrics{
+		openConns:    scope.Gauge("outbound.connections.open"),
+		pendingCalls: scope.Gauge("outbound.calls.pending"),
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   opts.DialTimeout,
+		KeepAlive: opts.KeepAlive,
+	}
+	transport := &http.Transport{
+		Proxy:           proxy,
+		TLSClientConfig: tlsConfig,
+		DialContext: func(
+			ctx context.Context, network, addr string,
+		) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			metrics.connOpened()
+			return &countedConn{Conn: conn, metrics: metrics}, nil
+		},
+		DisableKeepAlives:      false,
+		MaxIdleConns:           opts.MaxIdleConns,
+		MaxIdleConnsPerHost:    opts.MaxIdleConnsPerHost,
+		MaxConnsPerHost:        opts.MaxConnsPerHost,
+		IdleConnTimeout:        opts.IdleConnTimeout,
+		TLSHandshakeTimeout:    opts.TLSHandshakeTimeout,
+		ResponseHeaderTimeout:  opts.ResponseHeaderTimeout,
+		ExpectContinueTimeout:  opts.ExpectContinueTimeout,
+		MaxResponseHeaderBytes: opts.MaxResponseHeaderBytes,
+		ForceAttemptHTTP2:      opts.EnableHTTP2,
+	}
+	if !opts.EnableHTTP2 {
+		transport.TLSNextProto = map[string]func(
+			string, *tls.Conn,
+		) http.RoundTripper{}
+	}
+
+	var recorder *FixtureRecorder
+	if opts.RecordDir != "" {
+		recorder = NewFixtureRecorder(opts.RecordDir)
+	}
+
 	return &HTTPClient{
 		gateway: gateway,
 
 		Logger: gateway.Logger,
 		Client: &http.Client{
-			Transport: &http.Transport{
-				DisableKeepAlives:   false,
-				MaxIdleConns:        500,
-				MaxIdleConnsPerHost: 500,
+			Transport: &pendingCallsTransport{
+				transport: transport,
+				metrics:   metrics,
 			},
 		},
 		BaseURL: baseURL,
+
+		thrift:   thrift,
+		recorder: recorder,
+	}
+}
+
+// NewStandaloneHTTPClient allocates a http client that is not owned by a
+// gateway, as used by the generated gateway SDK. It sends json bodies with
+// the given client, http.DefaultClient if nil, and logs to a no-op logger
+// if logger is nil.
+func NewStandaloneHTTPClient(
+	baseURL string, client *http.Client, logger *zap.Logger,
+) *HTTPClient {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &HTTPClient{
+		Logger:  logger,
+		Client:  client,
+		BaseURL: baseURL,
+	}
+}
+
+func proxyFunc(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	switch proxy {
+	case "":
+		return nil, nil
+	case proxyFromEnvironment:
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, err
 	}
+	if proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return nil, errors.Errorf("proxy url %q has no scheme or host", proxy)
+	}
+	return http.ProxyURL(proxyURL), nil
+}
+
+// httpClientPoolMetrics exports the connection pool stats of a http client
+// as gauges.
+type httpClientPoolMetrics struct {
+	openConns    tally.Gauge
+	pendingCalls tally.Gauge
+
+	numOpenConns    int64
+	numPendingCalls int64
+}
+
+func (m *httpClientPoolMetrics) connOpened() {
+	m.openConns.Update(float64(atomic.AddInt64(&m.numOpenConns, 1)))
+}
+
+func (m *httpClientPoolMetrics) connClosed() {
+	m.openConns.Update(float64(atomic.AddInt64(&m.numOpenConns, -1)))
+}
+
+func (m *httpClientPoolMetrics) callStarted() {
+	m.pendingCalls.Update(float64(atomic.AddInt64(&m.numPendingCalls, 1)))
+}
+
+func (m *httpClientPoolMetrics) callFinished() {
+	m.pendingCalls.Update(float64(atomic.AddInt64(&m.numPendingCalls, -1)))
+}
+
+// countedConn decrements the open connections gauge when closed.
+type countedConn struct {
+	net.Conn
+	metrics   *httpClientPoolMetrics
+	closeOnce sync.Once
+}
+
+func (c *countedConn) Close() error {
+	c.closeOnce.Do(c.metrics.connClosed)
+	return c.Conn.Close()
+}
+
+// pendingCallsTransport tracks the calls waiting for a response.
+type pendingCallsTransport struct {
+	transport *http.Transport
+	metrics   *httpClientPoolMetrics
+}
+
+func (t *pendingCallsTransport) RoundTrip(
+	req *http.Request,
+) (*http.Response, error) {
+	t.metrics.callStarted()
+	defer t.metrics.callFinished()
+	return t.transport.RoundTrip(req)
 }
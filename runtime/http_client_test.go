@@ -0,0 +1,101 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/zanzibar/examples/example-gateway/build/clients"
+	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints"
+	zanzibar "github.com/uber/zanzibar/runtime"
+	"github.com/uber/zanzibar/test/lib/bench_gateway"
+)
+
+func TestNewHTTPClientOptions(t *testing.T) {
+	config := zanzibar.NewStaticConfigOrDie(nil, map[string]interface{}{
+		"http.clients.maxIdleConns":  int64(100),
+		"http.clients.dialTimeout":   int64(200),
+		"clients.bar.maxIdleConns":   int64(10),
+		"clients.bar.proxy":          "environment",
+		"clients.bar.enableHTTP2":    true,
+		"clients.other.maxIdleConns": int64(20),
+	})
+
+	opts := zanzibar.NewHTTPClientOptions(config, "bar")
+	assert.Equal(t, "bar", opts.ClientID)
+	assert.Equal(t, 10, opts.MaxIdleConns)
+	assert.Equal(t, 200*time.Millisecond, opts.DialTimeout)
+	assert.Equal(t, 90*time.Second, opts.IdleConnTimeout)
+	assert.Equal(t, "environment", opts.Proxy)
+	assert.True(t, opts.EnableHTTP2)
+
+	opts = zanzibar.NewHTTPClientOptions(config, "")
+	assert.Equal(t, 100, opts.MaxIdleConns)
+	assert.Equal(t, 500, opts.MaxIdleConnsPerHost)
+	assert.Equal(t, "", opts.Proxy)
+	assert.False(t, opts.EnableHTTP2)
+}
+
+func TestHTTPClientWithOptions(t *testing.T) {
+	gateway, err := benchGateway.CreateGateway(
+		defaultTestConfig,
+		defaultTestOptions,
+		clients.CreateClients,
+		endpoints.Register,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gateway.Close()
+
+	bgateway := gateway.(*benchGateway.BenchGateway)
+	bgateway.HTTPBackends()["bar"].HandleFunc(
+		"GET", "/bar-path",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(200)
+		},
+	)
+
+	opts := zanzibar.NewHTTPClientOptions(
+		bgateway.ActualGateway.Config, "bar",
+	)
+	client := zanzibar.NewHTTPClientWithOptions(
+		bgateway.ActualGateway,
+		"http://"+bgateway.HTTPBackends()["bar"].RealAddr,
+		opts,
+	)
+	res, err := client.Client.Get(client.BaseURL + "/bar-path")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, res.Body.Close())
+	assert.Equal(t, 200, res.StatusCode)
+
+	opts.Proxy = "not a url"
+	assert.Panics(t, func() {
+		zanzibar.NewHTTPClientWithOptions(
+			bgateway.ActualGateway, "/", opts,
+		)
+	})
+}
@@ -71,7 +71,12 @@ func (server *HTTPServer) JustListen() (net.Listener, error) {
 func (server *HTTPServer) JustServe(waitGroup *sync.WaitGroup) {
 	ln := server.listeningSocket.(*net.TCPListener)
 
-	err := server.Serve(tcpKeepAliveListener{ln})
+	var err error
+	if server.TLSConfig != nil {
+		err = server.ServeTLS(tcpKeepAliveListener{ln}, "", "")
+	} else {
+		err = server.Serve(tcpKeepAliveListener{ln})
+	}
 	if err != nil && !server.closing {
 		/* coverage ignore next line */
 		server.Logger.Error("Error http serving",
@@ -0,0 +1,153 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/thriftrw/protocol"
+	"go.uber.org/thriftrw/wire"
+)
+
+// Content types of thrift encoded http bodies.
+const (
+	ThriftBinaryContentType  = "application/x-thrift"
+	ThriftCompactContentType = "application/vnd.apache.thrift.compact"
+)
+
+// thriftProtocol is the subset of the thriftrw protocol interface used to
+// encode http bodies.
+type thriftProtocol interface {
+	Encode(v wire.Value, w io.Writer) error
+	Decode(r io.ReaderAt, t wire.Type) (wire.Value, error)
+}
+
+// thriftHTTPProtocol is a thrift protocol negotiated by content type.
+type thriftHTTPProtocol struct {
+	contentType string
+	protocol    thriftProtocol
+}
+
+var (
+	thriftBinaryHTTP = &thriftHTTPProtocol{
+		contentType: ThriftBinaryContentType,
+		protocol:    protocol.Binary,
+	}
+	thriftCompactHTTP = &thriftHTTPProtocol{
+		contentType: ThriftCompactContentType,
+		protocol:    compactProtocol{},
+	}
+)
+
+// thriftHTTPProtocols maps the recognized media types to their protocol,
+// nil is json.
+var thriftHTTPProtocols = map[string]*thriftHTTPProtocol{
+	ThriftBinaryContentType:                thriftBinaryHTTP,
+	"application/vnd.apache.thrift.binary": thriftBinaryHTTP,
+	ThriftCompactContentType:               thriftCompactHTTP,
+	"application/x-thrift-compact":         thriftCompactHTTP,
+	"application/json":                     nil,
+	"application/*":                        nil,
+	"*/*":                                  nil,
+}
+
+// thriftProtocolForEncoding returns the protocol of a client encoding
+// option, "json", "thrift" or "compact".
+func thriftProtocolForEncoding(encoding string) (*thriftHTTPProtocol, error) {
+	switch encoding {
+	case "", "json":
+		return nil, nil
+	case "thrift":
+		return thriftBinaryHTTP, nil
+	case "compact":
+		return thriftCompactHTTP, nil
+	}
+	return nil, errors.Errorf("unknown http body encoding %q", encoding)
+}
+
+// thriftProtocolForContentType returns the thrift protocol of a body, or nil
+// if the body is not thrift encoded.
+func thriftProtocolForContentType(contentType string) *thriftHTTPProtocol {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+	return thriftHTTPProtocols[mediaType]
+}
+
+// thriftProtocolForAccept negotiates the response protocol from an Accept
+// header, preferring the earliest media type with the highest quality. It
+// returns nil for json and false if no media type is recognized.
+func thriftProtocolForAccept(accept string) (*thriftHTTPProtocol, bool) {
+	var best *thriftHTTPProtocol
+	bestQuality := 0.0
+	found := false
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		p, ok := thriftHTTPProtocols[mediaType]
+		if !ok {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+		}
+		if quality > bestQuality {
+			best, bestQuality, found = p, quality, true
+		}
+	}
+	return best, found
+}
+
+func (p *thriftHTTPProtocol) marshal(s RWTStruct) ([]byte, error) {
+	wireValue, err := s.ToWire()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := p.protocol.Encode(wireValue, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (p *thriftHTTPProtocol) unmarshal(rawBody []byte, body interface{}) error {
+	s, ok := body.(RWTStruct)
+	if !ok {
+		return errors.Errorf("%T is not a thrift struct", body)
+	}
+	wireValue, err := p.protocol.Decode(bytes.NewReader(rawBody), wire.TStruct)
+	if err != nil {
+		return err
+	}
+	return s.FromWire(wireValue)
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/zanzibar/examples/example-gateway/build/clients"
+	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints"
+	zanzibar "github.com/uber/zanzibar/runtime"
+	"github.com/uber/zanzibar/test/lib/bench_gateway"
+
+	clientsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/bar/bar"
+	endpointsBarBar "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/bar/bar"
+)
+
+func handleBarJSON(bgateway *benchGateway.BenchGateway) {
+	bgateway.HTTPBackends()["bar"].HandleFunc(
+		"POST", "/bar-path",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{
+				"stringField": "foo",
+				"intWithRange": 1,
+				"intWithoutRange": 2,
+				"mapIntWithRange": {},
+				"mapIntWithoutRange": {}
+			}`))
+		},
+	)
+}
+
+func TestEndpointThriftBinary(t *testing.T) {
+	gateway, err := benchGateway.CreateGateway(
+		defaultTestConfig,
+		defaultTestOptions,
+		clients.CreateClients,
+		endpoints.Register,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gateway.Close()
+
+	bgateway := gateway.(*benchGateway.BenchGateway)
+	handleBarJSON(bgateway)
+
+	var body bytes.Buffer
+	err = zanzibar.WriteStruct(&body, &endpointsBarBar.Bar_Normal_Args{
+		Request: &endpointsBarBar.BarRequest{StringField: "foo"},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	res, err := gateway.MakeRequest("POST", "/bar/bar-path", map[string]string{
+		"Content-Type": zanzibar.ThriftBinaryContentType,
+	}, &body)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t,
+		zanzibar.ThriftBinaryContentType, res.Header.Get("Content-Type"),
+	)
+
+	var response endpointsBarBar.BarResponse
+	if assert.NoError(t, zanzibar.ReadStruct(res.Body, &response)) {
+		assert.Equal(t, "foo", response.StringField)
+		assert.Equal(t, int32(1), response.IntWithRange)
+	}
+}
+
+func TestEndpointThriftBadBody(t *testing.T) {
+	gateway, err := benchGateway.CreateGateway(
+		defaultTestConfig,
+		defaultTestOptions,
+		clients.CreateClients,
+		endpoints.Register,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gateway.Close()
+
+	for _, body := range [][]byte{
+		{0x1d},                               // unknown type
+		{0x18, 0xff, 0xff, 0xff, 0xff, 0x07}, // huge binary
+		bytes.Repeat([]byte{0x1c}, 1000),     // deeply nested structs
+	} {
+		res, err := gateway.MakeRequest("POST", "/bar/bar-path", map[string]string{
+			"Content-Type": zanzibar.ThriftCompactContentType,
+			"Accept":       "application/json",
+		}, bytes.NewReader(body))
+		if !assert.NoError(t, err) {
+			return
+		}
+		_ = res.Body.Close()
+
+		assert.Equal(t, 400, res.StatusCode, "body %x", body)
+		assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	}
+}
+
+func TestClientCompactToEndpoint(t *testing.T) {
+	gateway, err := benchGateway.CreateGateway(
+		defaultTestConfig,
+		defaultTestOptions,
+		clients.CreateClients,
+		endpoints.Register,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gateway.Close()
+
+	bgateway := gateway.(*benchGateway.BenchGateway)
+	handleBarJSON(bgateway)
+
+	opts := zanzibar.NewHTTPClientOptions(bgateway.ActualGateway.Config, "")
+	opts.Encoding = "compact"
+	baseURL := "http://" + bgateway.ActualGateway.RealHTTPAddr
+	client := zanzibar.NewHTTPClientWithOptions(
+		bgateway.ActualGateway, baseURL, opts,
+	)
+
+	req := zanzibar.NewClientHTTPRequest("gateway", "normal", client)
+	err = req.WriteJSON("POST", baseURL+"/bar/bar-path", nil,
+		&endpointsBarBar.Bar_Normal_Args{
+			Request: &endpointsBarBar.BarRequest{StringField: "foo"},
+		},
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	res, err := req.Do(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t,
+		zanzibar.ThriftCompactContentType, res.Header.Get("Content-Type"),
+	)
+
+	var response endpointsBarBar.BarResponse
+	if assert.NoError(t, res.ReadAndUnmarshalBody(&response)) {
+		assert.Equal(t, "foo", response.StringField)
+		assert.Equal(t, int32(1), response.IntWithRange)
+	}
+}
+
+func TestClientThriftBinary(t *testing.T) {
+	config := map[string]interface{}{
+		"clients.bar.encoding": "thrift",
+	}
+	for k, v := range defaultTestConfig {
+		config[k] = v
+	}
+	gateway, err := benchGateway.CreateGateway(
+		config,
+		defaultTestOptions,
+		clients.CreateClients,
+		endpoints.Register,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gateway.Close()
+
+	bgateway := gateway.(*benchGateway.BenchGateway)
+	bgateway.HTTPBackends()["bar"].HandleFunc(
+		"POST", "/bar-path",
+		func(w http.ResponseWriter, r *http.Request) {
+			var args clientsBarBar.Bar_Normal_Args
+			if r.Header.Get("Content-Type") != zanzibar.ThriftBinaryContentType ||
+				zanzibar.ReadStruct(r.Body, &args) != nil {
+				w.WriteHeader(400)
+				return
+			}
+
+			w.Header().Set("Content-Type", zanzibar.ThriftBinaryContentType)
+			w.WriteHeader(200)
+			_ = zanzibar.WriteStruct(w, &clientsBarBar.BarResponse{
+				StringField:        args.Request.StringField,
+				MapIntWithRange:    map[string]int32{},
+				MapIntWithoutRange: map[string]int32{},
+			})
+		},
+	)
+
+	bar := bgateway.ActualGateway.Clients.(*clients.Clients).Bar
+	response, _, err := bar.Normal(
+		context.Background(), nil, &clientsBarBar.Bar_Normal_Args{
+			Request: &clientsBarBar.BarRequest{StringField: "foo"},
+		},
+	)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "foo", response.StringField)
+	}
+}
@@ -32,6 +32,7 @@ import (
 
 	"github.com/uber/zanzibar/examples/example-gateway/build/clients"
 	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints"
+	exampleMiddleware "github.com/uber/zanzibar/examples/example-gateway/build/middlewares/example"
 	zanzibar "github.com/uber/zanzibar/runtime"
 	"github.com/uber/zanzibar/test/lib/bench_gateway"
 )
@@ -40,6 +41,7 @@ import (
 func TestHandlers(t *testing.T) {
 	ex := example.NewMiddleWare(
 		nil, // *zanzibar.Gateway
+		&exampleMiddleware.Dependencies{},
 		example.Options{
 			Foo: "foo",
 			Bar: 2,
@@ -239,6 +241,7 @@ func TestMiddlewareResponseAbort(t *testing.T) {
 func TestMiddlewareSharedStates(t *testing.T) {
 	ex := example.NewMiddleWare(
 		nil, // nil Gateway
+		&exampleMiddleware.Dependencies{},
 		example.Options{
 			Foo: "test_state",
 			Bar: 2,
@@ -21,8 +21,11 @@
 package zanzibar
 
 import (
+	"crypto/x509"
 	"encoding/json"
 	"io/ioutil"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 
@@ -33,6 +36,10 @@ import (
 	"go.uber.org/zap"
 )
 
+// maxFormMemory is how much of a multipart body is kept in memory, the
+// remaining file parts are stored in temporary files.
+const maxFormMemory = 32 << 20
+
 // ServerHTTPRequest struct manages request
 type ServerHTTPRequest struct {
 	httpRequest *http.Request
@@ -98,6 +105,31 @@ func (req *ServerHTTPRequest) start(endpoint string, handler string) {
 	req.metrics.requestRecvd.Inc(1)
 }
 
+// PeerCertificate returns the verified client certificate of a mutual TLS
+// request, or nil if the client was not authenticated.
+func (req *ServerHTTPRequest) PeerCertificate() *x509.Certificate {
+	state := req.httpRequest.TLS
+	if state == nil || len(state.VerifiedChains) == 0 ||
+		len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return state.VerifiedChains[0][0]
+}
+
+// PeerIdentity returns the identity of a mutual TLS client, which is the
+// first URI SAN of its certificate (e.g. a SPIFFE ID) or otherwise its subject
+// common name. It is empty if the client was not authenticated.
+func (req *ServerHTTPRequest) PeerIdentity() string {
+	cert := req.PeerCertificate()
+	if cert == nil {
+		return ""
+	}
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	return cert.Subject.CommonName
+}
+
 // CheckHeaders verifies that request contains required headers.
 func (req *ServerHTTPRequest) CheckHeaders(headers []string) bool {
 	for _, headerName := range headers {
@@ -116,7 +148,8 @@ func (req *ServerHTTPRequest) CheckHeaders(headers []string) bool {
 	return true
 }
 
-// ReadAndUnmarshalBody will try to unmarshal into struct or fail
+// ReadAndUnmarshalBody will try to unmarshal into struct or fail. Bodies
+// with a thrift content type are decoded with the thrift protocol.
 func (req *ServerHTTPRequest) ReadAndUnmarshalBody(
 	body json.Unmarshaler,
 ) bool {
@@ -125,9 +158,91 @@ func (req *ServerHTTPRequest) ReadAndUnmarshalBody(
 		return false
 	}
 
+	p := thriftProtocolForContentType(req.httpRequest.Header.Get("Content-Type"))
+	if p != nil {
+		return req.unmarshalThriftBody(p, body, rawBody)
+	}
 	return req.UnmarshalBody(body, rawBody)
 }
 
+func (req *ServerHTTPRequest) unmarshalThriftBody(
+	p *thriftHTTPProtocol, body json.Unmarshaler, rawBody []byte,
+) bool {
+	if _, ok := body.(RWTStruct); !ok {
+		req.res.SendErrorString(415, "Unsupported content type: "+p.contentType)
+		return false
+	}
+
+	err := p.unmarshal(rawBody, body)
+	if err != nil {
+		req.res.SendErrorString(400, "Could not parse thrift: "+err.Error())
+		req.Logger.Warn("Could not parse thrift",
+			zap.String("error", err.Error()),
+		)
+		return false
+	}
+
+	return true
+}
+
+// ReadAndUnmarshalForm will try to unmarshal an urlencoded or multipart
+// form body into struct or fail, file parts populate binary fields.
+func (req *ServerHTTPRequest) ReadAndUnmarshalForm(
+	body json.Unmarshaler,
+) bool {
+	httpReq := req.httpRequest
+	mediaType, _, _ := mime.ParseMediaType(httpReq.Header.Get("Content-Type"))
+
+	var err error
+	if mediaType == "multipart/form-data" {
+		err = httpReq.ParseMultipartForm(maxFormMemory)
+	} else {
+		err = httpReq.ParseForm()
+	}
+	if err != nil {
+		req.res.SendErrorString(400, "Could not parse form: "+err.Error())
+		req.Logger.Warn("Could not parse form",
+			zap.String("error", err.Error()),
+		)
+		return false
+	}
+
+	files := map[string][]byte{}
+	if httpReq.MultipartForm != nil {
+		for name, headers := range httpReq.MultipartForm.File {
+			content, err := readFormFile(headers[0])
+			if err != nil {
+				req.res.SendErrorString(500, "Could not read form file")
+				req.Logger.Error("Could not read form file",
+					zap.String("error", err.Error()),
+				)
+				return false
+			}
+			files[name] = content
+		}
+	}
+
+	err = UnmarshalForm(httpReq.PostForm, files, body)
+	if err != nil {
+		req.res.SendErrorString(400, "Could not parse form: "+err.Error())
+		req.Logger.Warn("Could not parse form",
+			zap.String("error", err.Error()),
+		)
+		return false
+	}
+
+	return true
+}
+
+func readFormFile(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	return ioutil.ReadAll(file)
+}
+
 // ReadAll helper to read entire body
 func (req *ServerHTTPRequest) ReadAll() ([]byte, bool) {
 	rawBody, err := ioutil.ReadAll(req.httpRequest.Body)
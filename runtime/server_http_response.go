@@ -41,6 +41,7 @@ type ServerHTTPResponse struct {
 	flushed           bool
 	pendingBodyBytes  []byte
 	pendingBodyObj    interface{}
+	pendingBodyThrift bool
 	pendingStatusCode int
 
 	StatusCode int
@@ -134,9 +135,11 @@ func (res *ServerHTTPResponse) WriteJSONBytes(
 
 	res.pendingStatusCode = statusCode
 	res.pendingBodyBytes = bytes
+	res.pendingBodyThrift = false
 }
 
-// WriteJSON writes a json serializable struct to Response
+// WriteJSON writes a json serializable struct to Response. Thrift structs
+// are written with the thrift protocol negotiated from the request instead.
 func (res *ServerHTTPResponse) WriteJSON(
 	statusCode int, headers Header, body json.Marshaler,
 ) {
@@ -146,6 +149,13 @@ func (res *ServerHTTPResponse) WriteJSON(
 		return
 	}
 
+	if s, ok := body.(RWTStruct); ok {
+		if p := res.responseThriftProtocol(); p != nil {
+			res.writeThrift(statusCode, headers, p, s)
+			return
+		}
+	}
+
 	bytes, err := body.MarshalJSON()
 	if err != nil {
 		res.SendErrorString(500, "Could not serialize json response")
@@ -171,6 +181,48 @@ func (res *ServerHTTPResponse) WriteJSON(
 	res.pendingStatusCode = statusCode
 	res.pendingBodyBytes = bytes
 	res.pendingBodyObj = body
+	res.pendingBodyThrift = false
+}
+
+// responseThriftProtocol negotiates the response protocol from the Accept
+// header, or the request content type if there is no Accept header. It
+// returns nil for json.
+func (res *ServerHTTPResponse) responseThriftProtocol() *thriftHTTPProtocol {
+	header := res.Request.httpRequest.Header
+	if accept := header.Get("Accept"); accept != "" {
+		p, _ := thriftProtocolForAccept(accept)
+		return p
+	}
+	return thriftProtocolForContentType(header.Get("Content-Type"))
+}
+
+func (res *ServerHTTPResponse) writeThrift(
+	statusCode int, headers Header, p *thriftHTTPProtocol, body RWTStruct,
+) {
+	bytes, err := p.marshal(body)
+	if err != nil {
+		res.SendErrorString(500, "Could not serialize thrift response")
+		res.Request.Logger.Error("Could not serialize thrift response",
+			zap.String("error", err.Error()),
+		)
+		return
+	}
+
+	if headers != nil {
+		for _, k := range headers.Keys() {
+			v, ok := headers.Get(k)
+			if ok {
+				res.responseWriter.Header().Set(k, v)
+			}
+		}
+	}
+
+	res.responseWriter.Header().Set("content-type", p.contentType)
+
+	res.pendingStatusCode = statusCode
+	res.pendingBodyBytes = bytes
+	res.pendingBodyObj = body
+	res.pendingBodyThrift = true
 }
 
 // PeekBody allows for inspecting a key path inside the body
@@ -179,9 +231,16 @@ func (res *ServerHTTPResponse) WriteJSON(
 func (res *ServerHTTPResponse) PeekBody(
 	keys ...string,
 ) ([]byte, jsonparser.ValueType, error) {
-	value, valueType, _, err := jsonparser.Get(
-		res.pendingBodyBytes, keys...,
-	)
+	body := res.pendingBodyBytes
+	if res.pendingBodyThrift {
+		var err error
+		body, err = res.pendingBodyObj.(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return nil, -1, err
+		}
+	}
+
+	value, valueType, _, err := jsonparser.Get(body, keys...)
 
 	if err != nil {
 		return nil, -1, err
@@ -32,6 +32,12 @@ import (
 	"github.com/pkg/errors"
 )
 
+// The sources of the values not read from a file
+const (
+	seedConfigSource = "seed"
+	setOrDieSource   = "set"
+)
+
 // StaticConfigValue represents a json serialized string.
 type StaticConfigValue struct {
 	bytes    []byte
@@ -43,6 +49,7 @@ type StaticConfig struct {
 	seedConfig   map[string]interface{}
 	files        []string
 	configValues map[string]StaticConfigValue
+	sources      map[string]string
 	frozen       bool
 	destroyed    bool
 }
@@ -75,10 +82,12 @@ func NewStaticConfigOrDie(
 		files:        files,
 		seedConfig:   map[string]interface{}{},
 		configValues: map[string]StaticConfigValue{},
+		sources:      map[string]string{},
 	}
 
 	for key, value := range seedConfig {
 		config.seedConfig[key] = value
+		config.sources[key] = seedConfigSource
 	}
 
 	config.initializeConfigValues()
@@ -86,6 +95,22 @@ func NewStaticConfigOrDie(
 	return config
 }
 
+// ContainsKey returns true if the key is set in the config. Like the
+// MustGet* getters, and unlike the Get* getters, it does not read the fields
+// of objects by their dotted path and panics once destroyed.
+func (conf *StaticConfig) ContainsKey(key string) bool {
+	if conf.destroyed {
+		panic(errors.Errorf("Cannot get(%s) because destroyed", key))
+	}
+
+	if _, contains := conf.seedConfig[key]; contains {
+		return true
+	}
+
+	_, contains := conf.configValues[key]
+	return contains
+}
+
 // MustGetBoolean returns the value as a boolean or panics.
 func (conf *StaticConfig) MustGetBoolean(key string) bool {
 	if conf.destroyed {
@@ -122,7 +147,11 @@ func (conf *StaticConfig) MustGetFloat(key string) float64 {
 	}
 
 	if value, contains := conf.seedConfig[key]; contains {
-		return value.(float64)
+		v, ok := seedFloat(value)
+		if !ok {
+			panic(errors.Errorf("Key (%s) is not a number: %v", key, value))
+		}
+		return v
 	}
 
 	if value, contains := conf.configValues[key]; contains {
@@ -151,7 +180,11 @@ func (conf *StaticConfig) MustGetInt(key string) int64 {
 	}
 
 	if value, contains := conf.seedConfig[key]; contains {
-		return value.(int64)
+		v, ok := seedInt(value)
+		if !ok {
+			panic(errors.Errorf("Key (%s) is not an integer: %v", key, value))
+		}
+		return v
 	}
 
 	if value, contains := conf.configValues[key]; contains {
@@ -250,6 +283,7 @@ func (conf *StaticConfig) SetOrDie(key string, value interface{}) {
 	}
 
 	conf.seedConfig[key] = value
+	conf.sources[key] = setOrDieSource
 }
 
 // Freeze the configuration store.
@@ -267,9 +301,11 @@ func (conf *StaticConfig) Destroy() {
 	conf.frozen = true
 	conf.configValues = map[string]StaticConfigValue{}
 	conf.seedConfig = map[string]interface{}{}
+	conf.sources = map[string]string{}
 }
 
-// InspectOrDie returns the entire config object.
+// InspectOrDie returns the entire config object, InspectSourcesOrDie returns
+// the file or override each value comes from.
 // This should not be mutated and should only be used for inspection or debugging
 func (conf *StaticConfig) InspectOrDie() map[string]interface{} {
 	result := map[string]interface{}{}
@@ -303,30 +339,28 @@ func (conf *StaticConfig) InspectOrDie() map[string]interface{} {
 	return result
 }
 
-func (conf *StaticConfig) initializeConfigValues() {
-	values := conf.collectConfigMaps()
-	conf.assignConfigValues(values)
-}
-
-func (conf *StaticConfig) collectConfigMaps() []map[string]StaticConfigValue {
-	var maps = []map[string]StaticConfigValue{}
-
-	for i := 0; i < len(conf.files); i++ {
-		fileObject := conf.parseFile(conf.files[i])
-		if fileObject != nil {
-			maps = append(maps, fileObject)
-		}
+// InspectSourcesOrDie returns where the value of every key comes from, the
+// last file setting it, "seed" for the seed config or "set" for SetOrDie.
+// This should only be used for inspection or debugging
+func (conf *StaticConfig) InspectSourcesOrDie() map[string]string {
+	if conf.destroyed {
+		panic(errors.New("Cannot inspect sources because destroyed"))
 	}
 
-	return maps
+	result := map[string]string{}
+	for k, v := range conf.sources {
+		result[k] = v
+	}
+	return result
 }
 
-func (conf *StaticConfig) assignConfigValues(values []map[string]StaticConfigValue) {
-	for i := 0; i < len(values); i++ {
-		configObject := values[i]
-
-		for key, value := range configObject {
+func (conf *StaticConfig) initializeConfigValues() {
+	for _, file := range conf.files {
+		for key, value := range conf.parseFile(file) {
 			conf.configValues[key] = value
+			if _, seeded := conf.seedConfig[key]; !seeded {
+				conf.sources[key] = file
+			}
 		}
 	}
 }
@@ -0,0 +1,328 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/buger/jsonparser"
+)
+
+// configEntry is a value found in the config, either seeded or read from a
+// config file.
+type configEntry struct {
+	seeded bool
+	seed   interface{}
+	value  StaticConfigValue
+}
+
+// lookup finds the value of the key. A key that is not in the config is read
+// as a dotted path into the object of the longest key it starts with, e.g.
+// "clients.bar.retry.count" is the "count" field of the "retry" field of
+// "clients.bar". Nothing is found once the config is destroyed.
+// ContainsKey and the MustGet* getters only find the keys themselves, and
+// panic once destroyed.
+func (conf *StaticConfig) lookup(key string) (configEntry, bool) {
+	if conf.destroyed {
+		return configEntry{}, false
+	}
+
+	if v, contains := conf.seedConfig[key]; contains {
+		return configEntry{seeded: true, seed: v}, true
+	}
+	if v, contains := conf.configValues[key]; contains {
+		return configEntry{value: v}, true
+	}
+
+	path := strings.Split(key, ".")
+	for i := len(path) - 1; i > 0; i-- {
+		prefix := strings.Join(path[:i], ".")
+		if v, contains := conf.seedConfig[prefix]; contains {
+			for _, field := range path[i:] {
+				object, ok := v.(map[string]interface{})
+				if !ok {
+					return configEntry{}, false
+				}
+				if v, ok = object[field]; !ok {
+					return configEntry{}, false
+				}
+			}
+			return configEntry{seeded: true, seed: v}, true
+		}
+		if v, contains := conf.configValues[prefix]; contains {
+			if v.dataType != jsonparser.Object {
+				return configEntry{}, false
+			}
+			bytes, dataType, _, err := jsonparser.Get(v.bytes, path[i:]...)
+			if err != nil {
+				return configEntry{}, false
+			}
+			return configEntry{value: StaticConfigValue{
+				bytes:    bytes,
+				dataType: dataType,
+			}}, true
+		}
+	}
+	return configEntry{}, false
+}
+
+// GetBoolean returns the value as a boolean, ok is false if the key is not
+// set or is not a boolean.
+func (conf *StaticConfig) GetBoolean(key string) (value bool, ok bool) {
+	entry, found := conf.lookup(key)
+	if !found {
+		return false, false
+	}
+	if entry.seeded {
+		value, ok = entry.seed.(bool)
+		return value, ok
+	}
+	if entry.value.dataType != jsonparser.Boolean {
+		return false, false
+	}
+	value, err := jsonparser.ParseBoolean(entry.value.bytes)
+	return value, err == nil
+}
+
+// GetFloat returns the value as a float, ok is false if the key is not set
+// or is not a number.
+func (conf *StaticConfig) GetFloat(key string) (value float64, ok bool) {
+	entry, found := conf.lookup(key)
+	if !found {
+		return 0, false
+	}
+	if entry.seeded {
+		return seedFloat(entry.seed)
+	}
+	if entry.value.dataType != jsonparser.Number {
+		return 0, false
+	}
+	value, err := jsonparser.ParseFloat(entry.value.bytes)
+	return value, err == nil
+}
+
+// GetInt returns the value as an int, ok is false if the key is not set or
+// is not an integer.
+func (conf *StaticConfig) GetInt(key string) (value int64, ok bool) {
+	entry, found := conf.lookup(key)
+	if !found {
+		return 0, false
+	}
+	if entry.seeded {
+		return seedInt(entry.seed)
+	}
+	if entry.value.dataType != jsonparser.Number {
+		return 0, false
+	}
+	value, err := jsonparser.ParseInt(entry.value.bytes)
+	return value, err == nil
+}
+
+// GetString returns the value as a string, ok is false if the key is not
+// set or is not a string.
+func (conf *StaticConfig) GetString(key string) (value string, ok bool) {
+	entry, found := conf.lookup(key)
+	if !found {
+		return "", false
+	}
+	if entry.seeded {
+		value, ok = entry.seed.(string)
+		return value, ok
+	}
+	if entry.value.dataType != jsonparser.String {
+		return "", false
+	}
+	value, err := jsonparser.ParseString(entry.value.bytes)
+	return value, err == nil
+}
+
+// GetDuration returns the value as a duration, an integer being a number of
+// milliseconds like the other timeouts of the config, or a string like
+// "1.5s" or a seeded time.Duration. ok is false if the key is not set or is
+// not an integer, a time.Duration or a string parsed by time.ParseDuration.
+func (conf *StaticConfig) GetDuration(key string) (value time.Duration, ok bool) {
+	entry, found := conf.lookup(key)
+	if found && entry.seeded {
+		value, ok = entry.seed.(time.Duration)
+		if ok {
+			return value, true
+		}
+	}
+	if millis, ok := conf.GetInt(key); ok {
+		return time.Duration(millis) * time.Millisecond, true
+	}
+	s, ok := conf.GetString(key)
+	if !ok {
+		return 0, false
+	}
+	value, err := time.ParseDuration(s)
+	return value, err == nil
+}
+
+// GetStringList returns the value as a list of strings, ok is false if the
+// key is not set or is not an array of strings.
+func (conf *StaticConfig) GetStringList(key string) (value []string, ok bool) {
+	entry, found := conf.lookup(key)
+	if !found {
+		return nil, false
+	}
+	if entry.seeded {
+		value, ok = entry.seed.([]string)
+		return value, ok
+	}
+	if entry.value.dataType != jsonparser.Array {
+		return nil, false
+	}
+	err := json.Unmarshal(entry.value.bytes, &value)
+	return value, err == nil
+}
+
+// GetStringMap returns the value as a map of strings, ok is false if the key
+// is not set or is not an object of strings.
+func (conf *StaticConfig) GetStringMap(key string) (value map[string]string, ok bool) {
+	entry, found := conf.lookup(key)
+	if !found {
+		return nil, false
+	}
+	if entry.seeded {
+		value, ok = entry.seed.(map[string]string)
+		return value, ok
+	}
+	if entry.value.dataType != jsonparser.Object {
+		return nil, false
+	}
+	err := json.Unmarshal(entry.value.bytes, &value)
+	return value, err == nil
+}
+
+// GetStruct reads the value into ptr like MustGetStruct, it returns false
+// if the key is not set or the value can not be read into ptr.
+func (conf *StaticConfig) GetStruct(key string, ptr interface{}) bool {
+	entry, found := conf.lookup(key)
+	if !found {
+		return false
+	}
+	rptr := reflect.ValueOf(ptr)
+	if rptr.Kind() != reflect.Ptr || rptr.IsNil() {
+		return false
+	}
+	if entry.seeded {
+		v := reflect.ValueOf(entry.seed)
+		if !v.IsValid() || !v.Type().AssignableTo(rptr.Elem().Type()) {
+			return false
+		}
+		rptr.Elem().Set(v)
+		return true
+	}
+	return json.Unmarshal(entry.value.bytes, ptr) == nil
+}
+
+// GetOrBoolean returns the value as a boolean, or defaultValue if the key is
+// not set or is not a boolean.
+func (conf *StaticConfig) GetOrBoolean(key string, defaultValue bool) bool {
+	if value, ok := conf.GetBoolean(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// GetOrFloat returns the value as a float, or defaultValue if the key is not
+// set or is not a number.
+func (conf *StaticConfig) GetOrFloat(key string, defaultValue float64) float64 {
+	if value, ok := conf.GetFloat(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// GetOrInt returns the value as an int, or defaultValue if the key is not
+// set or is not an integer.
+func (conf *StaticConfig) GetOrInt(key string, defaultValue int64) int64 {
+	if value, ok := conf.GetInt(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// GetOrString returns the value as a string, or defaultValue if the key is
+// not set or is not a string.
+func (conf *StaticConfig) GetOrString(key string, defaultValue string) string {
+	if value, ok := conf.GetString(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// GetOrDuration returns the value as a duration, or defaultValue if the key
+// is not set or is not a duration.
+func (conf *StaticConfig) GetOrDuration(
+	key string, defaultValue time.Duration,
+) time.Duration {
+	if value, ok := conf.GetDuration(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// GetOrStringList returns the value as a list of strings, or defaultValue if
+// the key is not set or is not an array of strings.
+func (conf *StaticConfig) GetOrStringList(
+	key string, defaultValue []string,
+) []string {
+	if value, ok := conf.GetStringList(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// GetOrStringMap returns the value as a map of strings, or defaultValue if
+// the key is not set or is not an object of strings.
+func (conf *StaticConfig) GetOrStringMap(
+	key string, defaultValue map[string]string,
+) map[string]string {
+	if value, ok := conf.GetStringMap(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// seedInt returns a seeded int or int64 as an int64.
+func seedInt(seed interface{}) (int64, bool) {
+	switch v := seed.(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// seedFloat returns a seeded float64, int or int64 as a float64.
+func seedFloat(seed interface{}) (float64, bool) {
+	if v, ok := seed.(float64); ok {
+		return v, true
+	}
+	v, ok := seedInt(seed)
+	return float64(v), ok
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	zanzibar "github.com/uber/zanzibar/runtime"
+)
+
+func getterConfig() (*zanzibar.StaticConfig, func()) {
+	closer := WriteFixture(testDir, map[string][]byte{
+		"config/production.json": mustMarshal(map[string]interface{}{
+			"bool":    true,
+			"int":     7,
+			"float":   1.5,
+			"string":  "v",
+			"timeout": "1500ms",
+			"hosts":   []string{"a", "b"},
+			"headers": map[string]string{"x-uuid": "uuid"},
+			"clients.bar": map[string]interface{}{
+				"retry": map[string]interface{}{
+					"count":   3,
+					"backoff": "10ms",
+				},
+				"hosts": []string{"c"},
+			},
+		}),
+	})
+	config := zanzibar.NewStaticConfigOrDie(
+		[]string{filepath.Join(testDir, "config", "production.json")},
+		map[string]interface{}{
+			"seed.int":      int64(2),
+			"seed.duration": time.Second,
+			"seed.list":     []string{"s"},
+			"seed.object": map[string]interface{}{
+				"nested": map[string]interface{}{"string": "n"},
+			},
+		},
+	)
+	return config, closer.Close
+}
+
+func TestGetters(t *testing.T) {
+	config, closer := getterConfig()
+	defer closer()
+
+	b, ok := config.GetBoolean("bool")
+	assert.True(t, ok)
+	assert.True(t, b)
+
+	i, ok := config.GetInt("int")
+	assert.True(t, ok)
+	assert.Equal(t, int64(7), i)
+
+	i, ok = config.GetInt("seed.int")
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), i)
+
+	f, ok := config.GetFloat("float")
+	assert.True(t, ok)
+	assert.Equal(t, 1.5, f)
+
+	f, ok = config.GetFloat("int")
+	assert.True(t, ok)
+	assert.Equal(t, float64(7), f)
+
+	f, ok = config.GetFloat("seed.int")
+	assert.True(t, ok)
+	assert.Equal(t, float64(2), f)
+
+	s, ok := config.GetString("string")
+	assert.True(t, ok)
+	assert.Equal(t, "v", s)
+
+	d, ok := config.GetDuration("timeout")
+	assert.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, d)
+
+	d, ok = config.GetDuration("seed.duration")
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, d)
+
+	d, ok = config.GetDuration("int")
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Millisecond, d)
+
+	d, ok = config.GetDuration("seed.int")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Millisecond, d)
+
+	list, ok := config.GetStringList("hosts")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a", "b"}, list)
+
+	list, ok = config.GetStringList("seed.list")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"s"}, list)
+
+	m, ok := config.GetStringMap("headers")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"x-uuid": "uuid"}, m)
+
+	var headers map[string]string
+	assert.True(t, config.GetStruct("headers", &headers))
+	assert.Equal(t, map[string]string{"x-uuid": "uuid"}, headers)
+}
+
+func TestGettersMissingOrWrongType(t *testing.T) {
+	config, closer := getterConfig()
+	defer closer()
+
+	_, ok := config.GetBoolean("missing")
+	assert.False(t, ok)
+	_, ok = config.GetBoolean("int")
+	assert.False(t, ok)
+	_, ok = config.GetInt("float")
+	assert.False(t, ok)
+	_, ok = config.GetInt("string")
+	assert.False(t, ok)
+	_, ok = config.GetFloat("string")
+	assert.False(t, ok)
+	_, ok = config.GetString("int")
+	assert.False(t, ok)
+	_, ok = config.GetDuration("string")
+	assert.False(t, ok)
+	_, ok = config.GetDuration("float")
+	assert.False(t, ok)
+	_, ok = config.GetStringList("headers")
+	assert.False(t, ok)
+	_, ok = config.GetStringMap("hosts")
+	assert.False(t, ok)
+	_, ok = config.GetStringMap("clients.bar")
+	assert.False(t, ok)
+	_, ok = config.GetString("string.nested")
+	assert.False(t, ok)
+
+	var count string
+	assert.False(t, config.GetStruct("clients.bar.retry.count", &count))
+	assert.False(t, config.GetStruct("missing", &count))
+}
+
+func TestGetOrDefaults(t *testing.T) {
+	config, closer := getterConfig()
+	defer closer()
+
+	assert.True(t, config.GetOrBoolean("bool", false))
+	assert.True(t, config.GetOrBoolean("missing", true))
+	assert.Equal(t, int64(7), config.GetOrInt("int", 1))
+	assert.Equal(t, int64(1), config.GetOrInt("string", 1))
+	assert.Equal(t, 1.5, config.GetOrFloat("float", 2))
+	assert.Equal(t, 2.0, config.GetOrFloat("missing", 2))
+	assert.Equal(t, "v", config.GetOrString("string", "d"))
+	assert.Equal(t, "d", config.GetOrString("missing", "d"))
+	assert.Equal(t,
+		1500*time.Millisecond, config.GetOrDuration("timeout", time.Second),
+	)
+	assert.Equal(t, time.Second, config.GetOrDuration("missing", time.Second))
+	assert.Equal(t,
+		[]string{"a", "b"}, config.GetOrStringList("hosts", nil),
+	)
+	assert.Equal(t,
+		[]string{"d"}, config.GetOrStringList("missing", []string{"d"}),
+	)
+	assert.Equal(t,
+		map[string]string{"x-uuid": "uuid"},
+		config.GetOrStringMap("headers", nil),
+	)
+	assert.Equal(t,
+		map[string]string{"k": "v"},
+		config.GetOrStringMap("missing", map[string]string{"k": "v"}),
+	)
+}
+
+func TestGetNestedPath(t *testing.T) {
+	config, closer := getterConfig()
+	defer closer()
+
+	assert.Equal(t, int64(3), config.GetOrInt("clients.bar.retry.count", 0))
+	assert.Equal(t,
+		10*time.Millisecond,
+		config.GetOrDuration("clients.bar.retry.backoff", 0),
+	)
+	assert.Equal(t,
+		[]string{"c"}, config.GetOrStringList("clients.bar.hosts", nil),
+	)
+	assert.Equal(t, "n", config.GetOrString("seed.object.nested.string", ""))
+
+	var retry struct {
+		Count   int
+		Backoff string
+	}
+	assert.True(t, config.GetStruct("clients.bar.retry", &retry))
+	assert.Equal(t, 3, retry.Count)
+	assert.Equal(t, "10ms", retry.Backoff)
+
+	_, ok := config.GetInt("clients.bar.retry.missing")
+	assert.False(t, ok)
+	_, ok = config.GetString("seed.object.missing")
+	assert.False(t, ok)
+	_, ok = config.GetString("seed.int.nested")
+	assert.False(t, ok)
+}
+
+func TestGettersNotFoundWhenDestroyed(t *testing.T) {
+	config := zanzibar.NewStaticConfigOrDie(nil, map[string]interface{}{
+		"k": "seed",
+	})
+	config.Destroy()
+
+	_, ok := config.GetString("k")
+	assert.False(t, ok)
+	assert.Equal(t, "v", config.GetOrString("k", "v"))
+	assert.Panics(t, func() {
+		config.MustGetString("k")
+	})
+}
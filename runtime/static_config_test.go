@@ -66,6 +66,17 @@ func TestGetNamespace(t *testing.T) {
 	assert.Equal(t, config.MustGetString("a.b.c"), "v")
 }
 
+func TestContainsKey(t *testing.T) {
+	config := zanzibar.NewStaticConfigOrDie(nil, map[string]interface{}{
+		"a.seed": "v",
+	})
+	config.SetOrDie("a.b.c", "v")
+
+	assert.True(t, config.ContainsKey("a.seed"))
+	assert.True(t, config.ContainsKey("a.b.c"))
+	assert.False(t, config.ContainsKey("a.b"))
+}
+
 func TestPanicNonExistantKeys(t *testing.T) {
 	config := zanzibar.NewStaticConfigOrDie(nil, nil)
 
@@ -22,6 +22,7 @@ package zanzibar
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/pkg/errors"
@@ -35,6 +36,11 @@ type TChannelClientOption struct {
 	ServiceName       string
 	Timeout           time.Duration
 	TimeoutPerAttempt time.Duration
+	// ClientID and RecordDir record the calls of the client to the
+	// RecordDir directory as endpoint test fixtures, calls are not
+	// recorded if RecordDir is empty.
+	ClientID  string
+	RecordDir string
 }
 
 // tchannelClient implements TChannelClient and makes outgoing Thrift calls.
@@ -44,6 +50,8 @@ type tchannelClient struct {
 	serviceName       string
 	timeout           time.Duration
 	timeoutPerAttempt time.Duration
+	clientID          string
+	recorder          *FixtureRecorder
 }
 
 // NewTChannelClient returns a tchannelClient that makes calls over the given tchannel to the given thrift service.
@@ -54,10 +62,29 @@ func NewTChannelClient(ch *tchannel.Channel, opt *TChannelClientOption) TChannel
 		serviceName:       opt.ServiceName,
 		timeout:           opt.Timeout,
 		timeoutPerAttempt: opt.TimeoutPerAttempt,
+		clientID:          opt.ClientID,
+	}
+	if opt.RecordDir != "" {
+		client.recorder = NewFixtureRecorder(opt.RecordDir)
 	}
 	return client
 }
 
+// TChannelClientRecordDir returns the directory the calls of a tchannel
+// client are recorded to, the "clients.<clientID>.recordDir" config or the
+// "tchannel.clients.recordDir" config shared by all tchannel clients.
+func TChannelClientRecordDir(config *StaticConfig, clientID string) string {
+	for _, key := range []string{
+		"clients." + clientID + ".recordDir",
+		"tchannel.clients.recordDir",
+	} {
+		if config.ContainsKey(key) {
+			return config.MustGetString(key)
+		}
+	}
+	return ""
+}
+
 func (c *tchannelClient) writeArgs(call *tchannel.OutboundCall, headers map[string]string, req RWTStruct) error {
 	writer, err := call.Arg2Writer()
 	if err != nil {
@@ -158,5 +185,52 @@ func (c *tchannelClient) Call(ctx context.Context, thriftService, methodName str
 		return false, nil, errors.Wrapf(err, "could not make outbound call: %s", c.serviceName)
 	}
 
+	if c.recorder != nil {
+		c.record(methodName, reqHeaders, req, resp, isOK, respHeaders)
+	}
 	return isOK, respHeaders, nil
 }
+
+// record adds a call to the fixtures of the client, the result struct is
+// recorded as the response or the exception it holds.
+func (c *tchannelClient) record(
+	methodName string, reqHeaders map[string]string, req, resp RWTStruct,
+	success bool, respHeaders map[string]string,
+) {
+	call := &RecordedClientCall{
+		ClientID:         c.clientID,
+		ClientMethod:     methodName,
+		ClientReqHeaders: map[string]string{},
+		ClientResHeaders: map[string]string{},
+	}
+	for k, v := range reqHeaders {
+		call.ClientReqHeaders[k] = v
+	}
+	for k, v := range respHeaders {
+		call.ClientResHeaders[k] = v
+	}
+	if rawReq, err := json.Marshal(req); err == nil {
+		call.ClientRequest = recordedJSON(rawReq)
+	}
+
+	var result map[string]interface{}
+	if rawResp, err := json.Marshal(resp); err == nil {
+		_ = json.Unmarshal(rawResp, &result)
+	}
+	if success {
+		call.ClientResponse = result["success"]
+	} else {
+		for name, body := range result {
+			call.ClientException = &RecordedClientException{
+				Name: name,
+				Body: body,
+			}
+		}
+	}
+
+	if err := c.recorder.Record(call); err != nil {
+		c.ch.Logger().WithFields(tchannel.ErrField(err)).Warn(
+			"Could not record client call.",
+		)
+	}
+}
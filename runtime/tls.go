@@ -0,0 +1,280 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// certReloader serves a certificate key pair from disk and reloads it when
+// either file changes, so rotated certificates are picked up without a
+// restart.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mutex   sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// newCertReloader loads the certificate key pair or returns an error.
+func newCertReloader(certFile string, keyFile string) (*certReloader, error) {
+	reloader := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	if _, err := reloader.certificate(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// certificate returns the current certificate, reloading it if the files
+// were modified. The previous certificate is kept if reloading fails.
+func (r *certReloader) certificate() (*tls.Certificate, error) {
+	modTime, err := latestModTime(r.certFile, r.keyFile)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err != nil {
+		if r.cert != nil {
+			return r.cert, nil
+		}
+		return nil, err
+	}
+	if r.cert != nil && modTime.Equal(r.modTime) {
+		return r.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		if r.cert != nil {
+			return r.cert, nil
+		}
+		return nil, errors.Wrapf(
+			err, "Could not load certificate (%s) and key (%s)",
+			r.certFile, r.keyFile,
+		)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	return r.cert, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (r *certReloader) GetCertificate(
+	*tls.ClientHelloInfo,
+) (*tls.Certificate, error) {
+	return r.certificate()
+}
+
+// GetClientCertificate implements tls.Config.GetClientCertificate.
+func (r *certReloader) GetClientCertificate(
+	*tls.CertificateRequestInfo,
+) (*tls.Certificate, error) {
+	return r.certificate()
+}
+
+// caReloader serves a pool of CA certificates from a bundle on disk and
+// reloads it when the file changes.
+type caReloader struct {
+	caFile string
+
+	mutex   sync.Mutex
+	pool    *x509.CertPool
+	modTime time.Time
+}
+
+// newCAReloader loads the CA bundle or returns an error.
+func newCAReloader(caFile string) (*caReloader, error) {
+	reloader := &caReloader{caFile: caFile}
+	if _, err := reloader.certPool(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// certPool returns the current CA pool, reloading it if the bundle was
+// modified. The previous pool is kept if reloading fails.
+func (r *caReloader) certPool() (*x509.CertPool, error) {
+	modTime, err := latestModTime(r.caFile)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err != nil {
+		if r.pool != nil {
+			return r.pool, nil
+		}
+		return nil, err
+	}
+	if r.pool != nil && modTime.Equal(r.modTime) {
+		return r.pool, nil
+	}
+
+	pool, err := loadCertPool(r.caFile)
+	if err != nil {
+		if r.pool != nil {
+			return r.pool, nil
+		}
+		return nil, err
+	}
+	r.pool = pool
+	r.modTime = modTime
+	return r.pool, nil
+}
+
+// verifyServer verifies the certificate chain of the server of a connection
+// against the current CA pool, as crypto/tls does with a fixed RootCAs.
+func (r *caReloader) verifyServer(state tls.ConnectionState) error {
+	pool, err := r.certPool()
+	if err != nil {
+		return err
+	}
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("Server did not present a certificate")
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         pool,
+		DNSName:       state.ServerName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range state.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err = state.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	bytes, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not read CA bundle (%s)", caFile)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bytes) {
+		return nil, errors.Errorf("No certificates found in CA bundle (%s)", caFile)
+	}
+	return pool, nil
+}
+
+func latestModTime(files ...string) (time.Time, error) {
+	var latest time.Time
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return latest, errors.Wrapf(err, "Could not stat (%s)", file)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// newServerTLSConfig creates the TLS config of the gateway servers from the
+// "http.tls.*" config keys, or returns nil if TLS is not configured. When
+// "http.tls.clientCAFile" is set, clients must present a certificate signed
+// by one of its CAs.
+func newServerTLSConfig(config *StaticConfig) (*tls.Config, error) {
+	if !config.ContainsKey("http.tls.certFile") {
+		return nil, nil
+	}
+
+	certs, err := newCertReloader(
+		config.MustGetString("http.tls.certFile"),
+		config.MustGetString("http.tls.keyFile"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if !config.ContainsKey("http.tls.clientCAFile") {
+		return &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certs.GetCertificate,
+		}, nil
+	}
+
+	clientCAs, err := newCAReloader(
+		config.MustGetString("http.tls.clientCAFile"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certs.GetCertificate,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			pool, err := clientCAs.certPool()
+			if err != nil {
+				return nil, err
+			}
+			return &tls.Config{
+				MinVersion:     tls.VersionTLS12,
+				GetCertificate: certs.GetCertificate,
+				ClientAuth:     tls.RequireAndVerifyClientCert,
+				ClientCAs:      pool,
+			}, nil
+		},
+	}, nil
+}
+
+// newClientTLSConfig creates the TLS config of a http client.
+func newClientTLSConfig(opts *TLSClientOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: opts.ServerName,
+	}
+
+	if opts.CAFile != "" {
+		rootCAs, err := newCAReloader(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		// The default verification against a fixed RootCAs is replaced by
+		// one against the current CA pool, so a rotated bundle is used by
+		// the next connection.
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = rootCAs.verifyServer
+	}
+
+	if opts.CertFile != "" {
+		certs, err := newCertReloader(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.GetClientCertificate = certs.GetClientCertificate
+	}
+
+	return tlsConfig, nil
+}
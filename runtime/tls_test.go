@@ -0,0 +1,281 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zanzibar_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/zanzibar/examples/example-gateway/build/clients"
+	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints"
+	zanzibar "github.com/uber/zanzibar/runtime"
+	"github.com/uber/zanzibar/test/lib/bench_gateway"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue writes a certificate key pair signed by the CA and returns the paths.
+func (ca *testCA) issue(
+	t *testing.T, dir string, name string, uri string,
+) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth,
+		},
+	}
+	if uri != "" {
+		parsed, err := url.Parse(uri)
+		assert.NoError(t, err)
+		template.URIs = []*url.URL{parsed}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	assert.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(
+		&pem.Block{Type: "CERTIFICATE", Bytes: der},
+	), 0600))
+	assert.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(
+		&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer},
+	), 0600))
+	return certFile, keyFile
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zanzibar-tls")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		assert.NoError(t, os.RemoveAll(dir))
+	}()
+
+	ca := newTestCA(t)
+	caFile := filepath.Join(dir, "ca.pem")
+	assert.NoError(t, ioutil.WriteFile(caFile, ca.pem, 0600))
+	serverCert, serverKey := ca.issue(t, dir, "server", "")
+	clientCert, clientKey := ca.issue(t, dir, "client", "spiffe://test/client")
+
+	config := map[string]interface{}{
+		"http.tls.certFile":     serverCert,
+		"http.tls.keyFile":      serverKey,
+		"http.tls.clientCAFile": caFile,
+	}
+	for key, value := range defaultTestConfig {
+		config[key] = value
+	}
+	gateway, err := benchGateway.CreateGateway(
+		config,
+		defaultTestOptions,
+		clients.CreateClients,
+		endpoints.Register,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gateway.Close()
+
+	bgateway := gateway.(*benchGateway.BenchGateway)
+	bgateway.ActualGateway.HTTPRouter.Register(
+		"GET", "/identity",
+		zanzibar.NewRouterEndpoint(
+			bgateway.ActualGateway,
+			"identity",
+			"identity",
+			func(
+				ctx context.Context,
+				req *zanzibar.ServerHTTPRequest,
+				resp *zanzibar.ServerHTTPResponse,
+			) {
+				resp.WriteJSONBytes(200, nil, []byte(req.PeerIdentity()))
+			},
+		),
+	)
+
+	client := zanzibar.NewHTTPClientWithOptions(
+		bgateway.ActualGateway,
+		"https://"+bgateway.ActualGateway.RealHTTPAddr,
+		&zanzibar.HTTPClientOptions{
+			ClientID:     "identity",
+			MaxIdleConns: 1,
+			TLS: &zanzibar.TLSClientOptions{
+				CAFile:     caFile,
+				CertFile:   clientCert,
+				KeyFile:    clientKey,
+				ServerName: "localhost",
+			},
+		},
+	)
+	res, err := client.Client.Get(client.BaseURL + "/identity")
+	if !assert.NoError(t, err) {
+		return
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "spiffe://test/client", string(body))
+	assert.Equal(t, "server", res.TLS.PeerCertificates[0].Subject.CommonName)
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(ca.pem)
+	anonymous := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: pool, ServerName: "localhost"},
+	}}
+	_, err = anonymous.Get(client.BaseURL + "/identity")
+	assert.Error(t, err, "expected client without certificate to be rejected")
+
+	// Rotate the server certificate, new connections must use it
+	rotatedCert, rotatedKey := ca.issue(t, dir, "rotated", "")
+	for src, dst := range map[string]string{
+		rotatedCert: serverCert,
+		rotatedKey:  serverKey,
+	} {
+		assert.NoError(t, os.Rename(src, dst))
+		future := time.Now().Add(time.Minute)
+		assert.NoError(t, os.Chtimes(dst, future, future))
+	}
+
+	conn, err := tls.Dial("tcp", bgateway.ActualGateway.RealHTTPAddr, &tls.Config{
+		RootCAs:    pool,
+		ServerName: "localhost",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "rotated", conn.ConnectionState().PeerCertificates[0].Subject.CommonName)
+	_ = conn.Close()
+}
+
+func TestClientCARotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zanzibar-tls")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		assert.NoError(t, os.RemoveAll(dir))
+	}()
+
+	oldCA := newTestCA(t)
+	newCA := newTestCA(t)
+	caFile := filepath.Join(dir, "ca.pem")
+	assert.NoError(t, ioutil.WriteFile(caFile, oldCA.pem, 0600))
+	serverCert, serverKey := newCA.issue(t, dir, "server", "")
+
+	config := map[string]interface{}{
+		"http.tls.certFile": serverCert,
+		"http.tls.keyFile":  serverKey,
+	}
+	for key, value := range defaultTestConfig {
+		config[key] = value
+	}
+	gateway, err := benchGateway.CreateGateway(
+		config,
+		defaultTestOptions,
+		clients.CreateClients,
+		endpoints.Register,
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gateway.Close()
+
+	bgateway := gateway.(*benchGateway.BenchGateway)
+	client := zanzibar.NewHTTPClientWithOptions(
+		bgateway.ActualGateway,
+		"https://"+bgateway.ActualGateway.RealHTTPAddr,
+		&zanzibar.HTTPClientOptions{
+			ClientID: "rotation",
+			TLS: &zanzibar.TLSClientOptions{
+				CAFile:     caFile,
+				ServerName: "localhost",
+			},
+		},
+	)
+
+	_, err = client.Client.Get(client.BaseURL + "/health")
+	assert.Error(t, err, "expected server signed by an unknown CA to be rejected")
+
+	// Rotate the CA bundle, new connections must trust the new CA
+	assert.NoError(t, ioutil.WriteFile(caFile, newCA.pem, 0600))
+	future := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(caFile, future, future))
+
+	res, err := client.Client.Get(client.BaseURL + "/health")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, res.Body.Close())
+	assert.Equal(t, "server", res.TLS.PeerCertificates[0].Subject.CommonName)
+}
@@ -59,6 +59,7 @@ func TestBarWithHeadersTransformCall(t *testing.T) {
 				bytes,
 			)
 
+			w.Header().Set("x-string-field", "headerValue")
 			w.WriteHeader(200)
 			if _, err := w.Write([]byte(`{
 				"stringField": "stringValue",
@@ -85,6 +86,7 @@ func TestBarWithHeadersTransformCall(t *testing.T) {
 
 	assert.Equal(t, "200 OK", res.Status)
 	assert.Equal(t, 1, counter)
+	assert.Equal(t, "stringValue", res.Header.Get("some-header-field"))
 
 	respBytes, err := ioutil.ReadAll(res.Body)
 	if !assert.NoError(t, err, "got http resp error") {
@@ -92,7 +94,7 @@ func TestBarWithHeadersTransformCall(t *testing.T) {
 	}
 
 	assert.Equal(t, string(respBytes), compactStr(`{
-		"stringField":"stringValue",
+		"stringField":"headerValue",
 		"intWithRange":0,
 		"intWithoutRange":0,
 		"mapIntWithRange":{},
@@ -126,6 +128,7 @@ func TestBarWithHeadersTransformFailWithoutHeaders(t *testing.T) {
 				bytes,
 			)
 
+			w.Header().Set("x-string-field", "headerValue")
 			w.WriteHeader(200)
 			if _, err := w.Write([]byte(`{
 				"stringField": "stringValue",
@@ -180,6 +183,7 @@ func TestBarWithHeadersTransformWithDuplicateField(t *testing.T) {
 	gateway.HTTPBackends()["bar"].HandleFunc(
 		"POST", "/bar/argWithHeaders",
 		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("x-string-field", "headerValue")
 			w.WriteHeader(200)
 			if _, err := w.Write([]byte(`{
 				"stringField": "stringValue",
@@ -213,7 +217,7 @@ func TestBarWithHeadersTransformWithDuplicateField(t *testing.T) {
 	}
 
 	assert.Equal(t, string(respBytes), compactStr(`{
-		"stringField":"stringValue",
+		"stringField":"headerValue",
 		"intWithRange":0,
 		"intWithoutRange":0,
 		"mapIntWithRange":{},
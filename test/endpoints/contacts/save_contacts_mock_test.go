@@ -0,0 +1,81 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package save_contacts_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uber/zanzibar/examples/example-gateway/build/clients/contacts/mock-client"
+	"github.com/uber/zanzibar/examples/example-gateway/build/endpoints/contacts/module"
+	"github.com/uber/zanzibar/examples/example-gateway/endpoints/contacts"
+	"go.uber.org/zap"
+
+	contactsClientStructs "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/clients/contacts/contacts"
+	endpointContacts "github.com/uber/zanzibar/examples/example-gateway/build/gen-code/endpoints/contacts/contacts"
+)
+
+func TestSaveContactsWithMockClient(t *testing.T) {
+	contactsClient := clientmock.NewMockClient()
+	contactsClient.On(
+		"SaveContacts",
+		mock.Anything,
+		map[string]string(nil),
+		&contactsClientStructs.SaveContactsRequest{UserUUID: "foo"},
+	).Return(&contactsClientStructs.SaveContactsResponse{}, nil, nil)
+
+	endpoint := contacts.SaveContactsEndpoint{
+		Clients: &module.ClientDependencies{Contacts: contactsClient},
+		Logger:  zap.NewNop(),
+	}
+	response, _, err := endpoint.Handle(
+		context.Background(), nil,
+		&endpointContacts.SaveContactsRequest{UserUUID: "foo"},
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, &endpointContacts.SaveContactsResponse{}, response)
+	contactsClient.AssertExpectations(t)
+}
+
+func TestSaveContactsWithMockClientError(t *testing.T) {
+	contactsClient := clientmock.NewMockClient()
+	contactsClient.On(
+		"SaveContacts", mock.Anything, mock.Anything, mock.Anything,
+	).Return(nil, nil, errors.New("contacts unavailable"))
+
+	endpoint := contacts.SaveContactsEndpoint{
+		Clients: &module.ClientDependencies{Contacts: contactsClient},
+		Logger:  zap.NewNop(),
+	}
+	response, _, err := endpoint.Handle(
+		context.Background(), nil,
+		&endpointContacts.SaveContactsRequest{UserUUID: "foo"},
+	)
+
+	assert.Nil(t, response)
+	assert.EqualError(t, err, "contacts unavailable")
+	contactsClient.AssertNumberOfCalls(t, "SaveContacts", 1)
+}
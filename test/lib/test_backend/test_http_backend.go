@@ -21,6 +21,7 @@
 package testBackend
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
 	"sync"
@@ -86,6 +87,77 @@ func (backend *TestHTTPBackend) HandleFunc(
 	backend.router.HandlerFunc(method, path, handler)
 }
 
+// ReplayCalls serves the recorded calls of http clients, a request is
+// answered by the first call of its route that is not served yet.
+func (backend *TestHTTPBackend) ReplayCalls(
+	calls []*zanzibar.RecordedClientCall,
+) {
+	var routes []string
+	routeCalls := map[string][]*zanzibar.RecordedClientCall{}
+	for _, call := range calls {
+		route := call.HTTPMethod + " " + call.HTTPPath
+		if _, ok := routeCalls[route]; !ok {
+			routes = append(routes, route)
+		}
+		routeCalls[route] = append(routeCalls[route], call)
+	}
+
+	for _, route := range routes {
+		var mutex sync.Mutex
+		pending := routeCalls[route]
+		backend.HandleFunc(
+			pending[0].HTTPMethod, pending[0].HTTPPath,
+			func(w http.ResponseWriter, r *http.Request) {
+				mutex.Lock()
+				if len(pending) == 0 {
+					mutex.Unlock()
+					http.Error(w, "no recorded call left for "+
+						r.Method+" "+r.URL.Path, http.StatusInternalServerError)
+					return
+				}
+				call := pending[0]
+				pending = pending[1:]
+				mutex.Unlock()
+
+				replayHTTPCall(w, call)
+			},
+		)
+	}
+}
+
+// ReplayFixture serves the recorded calls of a fixture file.
+func (backend *TestHTTPBackend) ReplayFixture(path string) error {
+	calls, err := zanzibar.ReadRecordedClientCalls(path)
+	if err != nil {
+		return err
+	}
+	backend.ReplayCalls(calls)
+	return nil
+}
+
+func replayHTTPCall(w http.ResponseWriter, call *zanzibar.RecordedClientCall) {
+	for k, v := range call.ClientResHeaders {
+		w.Header().Set(k, v)
+	}
+	var body []byte
+	if call.ClientResponse != nil {
+		var err error
+		body, err = json.Marshal(call.ClientResponse)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	statusCode := call.ClientStatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
+
 // Close ...
 func (backend *TestHTTPBackend) Close() {
 	backend.Server.Close()
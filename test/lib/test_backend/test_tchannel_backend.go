@@ -21,10 +21,15 @@
 package testBackend
 
 import (
+	"context"
+	"encoding/json"
 	"net"
 	"strconv"
+	"sync"
 
+	"github.com/pkg/errors"
 	"github.com/uber/tchannel-go"
+	"go.uber.org/thriftrw/wire"
 	"go.uber.org/zap"
 
 	"github.com/uber/zanzibar/runtime"
@@ -101,6 +106,61 @@ func (backend *TestTChannelBackend) Register(service string, method string, hand
 	backend.Router.Register(service, method, handler)
 }
 
+// ReplayCalls serves the recorded calls of a tchannel client method in
+// order, newResult returns an empty result struct of the method.
+func (backend *TestTChannelBackend) ReplayCalls(
+	service string, method string,
+	calls []*zanzibar.RecordedClientCall,
+	newResult func() zanzibar.RWTStruct,
+) {
+	backend.Register(service, method, &replayHandler{
+		name:      service + "::" + method,
+		pending:   calls,
+		newResult: newResult,
+	})
+}
+
+type replayHandler struct {
+	name      string
+	newResult func() zanzibar.RWTStruct
+
+	mutex   sync.Mutex
+	pending []*zanzibar.RecordedClientCall
+}
+
+func (h *replayHandler) Handle(
+	ctx context.Context, reqHeaders map[string]string, wireValue *wire.Value,
+) (bool, zanzibar.RWTStruct, map[string]string, error) {
+	h.mutex.Lock()
+	if len(h.pending) == 0 {
+		h.mutex.Unlock()
+		return false, nil, nil, errors.Errorf(
+			"no recorded call left for %s", h.name,
+		)
+	}
+	call := h.pending[0]
+	h.pending = h.pending[1:]
+	h.mutex.Unlock()
+
+	result := map[string]interface{}{}
+	if call.ClientException != nil {
+		result[call.ClientException.Name] = call.ClientException.Body
+	} else if call.ClientResponse != nil {
+		result["success"] = call.ClientResponse
+	}
+	rawResult, err := json.Marshal(result)
+	if err != nil {
+		return false, nil, nil, errors.Wrapf(err,
+			"could not serialize recorded result of %s", h.name)
+	}
+	res := h.newResult()
+	if err := json.Unmarshal(rawResult, res); err != nil {
+		return false, nil, nil, errors.Wrapf(err,
+			"could not parse recorded result of %s", h.name)
+	}
+	return call.ClientException == nil, res, call.ClientResHeaders, nil
+}
+
 // Close closes the underlying channel
 func (backend *TestTChannelBackend) Close() {
 	backend.Channel.Close()